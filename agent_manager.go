@@ -0,0 +1,954 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ==================== AGENT MODELS ====================
+
+// AgentCapability names one skill an agent offers.
+type AgentCapability string
+
+const (
+	CapabilityTextGeneration    AgentCapability = "text_generation"
+	CapabilityCodeGeneration    AgentCapability = "code_generation"
+	CapabilitySummarization     AgentCapability = "summarization"
+	CapabilityTranslation       AgentCapability = "translation"
+	CapabilityCoordination      AgentCapability = "coordination"
+	CapabilityCodeAnalysis      AgentCapability = "code_analysis"
+	CapabilityDebugging         AgentCapability = "debugging"
+	CapabilityVisualization     AgentCapability = "visualization"
+	CapabilityQualityAssessment AgentCapability = "quality_assessment"
+	CapabilityFactChecking      AgentCapability = "fact_checking"
+)
+
+// AllCapabilities returns every known capability.
+func AllCapabilities() []AgentCapability {
+	return []AgentCapability{
+		CapabilityTextGeneration,
+		CapabilityCodeGeneration,
+		CapabilitySummarization,
+		CapabilityTranslation,
+		CapabilityCoordination,
+		CapabilityCodeAnalysis,
+		CapabilityDebugging,
+		CapabilityVisualization,
+		CapabilityQualityAssessment,
+		CapabilityFactChecking,
+	}
+}
+
+// ParseCapability validates a capability string from config.
+func ParseCapability(s string) (AgentCapability, error) {
+	for _, c := range AllCapabilities() {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown capability %q", s)
+}
+
+// AgentConfig describes one agent and which provider backs it.
+type AgentConfig struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Role         string            `json:"role,omitempty"`
+	Personality  string            `json:"personality,omitempty"`
+	SystemPrompt string            `json:"system_prompt,omitempty"`
+	ProviderID   string            `json:"provider_id"`
+	Model        string            `json:"model"`
+	Capabilities []AgentCapability `json:"capabilities"`
+}
+
+// AgentPerformance tracks rolling quality metrics for one agent.
+type AgentPerformance struct {
+	SuccessRate         float64       `json:"success_rate"`
+	ErrorRate           float64       `json:"error_rate"`
+	AverageResponseTime time.Duration `json:"average_response_time"`
+	QualityScore        float64       `json:"quality_score"`
+	TotalTasks          int           `json:"total_tasks"`
+}
+
+// AgentStatus is one agent's live state.
+type AgentStatus struct {
+	AgentID     string           `json:"agent_id"`
+	Status      string           `json:"status"` // "idle", "busy", "offline"
+	TasksDone   int              `json:"tasks_done"`
+	Performance AgentPerformance `json:"performance"`
+	LastSeen    time.Time        `json:"last_seen"`
+}
+
+// AgentTask is one unit of work routed to an agent.
+type AgentTask struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Priority    int                    `json:"priority"`
+	Payload     string                 `json:"payload"`
+	Timeout     time.Duration          `json:"timeout,omitempty"`
+	MaxRetries  int                    `json:"max_retries,omitempty"`
+	Status      string                 `json:"status"` // "pending", "running", "done", "failed"
+	Result      interface{}            `json:"result,omitempty"`
+	AssignedTo  string                 `json:"assigned_to,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+}
+
+// AgentEvent is emitted on notable manager activity.
+type AgentEvent struct {
+	Type      string                 `json:"type"`
+	AgentID   string                 `json:"agent_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Provider is a backing AI service agents can be wired to by ID.
+type Provider interface {
+	ID() string
+	Name() string
+}
+
+// ==================== COORDINATION ====================
+
+// CoordinationStrategy picks which agent receives a task.
+type CoordinationStrategy interface {
+	SelectAgent(agents []AgentStatus, task AgentTask) (string, error)
+}
+
+// RoundRobinStrategy cycles through agents in order.
+type RoundRobinStrategy struct {
+	next int
+}
+
+// SelectAgent returns the next agent in rotation.
+func (rr *RoundRobinStrategy) SelectAgent(agents []AgentStatus, task AgentTask) (string, error) {
+	if len(agents) == 0 {
+		return "", fmt.Errorf("no agents available")
+	}
+	agent := agents[rr.next%len(agents)]
+	rr.next++
+	return agent.AgentID, nil
+}
+
+// capabilityForTaskType maps task types to the capability required to
+// handle them.
+func capabilityForTaskType(taskType string) (AgentCapability, bool) {
+	switch taskType {
+	case "code", "code_generation":
+		return CapabilityCodeGeneration, true
+	case "code_review", "code_analysis":
+		return CapabilityCodeAnalysis, true
+	case "debug", "debugging":
+		return CapabilityDebugging, true
+	case "coordinate", "coordination":
+		return CapabilityCoordination, true
+	case "summarize", "summarization":
+		return CapabilitySummarization, true
+	case "translate", "translation":
+		return CapabilityTranslation, true
+	case "visualize", "visualization":
+		return CapabilityVisualization, true
+	case "review", "quality_assessment":
+		return CapabilityQualityAssessment, true
+	case "fact_check", "fact_checking":
+		return CapabilityFactChecking, true
+	case "text", "text_generation":
+		return CapabilityTextGeneration, true
+	}
+	return "", false
+}
+
+// CapabilityStrategy routes tasks to the least-loaded agent whose
+// capabilities cover the task type, falling back to round-robin when no
+// agent qualifies.
+type CapabilityStrategy struct {
+	manager  *AgentManager
+	fallback RoundRobinStrategy
+}
+
+// NewCapabilityStrategy routes against the given manager's configs.
+func NewCapabilityStrategy(manager *AgentManager) *CapabilityStrategy {
+	return &CapabilityStrategy{manager: manager}
+}
+
+// SelectAgent filters to capable agents and picks the least loaded.
+func (cs *CapabilityStrategy) SelectAgent(agents []AgentStatus, task AgentTask) (string, error) {
+	required, ok := capabilityForTaskType(task.Type)
+	if !ok {
+		return cs.fallback.SelectAgent(agents, task)
+	}
+
+	var candidates []AgentStatus
+	for _, agent := range agents {
+		cfg, found := cs.manager.GetAgentConfig(agent.AgentID)
+		if !found {
+			continue
+		}
+		for _, cap := range cfg.Capabilities {
+			if cap == required {
+				candidates = append(candidates, agent)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return cs.fallback.SelectAgent(agents, task)
+	}
+
+	best := candidates[0]
+	for _, agent := range candidates[1:] {
+		if agent.TasksDone < best.TasksDone ||
+			(agent.TasksDone == best.TasksDone && bestScore(agent.Performance) > bestScore(best.Performance)) {
+			best = agent
+		}
+	}
+	return best.AgentID, nil
+}
+
+// TaskDistributor routes tasks to agents via its strategy.
+type TaskDistributor struct {
+	strategy CoordinationStrategy
+}
+
+// NewTaskDistributor defaults to round-robin.
+func NewTaskDistributor() *TaskDistributor {
+	return &TaskDistributor{strategy: &RoundRobinStrategy{}}
+}
+
+// SetStrategy swaps the routing strategy.
+func (td *TaskDistributor) SetStrategy(strategy CoordinationStrategy) {
+	td.strategy = strategy
+}
+
+// Distribute picks an agent for the task.
+func (td *TaskDistributor) Distribute(agents []AgentStatus, task AgentTask) (string, error) {
+	return td.strategy.SelectAgent(agents, task)
+}
+
+// ==================== AGENT MANAGER ====================
+
+// TaskHandler executes one task and returns its result.
+type TaskHandler func(ctx context.Context, task AgentTask) (interface{}, error)
+
+// taskQueue is a max-heap of pending tasks ordered by priority, with
+// earlier-created tasks winning ties.
+type taskQueue []AgentTask
+
+func (tq taskQueue) Len() int { return len(tq) }
+
+func (tq taskQueue) Less(i, j int) bool {
+	if tq[i].Priority != tq[j].Priority {
+		return tq[i].Priority > tq[j].Priority
+	}
+	return tq[i].CreatedAt.Before(tq[j].CreatedAt)
+}
+
+func (tq taskQueue) Swap(i, j int) { tq[i], tq[j] = tq[j], tq[i] }
+
+func (tq *taskQueue) Push(x interface{}) {
+	*tq = append(*tq, x.(AgentTask))
+}
+
+func (tq *taskQueue) Pop() interface{} {
+	old := *tq
+	n := len(old)
+	task := old[n-1]
+	*tq = old[:n-1]
+	return task
+}
+
+// AgentManager owns agent configs, live status, providers, and the task
+// dispatch path.
+type AgentManager struct {
+	mu          sync.RWMutex
+	configs     map[string]AgentConfig
+	statuses    map[string]*AgentStatus
+	providers   map[string]Provider
+	queue       taskQueue
+	queueCond   *sync.Cond
+	taskRecords map[string]*AgentTask
+	cancels     map[string]context.CancelFunc
+	distributor *TaskDistributor
+	handler     TaskHandler
+	workers     int
+	wg          sync.WaitGroup
+	runtimePath string
+
+	eventHandler func(AgentEvent)
+}
+
+// NewAgentManager returns a manager with no agents registered.
+func NewAgentManager() *AgentManager {
+	am := &AgentManager{
+		configs:     make(map[string]AgentConfig),
+		statuses:    make(map[string]*AgentStatus),
+		providers:   make(map[string]Provider),
+		taskRecords: make(map[string]*AgentTask),
+		cancels:     make(map[string]context.CancelFunc),
+		distributor: NewTaskDistributor(),
+		workers:     4,
+	}
+	am.queueCond = sync.NewCond(&am.mu)
+	return am
+}
+
+// SetEventHandler installs a callback for manager events.
+func (am *AgentManager) SetEventHandler(handler func(AgentEvent)) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.eventHandler = handler
+}
+
+// emitEvent invokes the handler if one is installed.
+func (am *AgentManager) emitEvent(event AgentEvent) {
+	am.mu.RLock()
+	handler := am.eventHandler
+	am.mu.RUnlock()
+	if handler != nil {
+		event.Timestamp = time.Now()
+		handler(event)
+	}
+}
+
+// ==================== PROVIDERS ====================
+
+// AddProvider registers a provider agents can reference by ID.
+func (am *AgentManager) AddProvider(p Provider) error {
+	am.mu.Lock()
+	if p == nil || p.ID() == "" {
+		am.mu.Unlock()
+		return fmt.Errorf("provider must have an ID")
+	}
+	if _, exists := am.providers[p.ID()]; exists {
+		am.mu.Unlock()
+		return fmt.Errorf("provider %s already registered", p.ID())
+	}
+	am.providers[p.ID()] = p
+	am.mu.Unlock()
+
+	am.emitEvent(AgentEvent{
+		Type: "provider_added",
+		Data: map[string]interface{}{"provider": p.ID(), "name": p.Name()},
+	})
+	return nil
+}
+
+// GetProvider looks up a registered provider.
+func (am *AgentManager) GetProvider(id string) (Provider, bool) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	p, ok := am.providers[id]
+	return p, ok
+}
+
+// ListProviders returns every registered provider.
+func (am *AgentManager) ListProviders() []Provider {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	out := make([]Provider, 0, len(am.providers))
+	for _, p := range am.providers {
+		out = append(out, p)
+	}
+	return out
+}
+
+// ResolveProvider returns the provider backing an agent's config.
+func (am *AgentManager) ResolveProvider(agentID string) (Provider, error) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	cfg, ok := am.configs[agentID]
+	if !ok {
+		return nil, fmt.Errorf("agent %s not found", agentID)
+	}
+	p, ok := am.providers[cfg.ProviderID]
+	if !ok {
+		return nil, fmt.Errorf("agent %s references unknown provider %s", agentID, cfg.ProviderID)
+	}
+	return p, nil
+}
+
+// ==================== AGENTS & TASKS ====================
+
+// defaultAgentConfigs is the built-in agent roster.
+func defaultAgentConfigs() []AgentConfig {
+	return []AgentConfig{
+		{
+			ID:           "coordinator",
+			Name:         "Coordinator",
+			Type:         "coordinator",
+			Capabilities: []AgentCapability{CapabilityCoordination, CapabilitySummarization},
+		},
+		{
+			ID:           "coder",
+			Name:         "Coder",
+			Type:         "specialist",
+			Capabilities: []AgentCapability{CapabilityCodeGeneration, CapabilityCodeAnalysis, CapabilityDebugging},
+		},
+		{
+			ID:           "analyst",
+			Name:         "Analyst",
+			Type:         "specialist",
+			Capabilities: []AgentCapability{CapabilityVisualization, CapabilityQualityAssessment},
+		},
+		{
+			ID:           "researcher",
+			Name:         "Researcher",
+			Type:         "specialist",
+			Capabilities: []AgentCapability{CapabilityTextGeneration, CapabilityFactChecking},
+		},
+	}
+}
+
+// initializeAgents registers the default roster and, when a runtime path
+// is configured, restores saved status and performance on top of it.
+func (am *AgentManager) initializeAgents() error {
+	for _, cfg := range defaultAgentConfigs() {
+		if err := am.AddAgent(cfg); err != nil {
+			return err
+		}
+	}
+	if am.runtimePath != "" {
+		if err := am.LoadRuntime(am.runtimePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRuntimePath configures where runtime state is persisted.
+func (am *AgentManager) SetRuntimePath(path string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.runtimePath = path
+}
+
+// agentRuntimeState is the on-disk shape of saved agent runtime data.
+type agentRuntimeState struct {
+	SavedAt time.Time              `json:"saved_at"`
+	Agents  map[string]AgentStatus `json:"agents"`
+}
+
+// SaveRuntime writes per-agent status and performance to path.
+func (am *AgentManager) SaveRuntime(path string) error {
+	am.mu.RLock()
+	state := agentRuntimeState{
+		SavedAt: time.Now(),
+		Agents:  make(map[string]AgentStatus, len(am.statuses)),
+	}
+	for id, status := range am.statuses {
+		state.Agents[id] = *status
+	}
+	am.mu.RUnlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal runtime state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write runtime state: %w", err)
+	}
+	return nil
+}
+
+// LoadRuntime restores saved status and performance for agents that still
+// have a config; entries for removed agents are dropped.
+func (am *AgentManager) LoadRuntime(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var state agentRuntimeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse runtime state: %w", err)
+	}
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	for id, saved := range state.Agents {
+		if _, ok := am.configs[id]; !ok {
+			continue
+		}
+		status, ok := am.statuses[id]
+		if !ok {
+			continue
+		}
+		status.TasksDone = saved.TasksDone
+		status.Performance = saved.Performance
+		if saved.Status == "offline" {
+			status.Status = "offline"
+		}
+	}
+	return nil
+}
+
+// AddAgent registers an agent config and initializes its status.
+func (am *AgentManager) AddAgent(cfg AgentConfig) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	if cfg.ID == "" {
+		return fmt.Errorf("agent config must have an ID")
+	}
+	if _, exists := am.configs[cfg.ID]; exists {
+		return fmt.Errorf("agent %s already registered", cfg.ID)
+	}
+	for _, cap := range cfg.Capabilities {
+		if _, err := ParseCapability(string(cap)); err != nil {
+			return fmt.Errorf("agent %s: %w", cfg.ID, err)
+		}
+	}
+	am.configs[cfg.ID] = cfg
+	am.statuses[cfg.ID] = &AgentStatus{
+		AgentID:  cfg.ID,
+		Status:   "idle",
+		LastSeen: time.Now(),
+	}
+	return nil
+}
+
+// GetAgentConfig returns the registered config for an agent.
+func (am *AgentManager) GetAgentConfig(agentID string) (AgentConfig, bool) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	cfg, ok := am.configs[agentID]
+	return cfg, ok
+}
+
+// SetStrategy swaps the routing strategy on the manager's distributor.
+func (am *AgentManager) SetStrategy(strategy CoordinationStrategy) {
+	am.distributor.SetStrategy(strategy)
+}
+
+// GetActiveAgents lists agents currently available for routing.
+func (am *AgentManager) GetActiveAgents() []AgentStatus {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	var out []AgentStatus
+	for _, status := range am.statuses {
+		if status.Status != "offline" {
+			out = append(out, *status)
+		}
+	}
+	return out
+}
+
+// SetTaskHandler installs the function workers run for each task.
+func (am *AgentManager) SetTaskHandler(handler TaskHandler) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.handler = handler
+}
+
+// Start launches the worker pool. Workers drain the task queue until the
+// context is cancelled.
+func (am *AgentManager) Start(ctx context.Context) {
+	for i := 0; i < am.workers; i++ {
+		am.wg.Add(1)
+		go am.worker(ctx)
+	}
+	// Wake any waiting workers when the context ends so they can exit.
+	go func() {
+		<-ctx.Done()
+		am.queueCond.Broadcast()
+	}()
+}
+
+// Wait blocks until all workers have exited.
+func (am *AgentManager) Wait() {
+	am.wg.Wait()
+}
+
+// worker drains the task queue, running the handler for each task.
+func (am *AgentManager) worker(ctx context.Context) {
+	defer am.wg.Done()
+	for {
+		task, ok := am.nextTask(ctx)
+		if !ok {
+			return
+		}
+		am.runTask(ctx, task)
+	}
+}
+
+// nextTask blocks until a task is available or the context ends. It pops
+// the highest-priority pending task.
+func (am *AgentManager) nextTask(ctx context.Context) (AgentTask, bool) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	for len(am.queue) == 0 {
+		if ctx.Err() != nil {
+			return AgentTask{}, false
+		}
+		am.queueCond.Wait()
+	}
+	if ctx.Err() != nil {
+		return AgentTask{}, false
+	}
+	task := heap.Pop(&am.queue).(AgentTask)
+	return task, true
+}
+
+// runTask executes one task under its timeout and records the outcome.
+func (am *AgentManager) runTask(ctx context.Context, task AgentTask) {
+	am.mu.RLock()
+	handler := am.handler
+	am.mu.RUnlock()
+
+	var taskCtx context.Context
+	var cancel context.CancelFunc
+	if task.Timeout > 0 {
+		taskCtx, cancel = context.WithTimeout(ctx, task.Timeout)
+	} else {
+		taskCtx, cancel = context.WithCancel(ctx)
+	}
+	am.mu.Lock()
+	am.cancels[task.ID] = cancel
+	am.mu.Unlock()
+	defer func() {
+		am.mu.Lock()
+		delete(am.cancels, task.ID)
+		am.mu.Unlock()
+		cancel()
+	}()
+
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]interface{})
+	}
+	attempt, _ := task.Metadata["attempts"].(int)
+	attempt++
+	task.Metadata["attempts"] = attempt
+	am.mu.Lock()
+	if record, found := am.taskRecords[task.ID]; found {
+		record.Metadata = task.Metadata
+	}
+	am.mu.Unlock()
+
+	am.setTaskStatus(task.ID, "running", nil)
+	am.setAgentStatus(task.AssignedTo, "busy")
+
+	started := time.Now()
+	var result interface{}
+	var err error
+	if handler != nil {
+		result, err = handler(taskCtx, task)
+	} else {
+		err = fmt.Errorf("no task handler registered")
+	}
+	ok := err == nil && taskCtx.Err() == nil
+	am.recordTaskResult(task.AssignedTo, ok, time.Since(started))
+
+	status := "done"
+	switch {
+	case taskCtx.Err() == context.DeadlineExceeded:
+		status = "timed_out"
+		result = taskCtx.Err().Error()
+		am.emitEvent(AgentEvent{
+			Type:    "task_timeout",
+			AgentID: task.AssignedTo,
+			Data:    map[string]interface{}{"task": task.ID, "timeout": task.Timeout.String()},
+		})
+	case taskCtx.Err() == context.Canceled && ctx.Err() == nil:
+		status = "cancelled"
+		result = taskCtx.Err().Error()
+		am.emitEvent(AgentEvent{
+			Type:    "task_cancelled",
+			AgentID: task.AssignedTo,
+			Data:    map[string]interface{}{"task": task.ID},
+		})
+	case err != nil:
+		if attempt <= task.MaxRetries {
+			am.setAgentStatus(task.AssignedTo, "idle")
+			am.retryTask(ctx, task, attempt, err)
+			return
+		}
+		status = "failed"
+		result = err.Error()
+	}
+	am.setTaskStatus(task.ID, status, result)
+	am.setAgentStatus(task.AssignedTo, "idle")
+
+	am.mu.Lock()
+	if agent, ok := am.statuses[task.AssignedTo]; ok {
+		agent.TasksDone++
+	}
+	am.mu.Unlock()
+}
+
+// retryTask requeues a failed task after exponential backoff.
+func (am *AgentManager) retryTask(ctx context.Context, task AgentTask, attempt int, cause error) {
+	backoff := 100 * time.Millisecond << uint(attempt-1)
+	am.setTaskStatus(task.ID, "retrying", cause.Error())
+	am.emitEvent(AgentEvent{
+		Type:    "task_retry",
+		AgentID: task.AssignedTo,
+		Data: map[string]interface{}{
+			"task":    task.ID,
+			"attempt": attempt,
+			"backoff": backoff.String(),
+			"error":   cause.Error(),
+		},
+	})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			am.setTaskStatus(task.ID, "cancelled", ctx.Err().Error())
+			return
+		case <-time.After(backoff):
+		}
+		am.mu.Lock()
+		heap.Push(&am.queue, task)
+		am.mu.Unlock()
+		am.queueCond.Signal()
+	}()
+}
+
+// setTaskStatus updates the stored record for a task.
+func (am *AgentManager) setTaskStatus(taskID, status string, result interface{}) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	record, ok := am.taskRecords[taskID]
+	if !ok {
+		return
+	}
+	record.Status = status
+	if result != nil {
+		record.Result = result
+	}
+	if status != "pending" && status != "running" && status != "retrying" {
+		now := time.Now()
+		record.CompletedAt = &now
+	}
+}
+
+// setAgentStatus updates one agent's live status string.
+func (am *AgentManager) setAgentStatus(agentID, status string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if agent, ok := am.statuses[agentID]; ok {
+		agent.Status = status
+		agent.LastSeen = time.Now()
+	}
+}
+
+// AssignTask routes a task to an agent, enqueues it, and returns the
+// tracked task record.
+func (am *AgentManager) AssignTask(task AgentTask) (*AgentTask, error) {
+	agents := am.GetActiveAgents()
+	agentID, err := am.distributor.Distribute(agents, task)
+	if err != nil {
+		return nil, err
+	}
+
+	task.AssignedTo = agentID
+	task.Status = "pending"
+	if task.ID == "" {
+		task.ID = newConvID()
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+
+	record := task
+	am.mu.Lock()
+	am.taskRecords[task.ID] = &record
+	heap.Push(&am.queue, task)
+	am.mu.Unlock()
+	am.queueCond.Signal()
+	return &record, nil
+}
+
+// CancelTask cancels an in-flight task by ID.
+func (am *AgentManager) CancelTask(taskID string) error {
+	am.mu.Lock()
+	cancel, ok := am.cancels[taskID]
+	am.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("task %s is not running", taskID)
+	}
+	cancel()
+	return nil
+}
+
+// GetTask returns a snapshot of a tracked task.
+func (am *AgentManager) GetTask(taskID string) (AgentTask, bool) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	record, ok := am.taskRecords[taskID]
+	if !ok {
+		return AgentTask{}, false
+	}
+	return *record, true
+}
+
+// recordTaskResult folds one finished task into the agent's rolling
+// performance metrics.
+func (am *AgentManager) recordTaskResult(agentID string, ok bool, dur time.Duration) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	status, found := am.statuses[agentID]
+	if !found {
+		return
+	}
+	perf := &status.Performance
+	perf.TotalTasks++
+	n := float64(perf.TotalTasks)
+
+	outcome := 0.0
+	if ok {
+		outcome = 1.0
+	}
+	perf.SuccessRate += (outcome - perf.SuccessRate) / n
+	perf.ErrorRate = 1.0 - perf.SuccessRate
+	perf.AverageResponseTime += (dur - perf.AverageResponseTime) / time.Duration(perf.TotalTasks)
+
+	// Quality blends reliability with responsiveness: fast agents at one
+	// second or under score full speed credit, slower ones decay toward 0.
+	speed := 1.0 / (1.0 + perf.AverageResponseTime.Seconds())
+	perf.QualityScore = perf.SuccessRate*0.7 + speed*0.3
+}
+
+// bestScore ranks an agent for tie-breaking during routing.
+func bestScore(perf AgentPerformance) float64 {
+	if perf.TotalTasks == 0 {
+		// Unproven agents rank neutrally so they still get work.
+		return 0.5
+	}
+	return perf.QualityScore
+}
+
+// ==================== HEALTH CHECKS ====================
+
+// HealthFunc probes one agent, returning an error when it is unhealthy.
+type HealthFunc func(ctx context.Context, agentID string) error
+
+// HealthChecker periodically probes agents and takes persistently
+// unhealthy ones out of the routing pool.
+type HealthChecker struct {
+	manager   *AgentManager
+	check     HealthFunc
+	interval  time.Duration
+	threshold int
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewHealthChecker probes with the given func at the given interval.
+// Agents go offline after three consecutive failures.
+func NewHealthChecker(manager *AgentManager, check HealthFunc, interval time.Duration) *HealthChecker {
+	return &HealthChecker{
+		manager:   manager,
+		check:     check,
+		interval:  interval,
+		threshold: 3,
+		failures:  make(map[string]int),
+	}
+}
+
+// SetThreshold overrides how many consecutive failures disable an agent.
+func (hc *HealthChecker) SetThreshold(n int) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if n > 0 {
+		hc.threshold = n
+	}
+}
+
+// Start runs checks on the interval until the context ends.
+func (hc *HealthChecker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(hc.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				hc.CheckOnce(ctx)
+			}
+		}
+	}()
+}
+
+// CheckOnce probes every registered agent a single time.
+func (hc *HealthChecker) CheckOnce(ctx context.Context) {
+	hc.manager.mu.RLock()
+	ids := make([]string, 0, len(hc.manager.statuses))
+	for id := range hc.manager.statuses {
+		ids = append(ids, id)
+	}
+	hc.manager.mu.RUnlock()
+
+	for _, id := range ids {
+		hc.probe(ctx, id)
+	}
+}
+
+// probe runs one health check and applies the disable/recover rules.
+func (hc *HealthChecker) probe(ctx context.Context, agentID string) {
+	err := hc.check(ctx, agentID)
+
+	hc.mu.Lock()
+	if err != nil {
+		hc.failures[agentID]++
+	} else {
+		hc.failures[agentID] = 0
+	}
+	count := hc.failures[agentID]
+	threshold := hc.threshold
+	hc.mu.Unlock()
+
+	am := hc.manager
+	am.mu.Lock()
+	status, ok := am.statuses[agentID]
+	if !ok {
+		am.mu.Unlock()
+		return
+	}
+	wasOffline := status.Status == "offline"
+	var eventType string
+	switch {
+	case err != nil && count >= threshold && !wasOffline:
+		status.Status = "offline"
+		eventType = "agent_unhealthy"
+	case err == nil && wasOffline:
+		status.Status = "idle"
+		status.LastSeen = time.Now()
+		eventType = "agent_recovered"
+	case err == nil:
+		status.LastSeen = time.Now()
+	}
+	am.mu.Unlock()
+
+	if eventType != "" {
+		data := map[string]interface{}{"failures": count}
+		if err != nil {
+			data["error"] = err.Error()
+		}
+		am.emitEvent(AgentEvent{Type: eventType, AgentID: agentID, Data: data})
+	}
+}
+
+// GetPerformanceMetrics returns the recorded metrics for an agent.
+func (am *AgentManager) GetPerformanceMetrics(agentID string) (AgentPerformance, error) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	status, ok := am.statuses[agentID]
+	if !ok {
+		return AgentPerformance{}, fmt.Errorf("agent %s not found", agentID)
+	}
+	return status.Performance, nil
+}
@@ -0,0 +1,610 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	id   string
+	name string
+}
+
+func (p fakeProvider) ID() string   { return p.id }
+func (p fakeProvider) Name() string { return p.name }
+
+func TestAddProviderAndLookup(t *testing.T) {
+	am := NewAgentManager()
+
+	var events []AgentEvent
+	am.SetEventHandler(func(e AgentEvent) { events = append(events, e) })
+
+	if err := am.AddProvider(fakeProvider{id: "openrouter", name: "OpenRouter"}); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+
+	p, ok := am.GetProvider("openrouter")
+	if !ok {
+		t.Fatal("expected provider to resolve")
+	}
+	if p.Name() != "OpenRouter" {
+		t.Errorf("Name = %q, want OpenRouter", p.Name())
+	}
+	if len(events) != 1 || events[0].Type != "provider_added" {
+		t.Errorf("expected one provider_added event, got %+v", events)
+	}
+}
+
+func TestAddProviderDuplicate(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddProvider(fakeProvider{id: "ollama", name: "Ollama"}); err != nil {
+		t.Fatalf("first AddProvider: %v", err)
+	}
+	if err := am.AddProvider(fakeProvider{id: "ollama", name: "Ollama 2"}); err == nil {
+		t.Fatal("expected duplicate provider ID to be rejected")
+	}
+	if err := am.AddProvider(fakeProvider{}); err == nil {
+		t.Fatal("expected empty provider ID to be rejected")
+	}
+}
+
+func TestListProviders(t *testing.T) {
+	am := NewAgentManager()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := am.AddProvider(fakeProvider{id: id, name: id}); err != nil {
+			t.Fatalf("AddProvider %s: %v", id, err)
+		}
+	}
+	if got := len(am.ListProviders()); got != 3 {
+		t.Errorf("ListProviders returned %d providers, want 3", got)
+	}
+}
+
+func TestAgentResolvesProvider(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddProvider(fakeProvider{id: "openrouter", name: "OpenRouter"}); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	if err := am.AddAgent(AgentConfig{
+		ID:         "coder",
+		Name:       "Coder",
+		ProviderID: "openrouter",
+	}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+
+	p, err := am.ResolveProvider("coder")
+	if err != nil {
+		t.Fatalf("ResolveProvider: %v", err)
+	}
+	if p.ID() != "openrouter" {
+		t.Errorf("resolved provider %q, want openrouter", p.ID())
+	}
+
+	if err := am.AddAgent(AgentConfig{ID: "orphan", ProviderID: "missing"}); err != nil {
+		t.Fatalf("AddAgent orphan: %v", err)
+	}
+	if _, err := am.ResolveProvider("orphan"); err == nil {
+		t.Fatal("expected unknown provider reference to error")
+	}
+}
+
+func TestDefaultConfigsLoad(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.initializeAgents(); err != nil {
+		t.Fatalf("initializeAgents: %v", err)
+	}
+	if got := len(am.GetActiveAgents()); got != len(defaultAgentConfigs()) {
+		t.Errorf("got %d active agents, want %d", got, len(defaultAgentConfigs()))
+	}
+}
+
+func TestAddAgentRejectsUnknownCapability(t *testing.T) {
+	am := NewAgentManager()
+	err := am.AddAgent(AgentConfig{
+		ID:           "bogus",
+		Capabilities: []AgentCapability{"time_travel"},
+	})
+	if err == nil {
+		t.Fatal("expected unknown capability to be rejected")
+	}
+}
+
+func TestParseCapability(t *testing.T) {
+	if _, err := ParseCapability("debugging"); err != nil {
+		t.Errorf("ParseCapability(debugging): %v", err)
+	}
+	if _, err := ParseCapability("nope"); err == nil {
+		t.Error("expected error for unknown capability")
+	}
+	if got := len(AllCapabilities()); got < 10 {
+		t.Errorf("AllCapabilities returned %d entries, want at least 10", got)
+	}
+}
+
+func TestCapabilityStrategyRoutesToCapableAgent(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.initializeAgents(); err != nil {
+		t.Fatalf("initializeAgents: %v", err)
+	}
+	am.SetStrategy(NewCapabilityStrategy(am))
+
+	// Code tasks must only land on the code-capable agent.
+	for i := 0; i < 5; i++ {
+		assigned, err := am.AssignTask(AgentTask{Type: "code"})
+		if err != nil {
+			t.Fatalf("AssignTask: %v", err)
+		}
+		if assigned.AssignedTo != "coder" {
+			t.Errorf("code task assigned to %q, want coder", assigned.AssignedTo)
+		}
+	}
+}
+
+func TestCapabilityStrategyFallsBack(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "generalist"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	am.SetStrategy(NewCapabilityStrategy(am))
+
+	// No agent declares debugging; round-robin fallback should still route.
+	assigned, err := am.AssignTask(AgentTask{Type: "debug"})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	if assigned.AssignedTo != "generalist" {
+		t.Errorf("fallback assigned to %q, want generalist", assigned.AssignedTo)
+	}
+}
+
+func TestRecordTaskResultMetrics(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+
+	// Three successes and one failure at a known duration.
+	for i := 0; i < 3; i++ {
+		am.recordTaskResult("worker-1", true, 100*time.Millisecond)
+	}
+	am.recordTaskResult("worker-1", false, 100*time.Millisecond)
+
+	perf, err := am.GetPerformanceMetrics("worker-1")
+	if err != nil {
+		t.Fatalf("GetPerformanceMetrics: %v", err)
+	}
+	if perf.TotalTasks != 4 {
+		t.Errorf("TotalTasks = %d, want 4", perf.TotalTasks)
+	}
+	if perf.SuccessRate < 0.74 || perf.SuccessRate > 0.76 {
+		t.Errorf("SuccessRate = %v, want 0.75", perf.SuccessRate)
+	}
+	if perf.ErrorRate < 0.24 || perf.ErrorRate > 0.26 {
+		t.Errorf("ErrorRate = %v, want 0.25", perf.ErrorRate)
+	}
+	if perf.AverageResponseTime < 90*time.Millisecond || perf.AverageResponseTime > 110*time.Millisecond {
+		t.Errorf("AverageResponseTime = %v, want ~100ms", perf.AverageResponseTime)
+	}
+	if perf.QualityScore <= 0 || perf.QualityScore > 1 {
+		t.Errorf("QualityScore = %v, want in (0, 1]", perf.QualityScore)
+	}
+}
+
+func TestBestScoreUnprovenAgent(t *testing.T) {
+	if got := bestScore(AgentPerformance{}); got != 0.5 {
+		t.Errorf("bestScore(zero) = %v, want 0.5", got)
+	}
+}
+
+func TestPriorityQueueOrder(t *testing.T) {
+	am := NewAgentManager()
+	am.workers = 1
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		mu.Lock()
+		order = append(order, task.Payload)
+		mu.Unlock()
+		return nil, nil
+	})
+
+	// Enqueue before starting so the single worker drains strictly by
+	// priority.
+	var last *AgentTask
+	for _, tc := range []struct {
+		payload  string
+		priority int
+	}{
+		{"low", 1},
+		{"high", 10},
+		{"mid", 5},
+	} {
+		assigned, err := am.AssignTask(AgentTask{Type: "echo", Payload: tc.payload, Priority: tc.priority})
+		if err != nil {
+			t.Fatalf("AssignTask %s: %v", tc.payload, err)
+		}
+		last = assigned
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	for _, task := range []string{last.ID} {
+		waitForTaskStatus(t, am, task)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(order)
+		mu.Unlock()
+		if n == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"high", "mid", "low"}
+	if len(order) != 3 {
+		t.Fatalf("processed %d tasks, want 3", len(order))
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order[%d] = %q, want %q (full order %v)", i, order[i], want[i], order)
+		}
+	}
+}
+
+func TestHealthCheckerDisablesAndRecovers(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "flaky-agent"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	var mu sync.Mutex
+	var events []string
+	am.SetEventHandler(func(e AgentEvent) {
+		mu.Lock()
+		events = append(events, e.Type)
+		mu.Unlock()
+	})
+
+	healthy := false
+	hc := NewHealthChecker(am, func(ctx context.Context, agentID string) error {
+		if healthy {
+			return nil
+		}
+		return context.DeadlineExceeded
+	}, time.Hour)
+	hc.SetThreshold(2)
+
+	ctx := context.Background()
+	hc.CheckOnce(ctx)
+	if got := len(am.GetActiveAgents()); got != 1 {
+		t.Errorf("one failure should not disable the agent, active = %d", got)
+	}
+	hc.CheckOnce(ctx)
+	if got := len(am.GetActiveAgents()); got != 0 {
+		t.Errorf("agent should be offline after threshold failures, active = %d", got)
+	}
+
+	healthy = true
+	hc.CheckOnce(ctx)
+	if got := len(am.GetActiveAgents()); got != 1 {
+		t.Errorf("agent should recover after a healthy probe, active = %d", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var sawUnhealthy, sawRecovered bool
+	for _, e := range events {
+		switch e {
+		case "agent_unhealthy":
+			sawUnhealthy = true
+		case "agent_recovered":
+			sawRecovered = true
+		}
+	}
+	if !sawUnhealthy || !sawRecovered {
+		t.Errorf("expected agent_unhealthy and agent_recovered events, got %v", events)
+	}
+}
+
+func TestRuntimeRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents-runtime.json")
+
+	am := NewAgentManager()
+	if err := am.initializeAgents(); err != nil {
+		t.Fatalf("initializeAgents: %v", err)
+	}
+	am.recordTaskResult("coder", true, 200*time.Millisecond)
+	am.recordTaskResult("coder", false, 400*time.Millisecond)
+	if err := am.SaveRuntime(path); err != nil {
+		t.Fatalf("SaveRuntime: %v", err)
+	}
+	want, err := am.GetPerformanceMetrics("coder")
+	if err != nil {
+		t.Fatalf("GetPerformanceMetrics: %v", err)
+	}
+
+	restored := NewAgentManager()
+	restored.SetRuntimePath(path)
+	if err := restored.initializeAgents(); err != nil {
+		t.Fatalf("initializeAgents (restore): %v", err)
+	}
+	got, err := restored.GetPerformanceMetrics("coder")
+	if err != nil {
+		t.Fatalf("GetPerformanceMetrics (restore): %v", err)
+	}
+	if got != want {
+		t.Errorf("restored metrics = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadRuntimeIgnoresRemovedAgents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents-runtime.json")
+
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "legacy-agent"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	am.recordTaskResult("legacy-agent", true, time.Millisecond)
+	if err := am.SaveRuntime(path); err != nil {
+		t.Fatalf("SaveRuntime: %v", err)
+	}
+
+	// A fresh manager without that agent's config must not resurrect it.
+	restored := NewAgentManager()
+	if err := restored.LoadRuntime(path); err != nil {
+		t.Fatalf("LoadRuntime: %v", err)
+	}
+	if _, err := restored.GetPerformanceMetrics("legacy-agent"); err == nil {
+		t.Error("expected saved agent without a config to be dropped")
+	}
+}
+
+// waitForTaskStatus polls until the task reaches a terminal status.
+func waitForTaskStatus(t *testing.T, am *AgentManager, taskID string) AgentTask {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		task, ok := am.GetTask(taskID)
+		if ok && task.CompletedAt != nil {
+			return task
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("task %s did not complete in time", taskID)
+	return AgentTask{}
+}
+
+func TestWorkerPoolRunsTasks(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1", Name: "Worker"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		return "echo: " + task.Payload, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	assigned, err := am.AssignTask(AgentTask{Type: "echo", Payload: "hello"})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	if assigned.AssignedTo != "worker-1" {
+		t.Errorf("AssignedTo = %q, want worker-1", assigned.AssignedTo)
+	}
+
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "done" {
+		t.Fatalf("task status = %q, want done", done.Status)
+	}
+	if done.Result != "echo: hello" {
+		t.Errorf("Result = %v, want echo: hello", done.Result)
+	}
+	if done.CompletedAt == nil {
+		t.Error("expected CompletedAt to be set")
+	}
+
+	agents := am.GetActiveAgents()
+	if len(agents) != 1 || agents[0].TasksDone != 1 {
+		t.Errorf("expected TasksDone=1, got %+v", agents)
+	}
+}
+
+func TestTaskTimeout(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	var events []AgentEvent
+	var mu sync.Mutex
+	am.SetEventHandler(func(e AgentEvent) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return "too late", nil
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	assigned, err := am.AssignTask(AgentTask{Type: "slow", Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "timed_out" {
+		t.Fatalf("task status = %q, want timed_out", done.Status)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var sawTimeout bool
+	for _, e := range events {
+		if e.Type == "task_timeout" {
+			sawTimeout = true
+		}
+	}
+	if !sawTimeout {
+		t.Error("expected a task_timeout event")
+	}
+
+	perf, err := am.GetPerformanceMetrics("worker-1")
+	if err != nil {
+		t.Fatalf("GetPerformanceMetrics: %v", err)
+	}
+	if perf.ErrorRate != 1.0 {
+		t.Errorf("ErrorRate = %v, want 1.0 after timeout", perf.ErrorRate)
+	}
+}
+
+func TestCancelTask(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	started := make(chan struct{})
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	assigned, err := am.AssignTask(AgentTask{Type: "blocker"})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	<-started
+	if err := am.CancelTask(assigned.ID); err != nil {
+		t.Fatalf("CancelTask: %v", err)
+	}
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "cancelled" {
+		t.Errorf("task status = %q, want cancelled", done.Status)
+	}
+
+	if err := am.CancelTask("no-such-task"); err == nil {
+		t.Error("expected cancelling an unknown task to error")
+	}
+}
+
+func TestTaskRetrySucceedsAfterFailures(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	var mu sync.Mutex
+	var retries int
+	am.SetEventHandler(func(e AgentEvent) {
+		if e.Type == "task_retry" {
+			mu.Lock()
+			retries++
+			mu.Unlock()
+		}
+	})
+	var calls int32
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n < 3 {
+			return nil, context.DeadlineExceeded // any error; not a real deadline
+		}
+		return "ok", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	assigned, err := am.AssignTask(AgentTask{Type: "flaky", MaxRetries: 3})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "done" {
+		t.Fatalf("task status = %q, want done", done.Status)
+	}
+	if got, _ := done.Metadata["attempts"].(int); got != 3 {
+		t.Errorf("attempts = %v, want 3", done.Metadata["attempts"])
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if retries != 2 {
+		t.Errorf("saw %d task_retry events, want 2", retries)
+	}
+}
+
+func TestTaskRetryExhaustion(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	am.SetTaskHandler(func(ctx context.Context, task AgentTask) (interface{}, error) {
+		return nil, context.DeadlineExceeded
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	assigned, err := am.AssignTask(AgentTask{Type: "doomed", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "failed" {
+		t.Errorf("task status = %q, want failed after retries exhausted", done.Status)
+	}
+	if got, _ := done.Metadata["attempts"].(int); got != 2 {
+		t.Errorf("attempts = %v, want 2", done.Metadata["attempts"])
+	}
+}
+
+func TestWorkerRecordsFailure(t *testing.T) {
+	am := NewAgentManager()
+	if err := am.AddAgent(AgentConfig{ID: "worker-1"}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	am.Start(ctx)
+
+	// No handler registered: the task should fail, not hang.
+	assigned, err := am.AssignTask(AgentTask{Type: "echo"})
+	if err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	done := waitForTaskStatus(t, am, assigned.ID)
+	if done.Status != "failed" {
+		t.Errorf("task status = %q, want failed", done.Status)
+	}
+}
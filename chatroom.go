@@ -0,0 +1,1109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ==================== CHATROOM MODELS ====================
+
+// ChatMsg is one message shown in the chatroom conversation panel.
+type ChatMsg struct {
+	ID        string    `json:"id"`
+	ConvID    string    `json:"conv_id"`
+	Sender    string    `json:"sender"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+	FromAgent bool      `json:"from_agent"`
+}
+
+// ChatConversation groups chatroom messages under a name and type.
+type ChatConversation struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ==================== CHATROOM THEMES ====================
+
+// Theme is a named color palette the chatroom styles are built from.
+type Theme struct {
+	Name      string
+	Title     string
+	Border    string
+	Agent     string
+	Accent    string
+	Error     string
+	Dim       string
+	Heading   string
+	CodeFg    string
+	CodeBg    string
+	Highlight string
+}
+
+// chatroomThemes holds the available palettes; chatThemeOrder fixes the
+// cycle order for the theme keybind.
+var (
+	chatroomThemes = map[string]Theme{
+		"ocean": {
+			Name: "ocean", Title: "86", Border: "31", Agent: "213", Accent: "212",
+			Error: "196", Dim: "240", Heading: "81", CodeFg: "229", CodeBg: "236", Highlight: "30",
+		},
+		"dark": {
+			Name: "dark", Title: "252", Border: "240", Agent: "141", Accent: "178",
+			Error: "160", Dim: "238", Heading: "117", CodeFg: "223", CodeBg: "235", Highlight: "58",
+		},
+		"light": {
+			Name: "light", Title: "18", Border: "245", Agent: "90", Accent: "130",
+			Error: "124", Dim: "250", Heading: "25", CodeFg: "235", CodeBg: "254", Highlight: "153",
+		},
+		"high-contrast": {
+			Name: "high-contrast", Title: "15", Border: "15", Agent: "226", Accent: "51",
+			Error: "201", Dim: "7", Heading: "15", CodeFg: "0", CodeBg: "15", Highlight: "21",
+		},
+	}
+	chatThemeOrder = []string{"ocean", "dark", "light", "high-contrast"}
+)
+
+// chatStyles is the set of lipgloss styles derived from the active theme.
+type chatStyles struct {
+	title     lipgloss.Style
+	panel     lipgloss.Style
+	agent     lipgloss.Style
+	accent    lipgloss.Style
+	errText   lipgloss.Style
+	dim       lipgloss.Style
+	heading   lipgloss.Style
+	bold      lipgloss.Style
+	code      lipgloss.Style
+	highlight lipgloss.Style
+}
+
+// newChatStyles builds the style set for a theme.
+func newChatStyles(t Theme) chatStyles {
+	return chatStyles{
+		title:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.Title)),
+		panel:     lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(t.Border)).Padding(0, 1),
+		agent:     lipgloss.NewStyle().Foreground(lipgloss.Color(t.Agent)),
+		accent:    lipgloss.NewStyle().Foreground(lipgloss.Color(t.Accent)).Bold(true),
+		errText:   lipgloss.NewStyle().Foreground(lipgloss.Color(t.Error)),
+		dim:       lipgloss.NewStyle().Foreground(lipgloss.Color(t.Dim)),
+		heading:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.Heading)),
+		bold:      lipgloss.NewStyle().Bold(true),
+		code:      lipgloss.NewStyle().Foreground(lipgloss.Color(t.CodeFg)).Background(lipgloss.Color(t.CodeBg)),
+		highlight: lipgloss.NewStyle().Background(lipgloss.Color(t.Highlight)).Bold(true),
+	}
+}
+
+// ==================== CHATROOM MODEL ====================
+
+// ChatroomModel is the multi-agent chatroom UI.
+type ChatroomModel struct {
+	width  int
+	height int
+
+	inputText     string
+	messages      []ChatMsg
+	conversations map[string]*ChatConversation
+	activeConvID  string
+
+	inputHistory []string
+	historyIndex int // -1 when not browsing history
+	historyDraft string
+	maxHistory   int
+
+	agents           []AgentConfig
+	selectedAgentIDs map[string]bool
+	agentCursor      int
+	focus            string // "input" or "agents"
+
+	providers       []string
+	activeProvider  string
+	providerCursor  int
+	availableModels []string
+	// providerModels resolves the model list for a provider; swapped out
+	// in tests to avoid network access.
+	providerModels func(id string) ([]string, error)
+
+	convManager *ConversationManager
+	convTypes   []string
+	typeCursor  int
+
+	scrollOffset   int // messages scrolled up from the bottom; 0 = latest
+	unreadCount    int
+	errorText      string
+	userName       string
+	renderMarkdown bool
+
+	themeName string
+	styles    chatStyles
+	timeMode  TimeMode
+}
+
+// ApplyTheme switches the chatroom to the named palette, rebuilding all
+// derived styles. Unknown names leave the model untouched.
+func (m ChatroomModel) ApplyTheme(name string) (ChatroomModel, error) {
+	theme, ok := chatroomThemes[name]
+	if !ok {
+		return m, fmt.Errorf("unknown theme: %s", name)
+	}
+	m.themeName = name
+	m.styles = newChatStyles(theme)
+	return m, nil
+}
+
+// cycleTheme advances to the next palette in chatThemeOrder.
+func (m ChatroomModel) cycleTheme() ChatroomModel {
+	for i, name := range chatThemeOrder {
+		if name == m.themeName {
+			next, _ := m.ApplyTheme(chatThemeOrder[(i+1)%len(chatThemeOrder)])
+			return next
+		}
+	}
+	next, _ := m.ApplyTheme(chatThemeOrder[0])
+	return next
+}
+
+// chatConvTypeMinimums is the smallest participant count each
+// conversation type makes sense with: the structured types all need at
+// least two agents to take turns, vote or argue.
+var chatConvTypeMinimums = map[string]int{
+	"free_form":    1,
+	"democratic":   2,
+	"hierarchical": 2,
+	"debate":       2,
+	"consensus":    2,
+	"ensemble":     2,
+}
+
+// newChatroomConvManager builds the conversation manager backing the
+// chatroom, registering the chatroom-only configs on top of the built-ins.
+func newChatroomConvManager() *ConversationManager {
+	cm := NewConversationManager()
+	cm.RegisterConfig(ConversationConfig{Name: "free_form", Type: ConversationType("free_form")})
+	cm.RegisterConfig(ConversationConfig{Name: "ensemble", Type: ConversationType("ensemble")})
+	return cm
+}
+
+// NewChatroomModel starts with one general conversation and the default
+// agent roster selected.
+func NewChatroomModel() ChatroomModel {
+	conv := &ChatConversation{
+		ID:        newConvID(),
+		Name:      "general",
+		Type:      "free_form",
+		CreatedAt: time.Now(),
+	}
+	m := ChatroomModel{
+		conversations:    map[string]*ChatConversation{conv.ID: conv},
+		activeConvID:     conv.ID,
+		agents:           defaultAgentConfigs(),
+		selectedAgentIDs: make(map[string]bool),
+		providers:        []string{"openrouter", "ollama"},
+		activeProvider:   "openrouter",
+		historyIndex:     -1,
+		maxHistory:       50,
+		renderMarkdown:   true,
+		themeName:        "ocean",
+		styles:           newChatStyles(chatroomThemes["ocean"]),
+		convManager:      newChatroomConvManager(),
+		providerModels:   resolveProviderModels,
+		convTypes:        []string{"free_form", "democratic", "hierarchical", "debate", "consensus", "ensemble"},
+		focus:            "input",
+		userName:         "user",
+	}
+	for _, agent := range m.agents {
+		m.selectedAgentIDs[agent.ID] = true
+	}
+	return m
+}
+
+// Init implements tea.Model.
+func (m ChatroomModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m ChatroomModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyTab:
+			switch m.focus {
+			case "input":
+				m.focus = "agents"
+			case "agents":
+				m.focus = "types"
+			case "types":
+				m.focus = "providers"
+			default:
+				m.focus = "input"
+			}
+			return m, nil
+		case tea.KeyEnter:
+			switch m.focus {
+			case "types":
+				return m.applyConversationType(), nil
+			case "providers":
+				return m.applyProvider(), nil
+			}
+			if m.focus != "input" {
+				return m, nil
+			}
+			// sendMessage returns the updated model; returning the
+			// receiver here would silently discard the appended message.
+			return m.sendMessage()
+		case tea.KeyCtrlB:
+			return m.cycleTheme(), nil
+		case tea.KeyPgUp:
+			return m.navigateMessages(1), nil
+		case tea.KeyPgDown:
+			return m.navigateMessages(-1), nil
+		case tea.KeyHome:
+			return m.jumpToOldest(), nil
+		case tea.KeyEnd:
+			return m.jumpToLatest(), nil
+		case tea.KeyCtrlR:
+			if m.timeMode == TimeAbsolute {
+				m.timeMode = TimeRelative
+			} else {
+				m.timeMode = TimeAbsolute
+			}
+			return m, nil
+		case tea.KeyCtrlS:
+			if err := m.SaveConversations(defaultChatroomSavePath()); err != nil {
+				m.errorText = err.Error()
+			}
+			return m, nil
+		case tea.KeyCtrlL:
+			loaded, err := m.LoadConversations(defaultChatroomSavePath())
+			if err != nil {
+				m.errorText = err.Error()
+				return m, nil
+			}
+			return loaded, nil
+		case tea.KeyCtrlG:
+			m.renderMarkdown = !m.renderMarkdown
+			return m, nil
+		case tea.KeyUp:
+			switch m.focus {
+			case "agents":
+				return m.moveAgentCursor(-1), nil
+			case "types":
+				return m.moveTypeCursor(-1), nil
+			case "providers":
+				return m.moveProviderCursor(-1), nil
+			}
+			return m.navigateHistory(-1), nil
+		case tea.KeyDown:
+			switch m.focus {
+			case "agents":
+				return m.moveAgentCursor(1), nil
+			case "types":
+				return m.moveTypeCursor(1), nil
+			case "providers":
+				return m.moveProviderCursor(1), nil
+			}
+			return m.navigateHistory(1), nil
+		case tea.KeyBackspace:
+			if len(m.inputText) > 0 {
+				m.inputText = m.inputText[:len(m.inputText)-1]
+			}
+			return m, nil
+		case tea.KeyRunes, tea.KeySpace:
+			if m.focus != "input" {
+				if m.focus == "agents" && msg.Type == tea.KeySpace {
+					return m.toggleAgentAtCursor(), nil
+				}
+				return m, nil
+			}
+			m.historyIndex = -1
+			if msg.Type == tea.KeySpace {
+				// The terminal driver sends KeySpace with Runes{' '};
+				// appending both would double every space.
+				m.inputText += " "
+			} else {
+				m.inputText += string(msg.Runes)
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// ==================== SLASH COMMANDS ====================
+
+// Command is a parsed slash command from the input line.
+type Command struct {
+	Name string
+	Arg  string
+}
+
+// chatCommandHelp documents the available slash commands in /help output.
+const chatCommandHelp = "/clear  drop this conversation's messages\n" +
+	"/agents  focus the agent panel\n" +
+	"/type <name>  switch the conversation type\n" +
+	"/provider <id>  switch the AI provider\n" +
+	"/save  save conversations to disk\n" +
+	"/help  show this help"
+
+// parseCommand parses a slash command. Commands that take an argument
+// require one; the rest reject trailing arguments.
+func parseCommand(input string) (Command, error) {
+	fields := strings.Fields(strings.TrimSpace(input))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return Command{}, fmt.Errorf("not a command: %s", input)
+	}
+	cmd := Command{Name: strings.TrimPrefix(fields[0], "/")}
+	switch cmd.Name {
+	case "clear", "agents", "save", "help":
+		if len(fields) > 1 {
+			return Command{}, fmt.Errorf("/%s takes no arguments", cmd.Name)
+		}
+	case "type", "provider":
+		if len(fields) != 2 {
+			return Command{}, fmt.Errorf("usage: /%s <name>", cmd.Name)
+		}
+		cmd.Arg = fields[1]
+	default:
+		return Command{}, fmt.Errorf("unknown command: /%s", cmd.Name)
+	}
+	return cmd, nil
+}
+
+// runCommand executes a parsed slash command against the model.
+func (m ChatroomModel) runCommand(cmd Command) ChatroomModel {
+	switch cmd.Name {
+	case "clear":
+		var kept []ChatMsg
+		for _, msg := range m.messages {
+			if msg.ConvID != m.activeConvID {
+				kept = append(kept, msg)
+			}
+		}
+		m.messages = kept
+		m.scrollOffset = 0
+		m.unreadCount = 0
+	case "agents":
+		m.focus = "agents"
+	case "type":
+		found := false
+		for i, name := range m.convTypes {
+			if name == cmd.Arg {
+				m.typeCursor = i
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.errorText = fmt.Sprintf("unknown conversation type: %s", cmd.Arg)
+			return m
+		}
+		m = m.applyConversationType()
+	case "provider":
+		found := false
+		for i, name := range m.providers {
+			if name == cmd.Arg {
+				m.providerCursor = i
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.errorText = fmt.Sprintf("unknown provider: %s", cmd.Arg)
+			return m
+		}
+		m = m.applyProvider()
+	case "save":
+		if err := m.SaveConversations(defaultChatroomSavePath()); err != nil {
+			m.errorText = err.Error()
+		}
+	case "help":
+		m = m.addIncoming(ChatMsg{
+			ID:        newConvID(),
+			ConvID:    m.activeConvID,
+			Sender:    "system",
+			Content:   chatCommandHelp,
+			Timestamp: time.Now(),
+		})
+	}
+	return m
+}
+
+// sendMessage appends the typed message to the active conversation and
+// clears the input, returning the updated model. Lines starting with a
+// slash are dispatched as commands instead of being sent.
+func (m ChatroomModel) sendMessage() (ChatroomModel, tea.Cmd) {
+	text := strings.TrimSpace(m.inputText)
+	if text == "" {
+		return m, nil
+	}
+	if strings.HasPrefix(text, "/") {
+		m = m.rememberInput(text)
+		m.inputText = ""
+		cmd, err := parseCommand(text)
+		if err != nil {
+			m.errorText = err.Error()
+			return m, nil
+		}
+		m.errorText = ""
+		return m.runCommand(cmd), nil
+	}
+	m.messages = append(m.messages, ChatMsg{
+		ID:        newConvID(),
+		ConvID:    m.activeConvID,
+		Sender:    m.userName,
+		Content:   text,
+		Timestamp: time.Now(),
+	})
+	m = m.rememberInput(text)
+	m.inputText = ""
+	m.errorText = ""
+	// Sending always jumps back to the latest messages.
+	m.scrollOffset = 0
+	m.unreadCount = 0
+	return m, nil
+}
+
+// addIncoming appends a message that arrived from elsewhere — an agent
+// reply or another user. When the view is scrolled up the message lands
+// out of sight, so it counts as unread; at the bottom the view simply
+// follows along.
+func (m ChatroomModel) addIncoming(msg ChatMsg) ChatroomModel {
+	m.messages = append(m.messages, msg)
+	if m.scrollOffset > 0 && msg.ConvID == m.activeConvID {
+		m.unreadCount++
+	}
+	return m
+}
+
+// jumpToLatest scrolls back to the newest message and clears the unread
+// counter.
+func (m ChatroomModel) jumpToLatest() ChatroomModel {
+	m.scrollOffset = 0
+	m.unreadCount = 0
+	return m
+}
+
+// visibleMessageCount is how many message lines fit in the conversation
+// panel at the current terminal height.
+func (m ChatroomModel) visibleMessageCount() int {
+	if m.height <= 0 {
+		return 10
+	}
+	// Borders, input line and titles eat into the panel height.
+	visible := m.height - 8
+	if visible < 3 {
+		visible = 3
+	}
+	return visible
+}
+
+// maxScrollOffset is how far up the view can scroll: once the oldest
+// message is on screen there is nothing further to show. With fewer
+// messages than fit in the panel it is zero, which keeps scrollOffset
+// from underflowing the render window.
+func (m ChatroomModel) maxScrollOffset() int {
+	max := len(m.activeMessages()) - m.visibleMessageCount()
+	if max < 0 {
+		return 0
+	}
+	return max
+}
+
+// navigateMessages scrolls the conversation panel, clamping to the
+// scrollable range on both ends.
+func (m ChatroomModel) navigateMessages(dir int) ChatroomModel {
+	m.scrollOffset += dir
+	if m.scrollOffset < 0 {
+		m.scrollOffset = 0
+	}
+	if max := m.maxScrollOffset(); m.scrollOffset > max {
+		m.scrollOffset = max
+	}
+	if m.scrollOffset == 0 {
+		m.unreadCount = 0
+	}
+	return m
+}
+
+// jumpToOldest scrolls to the top of the conversation.
+func (m ChatroomModel) jumpToOldest() ChatroomModel {
+	m.scrollOffset = m.maxScrollOffset()
+	return m
+}
+
+// rememberInput appends a sent line to the input history, skipping
+// consecutive duplicates and trimming to maxHistory entries.
+func (m ChatroomModel) rememberInput(text string) ChatroomModel {
+	if n := len(m.inputHistory); n == 0 || m.inputHistory[n-1] != text {
+		m.inputHistory = append(m.inputHistory, text)
+	}
+	if m.maxHistory > 0 && len(m.inputHistory) > m.maxHistory {
+		m.inputHistory = m.inputHistory[len(m.inputHistory)-m.maxHistory:]
+	}
+	m.historyIndex = -1
+	m.historyDraft = ""
+	return m
+}
+
+// navigateHistory moves through previously sent messages. Going up past
+// the oldest entry stays there; coming back down past the newest restores
+// whatever draft was being typed.
+func (m ChatroomModel) navigateHistory(dir int) ChatroomModel {
+	if len(m.inputHistory) == 0 {
+		return m
+	}
+	switch {
+	case dir < 0:
+		if m.historyIndex == -1 {
+			m.historyDraft = m.inputText
+			m.historyIndex = len(m.inputHistory) - 1
+		} else if m.historyIndex > 0 {
+			m.historyIndex--
+		}
+		m.inputText = m.inputHistory[m.historyIndex]
+	case dir > 0:
+		if m.historyIndex == -1 {
+			return m
+		}
+		if m.historyIndex < len(m.inputHistory)-1 {
+			m.historyIndex++
+			m.inputText = m.inputHistory[m.historyIndex]
+		} else {
+			m.historyIndex = -1
+			m.inputText = m.historyDraft
+			m.historyDraft = ""
+		}
+	}
+	return m
+}
+
+// activeMessages returns the messages of the active conversation.
+func (m ChatroomModel) activeMessages() []ChatMsg {
+	var out []ChatMsg
+	for _, msg := range m.messages {
+		if msg.ConvID == m.activeConvID {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// ==================== PERSISTENCE ====================
+
+// chatroomState is the on-disk snapshot of the chatroom.
+type chatroomState struct {
+	SavedAt       time.Time                    `json:"saved_at"`
+	ActiveConvID  string                       `json:"active_conv_id"`
+	Conversations map[string]*ChatConversation `json:"conversations"`
+	Messages      []ChatMsg                    `json:"messages"`
+}
+
+// SaveConversations writes the chatroom state to path atomically: the
+// snapshot is written to a temp file in the same directory and renamed
+// into place so a crash mid-write cannot truncate an existing save.
+func (m ChatroomModel) SaveConversations(path string) error {
+	state := chatroomState{
+		SavedAt:       time.Now(),
+		ActiveConvID:  m.activeConvID,
+		Conversations: m.conversations,
+		Messages:      m.messages,
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversations: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".chatroom-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write conversations: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadConversations replaces the chatroom state with the snapshot at
+// path, returning the updated model. If the saved active conversation no
+// longer exists, any remaining conversation is made active instead.
+func (m ChatroomModel) LoadConversations(path string) (ChatroomModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("failed to read conversations: %w", err)
+	}
+	var state chatroomState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return m, fmt.Errorf("failed to parse conversations: %w", err)
+	}
+	if len(state.Conversations) == 0 {
+		return m, fmt.Errorf("no conversations in %s", path)
+	}
+	m.conversations = state.Conversations
+	m.messages = state.Messages
+	m.activeConvID = state.ActiveConvID
+	if _, ok := m.conversations[m.activeConvID]; !ok {
+		for id := range m.conversations {
+			m.activeConvID = id
+			break
+		}
+	}
+	m.scrollOffset = 0
+	return m, nil
+}
+
+// defaultChatroomSavePath is where Ctrl+S/Ctrl+L persist the chatroom.
+func defaultChatroomSavePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".chatroom-conversations.json"
+	}
+	return filepath.Join(home, ".chatroom-conversations.json")
+}
+
+// ==================== TEXT WRAPPING ====================
+
+// wrapText wraps s to width at word boundaries, preserving explicit
+// newlines. Tokens longer than the width — URLs, hashes — are hard-broken
+// rather than overflowing the line. Width zero or less disables wrapping.
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return strings.Split(s, "\n")
+	}
+	var out []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		if paragraph == "" {
+			out = append(out, "")
+			continue
+		}
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			// Hard-break tokens that can never fit on one line.
+			for len(word) > width {
+				if current != "" {
+					out = append(out, current)
+					current = ""
+				}
+				out = append(out, word[:width])
+				word = word[width:]
+			}
+			switch {
+			case current == "":
+				current = word
+			case len(current)+1+len(word) <= width:
+				current += " " + word
+			default:
+				out = append(out, current)
+				current = word
+			}
+		}
+		if current != "" {
+			out = append(out, current)
+		}
+	}
+	return out
+}
+
+// ==================== TIMESTAMPS ====================
+
+// TimeMode selects how message timestamps are displayed.
+type TimeMode int
+
+const (
+	TimeAbsolute TimeMode = iota
+	TimeRelative
+)
+
+// formatMessageTime renders a message timestamp either as an absolute
+// date or relative to now ("3m ago"). Future timestamps — clock skew
+// between machines — fall back to absolute formatting.
+func formatMessageTime(t time.Time, mode TimeMode, now time.Time) string {
+	if mode == TimeAbsolute || t.After(now) {
+		return t.Format("2006-01-02 15:04")
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// ==================== MARKDOWN ====================
+
+// renderMarkdownText converts a small markdown subset — headings, list
+// items, **bold** spans and fenced code blocks — into styled terminal
+// text wrapped to width. It deliberately stays line-oriented; anything
+// it does not recognise passes through unchanged.
+func renderMarkdownText(content string, width int, styles chatStyles) string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+			continue
+		case inCode:
+			out = append(out, styles.code.Render(line))
+		case strings.HasPrefix(trimmed, "#"):
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, wrapMarkdownLine(styles.heading.Render(text), width)...)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			out = append(out, wrapMarkdownLine("• "+renderBoldSpans(trimmed[2:], styles), width)...)
+		default:
+			out = append(out, wrapMarkdownLine(renderBoldSpans(line, styles), width)...)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderBoldSpans styles **text** spans; unbalanced markers are left alone.
+func renderBoldSpans(line string, styles chatStyles) string {
+	parts := strings.Split(line, "**")
+	if len(parts) < 3 {
+		return line
+	}
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-(len(parts)%2) {
+			b.WriteString(styles.bold.Render(part))
+		} else {
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+// wrapMarkdownLine word-wraps one already-styled line to width. Styled
+// words keep their escape sequences; width accounting is approximate for
+// heavily styled text, which is acceptable for panel display.
+func wrapMarkdownLine(line string, width int) []string {
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return []string{line}
+	}
+	words := strings.Fields(line)
+	var lines []string
+	current := ""
+	for _, word := range words {
+		if current == "" {
+			current = word
+		} else if lipgloss.Width(current)+1+lipgloss.Width(word) <= width {
+			current += " " + word
+		} else {
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// ==================== RENDERING ====================
+
+// conversationWidth is the text width available inside the center panel.
+func (m ChatroomModel) conversationWidth() int {
+	if m.width <= 0 {
+		return 76
+	}
+	// Half the terminal for the conversation column, minus border and padding.
+	return minInt(m.width/2, m.width-4)
+}
+
+// renderConversationPanel shows the active conversation's messages.
+func (m ChatroomModel) renderConversationPanel() string {
+	var b strings.Builder
+	b.WriteString(m.styles.title.Render("Conversation"))
+	b.WriteString("\n")
+	msgs := m.activeMessages()
+	end := len(msgs) - m.scrollOffset
+	if end < 0 {
+		end = 0
+	}
+	start := end - m.visibleMessageCount()
+	if start < 0 {
+		start = 0
+	}
+	for _, msg := range msgs[start:end] {
+		sender := msg.Sender
+		if msg.FromAgent {
+			sender = m.styles.agent.Render(sender)
+		}
+		stamp := formatMessageTime(msg.Timestamp, m.timeMode, time.Now())
+		// The timestamp and sender prefix eats into the usable width;
+		// continuation lines are indented to line up under the content.
+		prefixWidth := len(stamp) + 1 + len(msg.Sender) + 2
+		var lines []string
+		if m.renderMarkdown {
+			// renderMarkdownText wraps styled lines itself; re-wrapping
+			// here would split escape sequences.
+			lines = strings.Split(renderMarkdownText(msg.Content, m.conversationWidth()-prefixWidth, m.styles), "\n")
+		} else {
+			lines = wrapText(msg.Content, m.conversationWidth()-prefixWidth)
+		}
+		indent := strings.Repeat(" ", prefixWidth)
+		for i, line := range lines {
+			if i == 0 {
+				b.WriteString(fmt.Sprintf("%s %s: %s\n", m.styles.dim.Render(stamp), sender, line))
+			} else {
+				b.WriteString(indent + line + "\n")
+			}
+		}
+	}
+	if m.scrollOffset > 0 && m.unreadCount > 0 {
+		b.WriteString(m.styles.accent.Render(fmt.Sprintf("▼ %d new", m.unreadCount)) + "\n")
+	}
+	b.WriteString("\n> " + m.inputText)
+	if m.errorText != "" {
+		b.WriteString("\n" + m.styles.errText.Render(m.errorText))
+	}
+	return m.styles.panel.Render(b.String())
+}
+
+// moveAgentCursor moves the agent-panel cursor, clamped to the roster.
+func (m ChatroomModel) moveAgentCursor(dir int) ChatroomModel {
+	if len(m.agents) == 0 {
+		return m
+	}
+	m.agentCursor += dir
+	if m.agentCursor < 0 {
+		m.agentCursor = 0
+	}
+	if m.agentCursor > len(m.agents)-1 {
+		m.agentCursor = len(m.agents) - 1
+	}
+	return m
+}
+
+// toggleAgentAtCursor flips whether the highlighted agent participates
+// in conversations.
+func (m ChatroomModel) toggleAgentAtCursor() ChatroomModel {
+	if m.agentCursor < 0 || m.agentCursor >= len(m.agents) {
+		return m
+	}
+	id := m.agents[m.agentCursor].ID
+	m.selectedAgentIDs[id] = !m.selectedAgentIDs[id]
+	return m
+}
+
+// renderAgentPanel lists agents and which are selected, highlighting the
+// cursor row when the panel has focus.
+func (m ChatroomModel) renderAgentPanel() string {
+	var b strings.Builder
+	b.WriteString(m.styles.title.Render("Agents"))
+	b.WriteString("\n")
+	for i, agent := range m.agents {
+		marker := "[ ]"
+		if m.selectedAgentIDs[agent.ID] {
+			marker = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", marker, agent.Name)
+		if m.focus == "agents" && i == m.agentCursor {
+			line = m.styles.accent.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return m.styles.panel.Render(b.String())
+}
+
+// moveTypeCursor moves the conversation-type cursor, clamped to the list.
+func (m ChatroomModel) moveTypeCursor(dir int) ChatroomModel {
+	if len(m.convTypes) == 0 {
+		return m
+	}
+	m.typeCursor += dir
+	if m.typeCursor < 0 {
+		m.typeCursor = 0
+	}
+	if m.typeCursor > len(m.convTypes)-1 {
+		m.typeCursor = len(m.convTypes) - 1
+	}
+	return m
+}
+
+// selectedAgents returns the IDs of the currently selected agents in
+// roster order.
+func (m ChatroomModel) selectedAgents() []string {
+	var out []string
+	for _, agent := range m.agents {
+		if m.selectedAgentIDs[agent.ID] {
+			out = append(out, agent.ID)
+		}
+	}
+	return out
+}
+
+// applyConversationType switches the active conversation to the
+// highlighted type, creating the backing managed conversation with the
+// selected agents as participants. Types with participant minimums are
+// rejected with an inline error instead of creating a broken conversation.
+func (m ChatroomModel) applyConversationType() ChatroomModel {
+	if m.typeCursor < 0 || m.typeCursor >= len(m.convTypes) {
+		return m
+	}
+	name := m.convTypes[m.typeCursor]
+	participants := m.selectedAgents()
+	if min, ok := chatConvTypeMinimums[name]; ok && len(participants) < min {
+		m.errorText = fmt.Sprintf("%s conversations need at least %d agents, %d selected", name, min, len(participants))
+		return m
+	}
+	managedID := newConvID()
+	if err := m.convManager.CreateConversation(managedID, name, participants); err != nil {
+		m.errorText = err.Error()
+		return m
+	}
+	if conv, ok := m.conversations[m.activeConvID]; ok {
+		conv.Type = name
+	}
+	m.errorText = ""
+	return m
+}
+
+// resolveProviderModels asks the named provider for its model list.
+func resolveProviderModels(id string) ([]string, error) {
+	switch id {
+	case "openrouter":
+		client := NewOpenRouterClient(OpenRouterConfig{APIKey: os.Getenv("OPENROUTER_API_KEY")})
+		return client.GetModels(context.Background())
+	case "ollama":
+		return NewOllamaClient(OllamaConfig{}).GetModels(context.Background())
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", id)
+	}
+}
+
+// moveProviderCursor moves the provider cursor, clamped to the list.
+func (m ChatroomModel) moveProviderCursor(dir int) ChatroomModel {
+	if len(m.providers) == 0 {
+		return m
+	}
+	m.providerCursor += dir
+	if m.providerCursor < 0 {
+		m.providerCursor = 0
+	}
+	if m.providerCursor > len(m.providers)-1 {
+		m.providerCursor = len(m.providers) - 1
+	}
+	return m
+}
+
+// applyProvider switches to the highlighted provider, re-resolving its
+// model list first. Providers that cannot be reached or expose no models
+// are rejected with an inline error and the current provider stays active.
+func (m ChatroomModel) applyProvider() ChatroomModel {
+	if m.providerCursor < 0 || m.providerCursor >= len(m.providers) {
+		return m
+	}
+	id := m.providers[m.providerCursor]
+	models, err := m.providerModels(id)
+	if err != nil {
+		m.errorText = fmt.Sprintf("provider %s unavailable: %v", id, err)
+		return m
+	}
+	if len(models) == 0 {
+		m.errorText = fmt.Sprintf("provider %s has no models", id)
+		return m
+	}
+	m.activeProvider = id
+	m.availableModels = models
+	m.errorText = ""
+	return m
+}
+
+// renderControlPanel shows conversation types and providers.
+func (m ChatroomModel) renderControlPanel() string {
+	var b strings.Builder
+	b.WriteString(m.styles.title.Render("Controls"))
+	b.WriteString("\n")
+	b.WriteString("Provider: " + m.styles.accent.Render(m.activeProvider) + "\n")
+	if conv, ok := m.conversations[m.activeConvID]; ok {
+		b.WriteString("Type: " + conv.Type + "\n")
+	}
+	b.WriteString("\n" + m.styles.title.Render("Conversation Types") + "\n")
+	for i, name := range m.convTypes {
+		line := name
+		if m.focus == "types" && i == m.typeCursor {
+			line = m.styles.accent.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n" + m.styles.title.Render("Providers") + "\n")
+	for i, name := range m.providers {
+		line := name
+		if name == m.activeProvider {
+			line += " *"
+		}
+		if m.focus == "providers" && i == m.providerCursor {
+			line = m.styles.accent.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return m.styles.panel.Render(b.String())
+}
+
+// Per-panel minimum content widths; the three-column minimum is derived
+// from these rather than a magic terminal size.
+const (
+	chatAgentPanelMinWidth   = 20
+	chatConvPanelMinWidth    = 40
+	chatControlPanelMinWidth = 24
+	chatPanelMinHeight       = 12
+	// Each panel adds two columns of border and two of padding.
+	chatPanelChrome = 4
+)
+
+// minSize implements minSizer: the chatroom needs all three columns at
+// their minimum widths plus their borders and padding.
+func (m ChatroomModel) minSize() (int, int) {
+	width := chatAgentPanelMinWidth + chatConvPanelMinWidth + chatControlPanelMinWidth + 3*chatPanelChrome
+	return width, chatPanelMinHeight
+}
+
+// joinColumns lays out the three chatroom panels side by side. The
+// position argument matters: JoinHorizontal aligns the shorter columns
+// against the top edge rather than garbling the layout.
+func joinColumns(left, center, right string) string {
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, center, right)
+}
+
+// View implements tea.Model.
+func (m ChatroomModel) View() string {
+	// Width zero means no WindowSizeMsg has arrived yet; render normally
+	// rather than flashing the too-small notice on startup.
+	if m.width > 0 && terminalTooSmall(m, m.width, m.height) {
+		return renderTooSmallView(m, m.width, m.height)
+	}
+	columns := joinColumns(m.renderAgentPanel(), m.renderConversationPanel(), m.renderControlPanel())
+	return lipgloss.JoinVertical(lipgloss.Left, columns)
+}
@@ -0,0 +1,876 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeInto feeds a string into the model one rune at a time.
+func typeInto(t *testing.T, m ChatroomModel, s string) ChatroomModel {
+	t.Helper()
+	for _, r := range s {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = model.(ChatroomModel)
+	}
+	return m
+}
+
+func TestSendMessageAppendsAndClearsInput(t *testing.T) {
+	m := NewChatroomModel()
+	m = typeInto(t, m, "hello agents")
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if len(m.messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(m.messages))
+	}
+	if m.messages[0].Content != "hello agents" {
+		t.Errorf("content = %q", m.messages[0].Content)
+	}
+	if m.messages[0].ConvID != m.activeConvID {
+		t.Errorf("message attached to conv %q, want %q", m.messages[0].ConvID, m.activeConvID)
+	}
+	if m.inputText != "" {
+		t.Errorf("input not cleared: %q", m.inputText)
+	}
+}
+
+// send types a line and presses enter.
+func send(t *testing.T, m ChatroomModel, s string) ChatroomModel {
+	t.Helper()
+	m = typeInto(t, m, s)
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return model.(ChatroomModel)
+}
+
+func TestHistoryCyclesAndRestoresDraft(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "first")
+	m = send(t, m, "second")
+	m = typeInto(t, m, "dra")
+
+	m = m.navigateHistory(-1)
+	if m.inputText != "second" {
+		t.Fatalf("after up, input = %q, want second", m.inputText)
+	}
+	m = m.navigateHistory(-1)
+	if m.inputText != "first" {
+		t.Fatalf("after two ups, input = %q, want first", m.inputText)
+	}
+	// Up at the oldest entry stays put.
+	m = m.navigateHistory(-1)
+	if m.inputText != "first" {
+		t.Errorf("up past oldest moved to %q", m.inputText)
+	}
+	m = m.navigateHistory(1)
+	if m.inputText != "second" {
+		t.Errorf("after down, input = %q, want second", m.inputText)
+	}
+	// Down past the newest restores the in-progress draft.
+	m = m.navigateHistory(1)
+	if m.inputText != "dra" {
+		t.Errorf("draft not restored, input = %q", m.inputText)
+	}
+	if m.historyIndex != -1 {
+		t.Errorf("historyIndex = %d, want -1", m.historyIndex)
+	}
+}
+
+func TestHistorySkipsConsecutiveDuplicates(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "same")
+	m = send(t, m, "same")
+	m = send(t, m, "other")
+	m = send(t, m, "same")
+
+	want := []string{"same", "other", "same"}
+	if len(m.inputHistory) != len(want) {
+		t.Fatalf("history = %v, want %v", m.inputHistory, want)
+	}
+	for i, w := range want {
+		if m.inputHistory[i] != w {
+			t.Errorf("history[%d] = %q, want %q", i, m.inputHistory[i], w)
+		}
+	}
+}
+
+func TestHistoryCapped(t *testing.T) {
+	m := NewChatroomModel()
+	m.maxHistory = 3
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		m = send(t, m, s)
+	}
+	if len(m.inputHistory) != 3 {
+		t.Fatalf("history length = %d, want 3", len(m.inputHistory))
+	}
+	if m.inputHistory[0] != "c" || m.inputHistory[2] != "e" {
+		t.Errorf("history = %v, want [c d e]", m.inputHistory)
+	}
+}
+
+func TestMarkdownStylesCodeFences(t *testing.T) {
+	styles := newChatStyles(chatroomThemes["ocean"])
+	got := renderMarkdownText("before\n```\ncode line\n```\nafter", 80, styles)
+	if strings.Contains(got, "```") {
+		t.Error("fence markers should not be rendered")
+	}
+	want := styles.code.Render("code line")
+	if !strings.Contains(got, want) {
+		t.Errorf("code line not styled:\n%q", got)
+	}
+	if !strings.Contains(got, "before") || !strings.Contains(got, "after") {
+		t.Error("surrounding text lost")
+	}
+}
+
+func TestMarkdownHeadingsListsAndBold(t *testing.T) {
+	styles := newChatStyles(chatroomThemes["ocean"])
+	got := renderMarkdownText("# Title\n- item\nsome **bold** text", 80, styles)
+	if !strings.Contains(got, styles.heading.Render("Title")) {
+		t.Error("heading not styled")
+	}
+	if !strings.Contains(got, "• item") {
+		t.Error("list item not bulleted")
+	}
+	if !strings.Contains(got, styles.bold.Render("bold")) {
+		t.Error("bold span not styled")
+	}
+}
+
+func TestMarkdownWrapsToWidth(t *testing.T) {
+	styles := newChatStyles(chatroomThemes["ocean"])
+	got := renderMarkdownText("one two three four five six seven eight", 15, styles)
+	for i, line := range strings.Split(got, "\n") {
+		if w := len(line); w > 15 {
+			t.Errorf("line %d is %d columns wide: %q", i, w, line)
+		}
+	}
+	if !strings.Contains(got, "\n") {
+		t.Error("long line was not wrapped")
+	}
+}
+
+func TestMarkdownToggleFallsBackToPlainText(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "# heading")
+
+	if view := m.renderConversationPanel(); !strings.Contains(view, m.styles.heading.Render("heading")) {
+		t.Error("markdown should render by default")
+	}
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	m = model.(ChatroomModel)
+	if view := m.renderConversationPanel(); !strings.Contains(view, "# heading") {
+		t.Error("plain-text fallback should show the raw message")
+	}
+}
+
+func TestAgentCursorNavigationClamps(t *testing.T) {
+	m := NewChatroomModel()
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = model.(ChatroomModel)
+	if m.focus != "agents" {
+		t.Fatalf("focus = %q, want agents", m.focus)
+	}
+
+	// Moving up from the top stays at the top.
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = model.(ChatroomModel)
+	if m.agentCursor != 0 {
+		t.Errorf("cursor = %d after up at top", m.agentCursor)
+	}
+
+	for i := 0; i < len(m.agents)+3; i++ {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = model.(ChatroomModel)
+	}
+	if m.agentCursor != len(m.agents)-1 {
+		t.Errorf("cursor = %d, want clamped to %d", m.agentCursor, len(m.agents)-1)
+	}
+}
+
+func TestSpaceTogglesAgentSelection(t *testing.T) {
+	m := NewChatroomModel()
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = model.(ChatroomModel)
+
+	id := m.agents[0].ID
+	if !m.selectedAgentIDs[id] {
+		t.Fatal("agents start selected")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(ChatroomModel)
+	if m.selectedAgentIDs[id] {
+		t.Error("space did not deselect the agent")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(ChatroomModel)
+	if !m.selectedAgentIDs[id] {
+		t.Error("space did not reselect the agent")
+	}
+	if m.inputText != "" {
+		t.Errorf("agent-panel space leaked into the input: %q", m.inputText)
+	}
+}
+
+func TestSpaceInsertsSingleCharacter(t *testing.T) {
+	m := NewChatroomModel()
+	// The real terminal driver populates Runes alongside KeySpace.
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = model.(ChatroomModel)
+	if m.inputText != " " {
+		t.Errorf("inputText = %q, want a single space", m.inputText)
+	}
+}
+
+func TestTabReturnsFocusToInput(t *testing.T) {
+	m := NewChatroomModel()
+	for i := 0; i < 4; i++ {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = model.(ChatroomModel)
+	}
+	if m.focus != "input" {
+		t.Fatalf("focus = %q, want input", m.focus)
+	}
+	m = send(t, m, "typing works again")
+	if len(m.messages) != 1 {
+		t.Error("input focus did not come back")
+	}
+}
+
+// focusTypes tabs from the input to the conversation-type list.
+func focusTypes(t *testing.T, m ChatroomModel) ChatroomModel {
+	t.Helper()
+	for i := 0; i < 2; i++ {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = model.(ChatroomModel)
+	}
+	if m.focus != "types" {
+		t.Fatalf("focus = %q, want types", m.focus)
+	}
+	return m
+}
+
+func TestSelectEnsembleConversationType(t *testing.T) {
+	m := NewChatroomModel()
+	m = focusTypes(t, m)
+	for m.convTypes[m.typeCursor] != "ensemble" {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = model.(ChatroomModel)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if m.errorText != "" {
+		t.Fatalf("unexpected error: %s", m.errorText)
+	}
+	if got := m.conversations[m.activeConvID].Type; got != "ensemble" {
+		t.Errorf("active conversation type = %q, want ensemble", got)
+	}
+	state, err := m.convManager.GetActiveConversation()
+	if err != nil {
+		t.Fatalf("GetActiveConversation: %v", err)
+	}
+	if string(state.Type) != "ensemble" {
+		t.Errorf("managed conversation type = %q", state.Type)
+	}
+	if len(state.Participants) != len(m.selectedAgents()) {
+		t.Errorf("participants = %v", state.Participants)
+	}
+}
+
+func TestConversationTypeParticipantMinimum(t *testing.T) {
+	m := NewChatroomModel()
+	// Deselect everyone so structured types cannot be formed.
+	for id := range m.selectedAgentIDs {
+		m.selectedAgentIDs[id] = false
+	}
+	m = focusTypes(t, m)
+	for m.convTypes[m.typeCursor] != "debate" {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = model.(ChatroomModel)
+	}
+	before := m.conversations[m.activeConvID].Type
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if m.errorText == "" {
+		t.Fatal("expected a participant-minimum error")
+	}
+	if got := m.conversations[m.activeConvID].Type; got != before {
+		t.Errorf("type changed to %q despite the error", got)
+	}
+	if !strings.Contains(m.renderConversationPanel(), m.styles.errText.Render(m.errorText)) {
+		t.Error("error not rendered in the error style")
+	}
+}
+
+// focusProviders tabs from the input to the provider list.
+func focusProviders(t *testing.T, m ChatroomModel) ChatroomModel {
+	t.Helper()
+	for i := 0; i < 3; i++ {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = model.(ChatroomModel)
+	}
+	if m.focus != "providers" {
+		t.Fatalf("focus = %q, want providers", m.focus)
+	}
+	return m
+}
+
+func TestSwitchProviderResolvesModels(t *testing.T) {
+	m := NewChatroomModel()
+	m.providerModels = func(id string) ([]string, error) {
+		if id != "ollama" {
+			t.Errorf("resolved models for %q", id)
+		}
+		return []string{"llama3", "mistral"}, nil
+	}
+	m = focusProviders(t, m)
+	for m.providers[m.providerCursor] != "ollama" {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = model.(ChatroomModel)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if m.activeProvider != "ollama" {
+		t.Errorf("activeProvider = %q, want ollama", m.activeProvider)
+	}
+	if len(m.availableModels) != 2 {
+		t.Errorf("availableModels = %v", m.availableModels)
+	}
+	if m.errorText != "" {
+		t.Errorf("unexpected error: %s", m.errorText)
+	}
+}
+
+func TestSwitchProviderRejectsUnavailable(t *testing.T) {
+	m := NewChatroomModel()
+	m.providerModels = func(id string) ([]string, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+	m = focusProviders(t, m)
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = model.(ChatroomModel)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if m.activeProvider != "openrouter" {
+		t.Errorf("activeProvider changed to %q despite the error", m.activeProvider)
+	}
+	if m.errorText == "" {
+		t.Error("expected an inline error")
+	}
+}
+
+func TestSwitchProviderRejectsEmptyModelList(t *testing.T) {
+	m := NewChatroomModel()
+	m.providerModels = func(id string) ([]string, error) { return nil, nil }
+	m = focusProviders(t, m)
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = model.(ChatroomModel)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if m.activeProvider != "openrouter" {
+		t.Errorf("provider with no models became active: %q", m.activeProvider)
+	}
+	if m.errorText == "" {
+		t.Error("expected an inline error for an empty model list")
+	}
+}
+
+func TestFormatMessageTime(t *testing.T) {
+	now := time.Date(2026, 8, 29, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		at   time.Time
+		mode TimeMode
+		want string
+	}{
+		{now.Add(-30 * time.Second), TimeAbsolute, "2026-08-29 11:59"},
+		{now.Add(-30 * time.Second), TimeRelative, "30s ago"},
+		{now.Add(-3 * time.Minute), TimeRelative, "3m ago"},
+		{now.Add(-5 * time.Hour), TimeRelative, "5h ago"},
+		{now.Add(-49 * time.Hour), TimeRelative, "2d ago"},
+		// Future timestamps fall back to absolute.
+		{now.Add(time.Minute), TimeRelative, "2026-08-29 12:01"},
+	}
+	for _, tc := range cases {
+		if got := formatMessageTime(tc.at, tc.mode, now); got != tc.want {
+			t.Errorf("formatMessageTime(%v, %v) = %q, want %q", tc.at, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestTimeModeToggleKeybind(t *testing.T) {
+	m := NewChatroomModel()
+	if m.timeMode != TimeAbsolute {
+		t.Fatalf("default timeMode = %v", m.timeMode)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = model.(ChatroomModel)
+	if m.timeMode != TimeRelative {
+		t.Error("Ctrl+R did not switch to relative timestamps")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = model.(ChatroomModel)
+	if m.timeMode != TimeAbsolute {
+		t.Error("Ctrl+R did not switch back to absolute timestamps")
+	}
+}
+
+func TestUnreadBadgeWhileScrolledUp(t *testing.T) {
+	m := NewChatroomModel()
+	for i := 0; i < 15; i++ {
+		m = send(t, m, fmt.Sprintf("message %d", i))
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m = model.(ChatroomModel)
+
+	m = m.addIncoming(ChatMsg{ID: newConvID(), ConvID: m.activeConvID, Sender: "coder", Content: "done", Timestamp: time.Now(), FromAgent: true})
+	m = m.addIncoming(ChatMsg{ID: newConvID(), ConvID: m.activeConvID, Sender: "analyst", Content: "ok", Timestamp: time.Now(), FromAgent: true})
+
+	if m.unreadCount != 2 {
+		t.Fatalf("unreadCount = %d, want 2", m.unreadCount)
+	}
+	if !strings.Contains(m.renderConversationPanel(), "▼ 2 new") {
+		t.Error("unread badge not rendered")
+	}
+}
+
+func TestJumpToLatestClearsUnread(t *testing.T) {
+	m := NewChatroomModel()
+	for i := 0; i < 5; i++ {
+		m = send(t, m, fmt.Sprintf("message %d", i))
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m = model.(ChatroomModel)
+	m = m.addIncoming(ChatMsg{ID: newConvID(), ConvID: m.activeConvID, Sender: "coder", Content: "late", Timestamp: time.Now(), FromAgent: true})
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	m = model.(ChatroomModel)
+	if m.scrollOffset != 0 || m.unreadCount != 0 {
+		t.Errorf("scrollOffset = %d, unreadCount = %d after jump", m.scrollOffset, m.unreadCount)
+	}
+	if strings.Contains(m.renderConversationPanel(), "new") && strings.Contains(m.renderConversationPanel(), "▼") {
+		t.Error("badge still rendered after jumping to latest")
+	}
+}
+
+func TestAutoScrollAtBottom(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "first")
+	m = m.addIncoming(ChatMsg{ID: newConvID(), ConvID: m.activeConvID, Sender: "coder", Content: "fresh reply", Timestamp: time.Now(), FromAgent: true})
+
+	if m.unreadCount != 0 {
+		t.Errorf("message at the bottom counted as unread: %d", m.unreadCount)
+	}
+	if !strings.Contains(m.renderConversationPanel(), "fresh reply") {
+		t.Error("latest message not visible at the bottom")
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Command
+		wantErr bool
+	}{
+		{"/clear", Command{Name: "clear"}, false},
+		{"/agents", Command{Name: "agents"}, false},
+		{"/type ensemble", Command{Name: "type", Arg: "ensemble"}, false},
+		{"/provider ollama", Command{Name: "provider", Arg: "ollama"}, false},
+		{"/save", Command{Name: "save"}, false},
+		{"/help", Command{Name: "help"}, false},
+		{"/type", Command{}, true},
+		{"/clear now", Command{}, true},
+		{"/frobnicate", Command{}, true},
+		{"plain text", Command{}, true},
+	}
+	for _, tc := range cases {
+		got, err := parseCommand(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseCommand(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && got != tc.want {
+			t.Errorf("parseCommand(%q) = %+v, want %+v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestClearCommandDropsActiveMessages(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "one")
+	m = send(t, m, "two")
+	m = send(t, m, "/clear")
+
+	if got := len(m.activeMessages()); got != 0 {
+		t.Errorf("messages after /clear = %d", got)
+	}
+}
+
+func TestTypeCommandSwitchesConversationType(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "/type ensemble")
+
+	if m.errorText != "" {
+		t.Fatalf("unexpected error: %s", m.errorText)
+	}
+	if got := m.conversations[m.activeConvID].Type; got != "ensemble" {
+		t.Errorf("conversation type = %q, want ensemble", got)
+	}
+}
+
+func TestProviderCommandSwitchesProvider(t *testing.T) {
+	m := NewChatroomModel()
+	m.providerModels = func(id string) ([]string, error) { return []string{"llama3"}, nil }
+	m = send(t, m, "/provider ollama")
+
+	if m.activeProvider != "ollama" {
+		t.Errorf("activeProvider = %q, want ollama", m.activeProvider)
+	}
+}
+
+func TestAgentsCommandFocusesPanel(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "/agents")
+	if m.focus != "agents" {
+		t.Errorf("focus = %q, want agents", m.focus)
+	}
+}
+
+func TestHelpCommandShowsUsage(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "/help")
+	msgs := m.activeMessages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0].Content, "/provider") {
+		t.Errorf("help output missing: %v", msgs)
+	}
+}
+
+func TestUnknownCommandShowsInlineError(t *testing.T) {
+	m := NewChatroomModel()
+	m = send(t, m, "/bogus")
+	if m.errorText == "" {
+		t.Fatal("expected an inline error")
+	}
+	if len(m.activeMessages()) != 0 {
+		t.Error("failed command was sent as a message")
+	}
+	if m.inputText != "" {
+		t.Errorf("input not cleared: %q", m.inputText)
+	}
+}
+
+// withMessages fills the active conversation with n numbered messages.
+func withMessages(m ChatroomModel, n int) ChatroomModel {
+	for i := 0; i < n; i++ {
+		m = m.addIncoming(ChatMsg{
+			ID:        newConvID(),
+			ConvID:    m.activeConvID,
+			Sender:    "coder",
+			Content:   fmt.Sprintf("msg %d", i),
+			Timestamp: time.Now(),
+			FromAgent: true,
+		})
+	}
+	return m
+}
+
+func TestScrollClampedWithNoMessages(t *testing.T) {
+	m := NewChatroomModel()
+	for _, key := range []tea.KeyType{tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd} {
+		model, _ := m.Update(tea.KeyMsg{Type: key})
+		m = model.(ChatroomModel)
+		if m.scrollOffset != 0 {
+			t.Errorf("scrollOffset = %d after %v with no messages", m.scrollOffset, key)
+		}
+	}
+}
+
+func TestScrollClampedWithFewMessages(t *testing.T) {
+	// Five messages all fit in the panel, so there is nothing to scroll.
+	m := withMessages(NewChatroomModel(), 5)
+	for i := 0; i < 10; i++ {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+		m = model.(ChatroomModel)
+	}
+	if m.scrollOffset != 0 {
+		t.Errorf("scrollOffset = %d, want 0 when everything fits", m.scrollOffset)
+	}
+}
+
+func TestScrollClampedWithManyMessages(t *testing.T) {
+	m := withMessages(NewChatroomModel(), 50)
+	wantMax := 50 - m.visibleMessageCount()
+
+	for i := 0; i < 100; i++ {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+		m = model.(ChatroomModel)
+	}
+	if m.scrollOffset != wantMax {
+		t.Errorf("scrollOffset = %d, want clamped to %d", m.scrollOffset, wantMax)
+	}
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	m = model.(ChatroomModel)
+	if m.scrollOffset != wantMax {
+		t.Errorf("Home scrollOffset = %d, want %d", m.scrollOffset, wantMax)
+	}
+	if !strings.Contains(m.renderConversationPanel(), "msg 0") {
+		t.Error("oldest message not visible at the top")
+	}
+
+	for i := 0; i < 100; i++ {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		m = model.(ChatroomModel)
+	}
+	if m.scrollOffset != 0 {
+		t.Errorf("scrollOffset = %d after scrolling past the bottom", m.scrollOffset)
+	}
+}
+
+func TestWrapTextWordBoundaries(t *testing.T) {
+	got := wrapText("the quick brown fox jumps over the lazy dog", 15)
+	for i, line := range got {
+		if len(line) > 15 {
+			t.Errorf("line %d too wide: %q", i, line)
+		}
+	}
+	if rejoined := strings.Join(got, " "); rejoined != "the quick brown fox jumps over the lazy dog" {
+		t.Errorf("words lost or reordered: %q", rejoined)
+	}
+}
+
+func TestWrapTextHardBreaksLongTokens(t *testing.T) {
+	got := wrapText("see https://example.com/a/very/long/path/that/never/ends ok", 20)
+	for i, line := range got {
+		if len(line) > 20 {
+			t.Errorf("line %d too wide: %q", i, line)
+		}
+	}
+	if !strings.Contains(strings.Join(got, ""), "https://example.com/") {
+		t.Error("long token content lost")
+	}
+}
+
+func TestWrapTextPreservesNewlines(t *testing.T) {
+	got := wrapText("first\n\nsecond", 40)
+	want := []string{"first", "", "second"}
+	if len(got) != len(want) {
+		t.Fatalf("wrapText = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWrapTextZeroWidthDisablesWrapping(t *testing.T) {
+	got := wrapText("no wrapping applied here", 0)
+	if len(got) != 1 || got[0] != "no wrapping applied here" {
+		t.Errorf("wrapText = %v", got)
+	}
+}
+
+func TestConversationPanelIndentsWrappedLines(t *testing.T) {
+	m := NewChatroomModel()
+	m.renderMarkdown = false
+	m.width = 60
+	m = send(t, m, "a message long enough that it cannot fit on a single panel line at this width")
+
+	// Continuation lines sit inside the panel border, indented past the
+	// timestamp/sender prefix.
+	indented := false
+	for _, line := range strings.Split(m.renderConversationPanel(), "\n") {
+		body := strings.Trim(line, "│")
+		if strings.HasPrefix(body, "         ") && strings.TrimSpace(body) != "" {
+			indented = true
+			break
+		}
+	}
+	if !indented {
+		t.Error("continuation lines not indented under the prefix")
+	}
+}
+
+func TestTooSmallViewReportsRequiredSize(t *testing.T) {
+	m := NewChatroomModel()
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 50, Height: 10})
+	m = model.(ChatroomModel)
+
+	minW, minH := m.minSize()
+	view := m.View()
+	if !strings.Contains(view, "Terminal too small") {
+		t.Fatal("too-small notice not rendered")
+	}
+	if !strings.Contains(view, "Current: 50x10") {
+		t.Errorf("current size missing from notice:\n%s", view)
+	}
+	if !strings.Contains(view, fmt.Sprintf("Required: %dx%d", minW, minH)) {
+		t.Errorf("required size missing from notice:\n%s", view)
+	}
+}
+
+func TestTooSmallViewClearsOnResize(t *testing.T) {
+	m := NewChatroomModel()
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 50, Height: 10})
+	m = model.(ChatroomModel)
+	if !strings.Contains(m.View(), "Terminal too small") {
+		t.Fatal("expected the too-small notice at 50x10")
+	}
+
+	minW, minH := m.minSize()
+	model, _ = m.Update(tea.WindowSizeMsg{Width: minW, Height: minH})
+	m = model.(ChatroomModel)
+	if strings.Contains(m.View(), "Terminal too small") {
+		t.Error("notice still shown after growing to the minimum size")
+	}
+	if !strings.Contains(m.View(), "Conversation") {
+		t.Error("panels not restored after resize")
+	}
+}
+
+func TestChatroomMinSizeDerivedFromPanels(t *testing.T) {
+	m := NewChatroomModel()
+	minW, _ := m.minSize()
+	want := chatAgentPanelMinWidth + chatConvPanelMinWidth + chatControlPanelMinWidth + 3*chatPanelChrome
+	if minW != want {
+		t.Errorf("minimum width = %d, want %d from panel minimums", minW, want)
+	}
+}
+
+func TestApplyThemeChangesViewColors(t *testing.T) {
+	m := NewChatroomModel()
+	// Color output is stripped without a TTY, so compare the styles
+	// themselves rather than a rendered frame.
+	before := m.styles.title.GetForeground()
+
+	m, err := m.ApplyTheme("high-contrast")
+	if err != nil {
+		t.Fatalf("ApplyTheme: %v", err)
+	}
+	if m.themeName != "high-contrast" {
+		t.Errorf("themeName = %q", m.themeName)
+	}
+	if after := m.styles.title.GetForeground(); after == before {
+		t.Error("switching theme did not change the title color")
+	}
+}
+
+func TestApplyThemeUnknownName(t *testing.T) {
+	m := NewChatroomModel()
+	if _, err := m.ApplyTheme("neon"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
+
+func TestCycleThemeVisitsAllPalettes(t *testing.T) {
+	m := NewChatroomModel()
+	seen := map[string]bool{m.themeName: true}
+	for i := 0; i < len(chatThemeOrder)-1; i++ {
+		m = m.cycleTheme()
+		seen[m.themeName] = true
+	}
+	if len(seen) != len(chatThemeOrder) {
+		t.Errorf("cycled through %d themes, want %d: %v", len(seen), len(chatThemeOrder), seen)
+	}
+	m = m.cycleTheme()
+	if m.themeName != "ocean" {
+		t.Errorf("cycle did not wrap back to ocean, got %q", m.themeName)
+	}
+}
+
+func TestConversationsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "convos.json")
+	m := NewChatroomModel()
+	m = send(t, m, "persist me")
+	m = send(t, m, "and me")
+
+	if err := m.SaveConversations(path); err != nil {
+		t.Fatalf("SaveConversations: %v", err)
+	}
+
+	fresh := NewChatroomModel()
+	loaded, err := fresh.LoadConversations(path)
+	if err != nil {
+		t.Fatalf("LoadConversations: %v", err)
+	}
+	if len(loaded.messages) != 2 {
+		t.Fatalf("loaded %d messages, want 2", len(loaded.messages))
+	}
+	if loaded.messages[0].Content != "persist me" {
+		t.Errorf("content = %q", loaded.messages[0].Content)
+	}
+	if loaded.activeConvID != m.activeConvID {
+		t.Errorf("activeConvID = %q, want %q", loaded.activeConvID, m.activeConvID)
+	}
+	if _, ok := loaded.conversations[loaded.activeConvID]; !ok {
+		t.Error("active conversation missing after load")
+	}
+}
+
+func TestLoadConversationsRepairsActiveID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "convos.json")
+	m := NewChatroomModel()
+	saved := m.activeConvID
+	m.activeConvID = "gone"
+	if err := m.SaveConversations(path); err != nil {
+		t.Fatalf("SaveConversations: %v", err)
+	}
+
+	loaded, err := NewChatroomModel().LoadConversations(path)
+	if err != nil {
+		t.Fatalf("LoadConversations: %v", err)
+	}
+	if loaded.activeConvID != saved {
+		t.Errorf("activeConvID = %q, want surviving conversation %q", loaded.activeConvID, saved)
+	}
+}
+
+func TestLoadConversationsMissingFile(t *testing.T) {
+	m := NewChatroomModel()
+	if _, err := m.LoadConversations(filepath.Join(t.TempDir(), "absent.json")); err == nil {
+		t.Error("expected an error for a missing save file")
+	}
+}
+
+func TestViewJoinsAllThreePanels(t *testing.T) {
+	m := NewChatroomModel()
+	view := m.View()
+	for _, title := range []string{"Agents", "Conversation", "Controls"} {
+		if !strings.Contains(view, title) {
+			t.Errorf("view missing %q panel", title)
+		}
+	}
+}
+
+func TestJoinColumnsKeepsAllColumns(t *testing.T) {
+	joined := joinColumns("left", "center", "right")
+	for _, want := range []string{"left", "center", "right"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("joined output missing %q", want)
+		}
+	}
+}
+
+func TestSendMessageIgnoresEmptyInput(t *testing.T) {
+	m := NewChatroomModel()
+	m = typeInto(t, m, "   ")
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ChatroomModel)
+
+	if len(m.messages) != 0 {
+		t.Fatalf("blank input produced %d messages", len(m.messages))
+	}
+}
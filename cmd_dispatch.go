@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ==================== SUBCOMMAND DISPATCH ====================
+
+// commandTable maps subcommand names to their entry points. Every demo
+// that used to be its own main() lives here behind one binary.
+func commandTable() map[string]func() error {
+	return map[string]func() error{
+		"tui":             RunTUI,
+		"chatroom":        RunChatroom,
+		"ai":              RunAI,
+		"evolving":        RunEvolving,
+		"simple":          RunSimple,
+		"agents":          RunAgents,
+		"files":           RunFiles,
+		"convo-test":      RunConvoTest,
+		"openrouter-test": RunOpenRouterTest,
+		"run-tests":       RunTests,
+	}
+}
+
+// usageText lists the available subcommands for error output and --help.
+func usageText() string {
+	var names []string
+	for name := range commandTable() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "Usage: go-tui-app [--headless] <command>\n\nCommands:\n  " + strings.Join(names, "\n  ")
+}
+
+// runSubcommand dispatches the first argument to its entry point. With
+// no arguments the full TUI runs, preserving the old default behavior.
+func runSubcommand(args []string) error {
+	if len(args) == 0 {
+		return RunTUI()
+	}
+	name := args[0]
+	if name == "--help" || name == "help" {
+		fmt.Println(usageText())
+		return nil
+	}
+	cmd, ok := commandTable()[name]
+	if !ok {
+		return fmt.Errorf("unknown subcommand %q\n\n%s", name, usageText())
+	}
+	return cmd()
+}
+
+// RunChatroom starts the multi-agent chatroom UI.
+func RunChatroom() error {
+	p := tea.NewProgram(NewChatroomModel(), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error starting chatroom: %w", err)
+	}
+	return nil
+}
+
+// RunAgents initializes the agent roster and dumps each agent's status
+// as JSON, without starting any workers.
+func RunAgents() error {
+	am := NewAgentManager()
+	if err := am.initializeAgents(); err != nil {
+		return fmt.Errorf("failed to initialize agents: %w", err)
+	}
+	agents := am.GetActiveAgents()
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AgentID < agents[j].AgentID })
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(agents)
+}
+
+// RunFiles lists the shared files currently in the file store.
+func RunFiles() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	fm, err := NewFileManager(filepath.Join(home, ".go-tui-files"))
+	if err != nil {
+		return fmt.Errorf("failed to open file store: %w", err)
+	}
+	files := fm.ListFiles(nil)
+	if len(files) == 0 {
+		fmt.Println("No shared files.")
+		return nil
+	}
+	for _, f := range files {
+		fmt.Printf("%s  %d bytes  %s\n", f.Name, f.Size, f.Owner)
+	}
+	return nil
+}
+
+// RunConvoTest runs one scripted round of each conversation type and
+// prints the outcome, as a quick end-to-end check of the manager.
+func RunConvoTest() error {
+	cm := NewConversationManager()
+	participants := []string{"coordinator", "coder", "analyst"}
+	for _, cfg := range []string{"democratic", "hierarchical", "debate", "consensus"} {
+		convID := newConvID()
+		if err := cm.CreateConversation(convID, cfg, participants); err != nil {
+			return fmt.Errorf("%s: %w", cfg, err)
+		}
+		for _, id := range participants {
+			msg := ConvoMessage{
+				ID:        newConvID(),
+				Sender:    id,
+				Content:   fmt.Sprintf("%s checking in", id),
+				Timestamp: time.Now(),
+			}
+			if err := cm.AddMessage(convID, msg); err != nil {
+				return fmt.Errorf("%s: %w", cfg, err)
+			}
+		}
+		state, err := cm.GetConversation(convID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cfg, err)
+		}
+		fmt.Printf("%s: %d messages, %d participants\n", cfg, len(state.Messages), len(state.Participants))
+	}
+	return nil
+}
+
+// RunOpenRouterTest checks OpenRouter connectivity by listing models.
+func RunOpenRouterTest() error {
+	apiKey := os.Getenv("OPENROUTER_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("OPENROUTER_API_KEY is not set")
+	}
+	client := NewOpenRouterClient(OpenRouterConfig{APIKey: apiKey})
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	models, err := client.GetModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+	fmt.Printf("OpenRouter reachable, %d models available.\n", len(models))
+	return nil
+}
+
+// RunTests exercises the headless pipeline as a smoke test of the core
+// managers working together.
+func RunTests() error {
+	return runHeadless(os.Stdout)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnknownSubcommandReturnsUsage(t *testing.T) {
+	err := runSubcommand([]string{"frobnicate"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown subcommand")
+	}
+	if !strings.Contains(err.Error(), `unknown subcommand "frobnicate"`) {
+		t.Errorf("error does not name the bad subcommand: %v", err)
+	}
+	if !strings.Contains(err.Error(), "Usage:") {
+		t.Errorf("error does not include usage: %v", err)
+	}
+}
+
+func TestUsageListsEveryCommand(t *testing.T) {
+	usage := usageText()
+	for name := range commandTable() {
+		if !strings.Contains(usage, name) {
+			t.Errorf("usage missing %q", name)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ==================== COLLABORATION MODELS ====================
+
+// Collaborative operation kinds.
+const (
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// CollabChange is one edit against a session's content. BaseVersion is the
+// session version the client had when producing the change; the manager
+// transforms the change against anything committed since.
+type CollabChange struct {
+	ID          string    `json:"id"`
+	SessionID   string    `json:"session_id"`
+	UserID      string    `json:"user_id"`
+	Op          string    `json:"op"` // "insert" or "delete"
+	Position    int       `json:"position"`
+	Text        string    `json:"text"`
+	BaseVersion int       `json:"base_version"`
+	Version     int       `json:"version"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// CursorPos is one collaborator's cursor location.
+type CursorPos struct {
+	Line      int       `json:"line"`
+	Col       int       `json:"col"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CollabSession is one shared editing session.
+type CollabSession struct {
+	ID           string               `json:"id"`
+	Content      string               `json:"content"`
+	Version      int                  `json:"version"`
+	Participants []string             `json:"participants"`
+	Changes      []CollabChange       `json:"changes"`
+	Cursors      map[string]CursorPos `json:"cursors,omitempty"`
+	IsActive     bool                 `json:"is_active"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+// CollabEvent is emitted on notable session activity.
+type CollabEvent struct {
+	Type      string                 `json:"type"`
+	SessionID string                 `json:"session_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// ==================== COLLAB MANAGER ====================
+
+// CollabManager owns collaborative editing sessions and merges concurrent
+// changes with a minimal operational-transform layer.
+type CollabManager struct {
+	mu       sync.RWMutex
+	sessions map[string]*CollabSession
+	events   []CollabEvent
+
+	// MaxUndoDepth bounds each user's undo history per session.
+	MaxUndoDepth int
+
+	undoStacks map[string]map[string][]CollabChange // sessionID -> userID -> stack
+	redoStacks map[string]map[string][]CollabChange
+}
+
+// defaultUndoDepth bounds undo history unless configured otherwise.
+const defaultUndoDepth = 100
+
+// NewCollabManager returns an empty manager.
+func NewCollabManager() *CollabManager {
+	return &CollabManager{
+		sessions:     make(map[string]*CollabSession),
+		MaxUndoDepth: defaultUndoDepth,
+		undoStacks:   make(map[string]map[string][]CollabChange),
+		redoStacks:   make(map[string]map[string][]CollabChange),
+	}
+}
+
+// CreateSession starts a new session with the given initial content.
+func (cb *CollabManager) CreateSession(sessionID, content string, participants []string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if _, exists := cb.sessions[sessionID]; exists {
+		return fmt.Errorf("session %s already exists", sessionID)
+	}
+	cb.sessions[sessionID] = &CollabSession{
+		ID:           sessionID,
+		Content:      content,
+		Participants: participants,
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+	}
+	return nil
+}
+
+// JoinSession adds a participant to an active session.
+func (cb *CollabManager) JoinSession(sessionID, userID string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if !session.IsActive {
+		return fmt.Errorf("session %s is closed", sessionID)
+	}
+	for _, p := range session.Participants {
+		if p == userID {
+			return nil
+		}
+	}
+	session.Participants = append(session.Participants, userID)
+	return nil
+}
+
+// GetSession returns the session for sessionID.
+func (cb *CollabManager) GetSession(sessionID string) (*CollabSession, error) {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	return session, nil
+}
+
+// GetActiveSessions lists sessions that are still open.
+func (cb *CollabManager) GetActiveSessions() []*CollabSession {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	var active []*CollabSession
+	for _, session := range cb.sessions {
+		if session.IsActive {
+			active = append(active, session)
+		}
+	}
+	return active
+}
+
+// ==================== CURSOR PRESENCE ====================
+
+// UpdateCursor records where userID's cursor is so other clients can show
+// collaborator presence. Emits a cursor_moved event.
+func (cb *CollabManager) UpdateCursor(sessionID, userID string, line, col int) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if !session.IsActive {
+		return fmt.Errorf("session %s is closed", sessionID)
+	}
+	if session.Cursors == nil {
+		session.Cursors = make(map[string]CursorPos)
+	}
+	session.Cursors[userID] = CursorPos{Line: line, Col: col, UpdatedAt: time.Now()}
+	cb.emitEventLocked("cursor_moved", sessionID, map[string]interface{}{
+		"user": userID,
+		"line": line,
+		"col":  col,
+	})
+	return nil
+}
+
+// GetCursors returns a copy of every collaborator's cursor position.
+func (cb *CollabManager) GetCursors(sessionID string) (map[string]CursorPos, error) {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	cursors := make(map[string]CursorPos, len(session.Cursors))
+	for user, pos := range session.Cursors {
+		cursors[user] = pos
+	}
+	return cursors, nil
+}
+
+// LeaveSession removes a participant and prunes their cursor. When the
+// last participant leaves, the session is closed and a
+// collab_session_closed event is emitted; closed sessions no longer appear
+// in GetActiveSessions.
+func (cb *CollabManager) LeaveSession(sessionID, userID string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	for i, p := range session.Participants {
+		if p != userID {
+			continue
+		}
+		session.Participants = append(session.Participants[:i], session.Participants[i+1:]...)
+		delete(session.Cursors, userID)
+		if len(session.Participants) == 0 {
+			session.IsActive = false
+			cb.emitEventLocked("collab_session_closed", sessionID, map[string]interface{}{
+				"last_participant": userID,
+			})
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not in session %s", userID, sessionID)
+}
+
+// ==================== OPERATIONAL TRANSFORM ====================
+
+// transform rewrites change a so it can apply after concurrent change b,
+// and vice versa. Concurrent inserts at the same position are ordered by
+// UserID so every replica resolves them identically.
+func transform(a, b CollabChange) (CollabChange, CollabChange) {
+	switch {
+	case a.Op == OpInsert && b.Op == OpInsert:
+		if a.Position < b.Position || (a.Position == b.Position && a.UserID < b.UserID) {
+			b.Position += len(a.Text)
+		} else {
+			a.Position += len(b.Text)
+		}
+	case a.Op == OpInsert && b.Op == OpDelete:
+		if a.Position <= b.Position {
+			b.Position += len(a.Text)
+		} else if a.Position >= b.Position+len(b.Text) {
+			a.Position -= len(b.Text)
+		} else {
+			// Insert lands inside the deleted range: move it to the cut
+			a.Position = b.Position
+		}
+	case a.Op == OpDelete && b.Op == OpInsert:
+		b2, a2 := transform(b, a)
+		return a2, b2
+	case a.Op == OpDelete && b.Op == OpDelete:
+		if a.Position >= b.Position+len(b.Text) {
+			a.Position -= len(b.Text)
+		} else if b.Position >= a.Position+len(a.Text) {
+			b.Position -= len(a.Text)
+		} else {
+			// Overlapping deletes: each keeps only the part of its
+			// range the other did not already remove. Both trims are
+			// computed from the original positions before either
+			// change is reassigned.
+			aEnd := a.Position + len(a.Text)
+			bEnd := b.Position + len(b.Text)
+			if a.Position <= b.Position {
+				overlap := minInt(aEnd, bEnd) - b.Position
+				keep := b.Position - a.Position
+				a.Text = a.Text[:keep] + a.Text[minInt(keep+overlap, len(a.Text)):]
+				b.Text = b.Text[minInt(overlap, len(b.Text)):]
+				b.Position = a.Position
+			} else {
+				overlap := minInt(bEnd, aEnd) - a.Position
+				keep := a.Position - b.Position
+				b.Text = b.Text[:keep] + b.Text[minInt(keep+overlap, len(b.Text)):]
+				a.Text = a.Text[minInt(overlap, len(a.Text)):]
+				a.Position = b.Position
+			}
+		}
+	}
+	return a, b
+}
+
+// applyToContent mutates content with one change, validating bounds and,
+// for deletes, that the removed text matches what the change claims.
+func applyToContent(content string, c CollabChange) (string, error) {
+	switch c.Op {
+	case OpInsert:
+		if c.Position < 0 || c.Position > len(content) {
+			return content, fmt.Errorf("insert position %d out of range (len %d)", c.Position, len(content))
+		}
+		return content[:c.Position] + c.Text + content[c.Position:], nil
+	case OpDelete:
+		end := c.Position + len(c.Text)
+		if c.Position < 0 || end > len(content) {
+			return content, fmt.Errorf("delete range [%d,%d) out of range (len %d)", c.Position, end, len(content))
+		}
+		if got := content[c.Position:end]; got != c.Text {
+			return content, fmt.Errorf("delete mismatch at %d: content has %q, change expects %q", c.Position, got, c.Text)
+		}
+		return content[:c.Position] + content[end:], nil
+	default:
+		return content, fmt.Errorf("unknown operation %q", c.Op)
+	}
+}
+
+// ApplyChange transforms the incoming change against every change
+// committed since the client's base version, applies it to the session
+// content, and commits it at the next version.
+func (cb *CollabManager) ApplyChange(sessionID string, change CollabChange) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if !session.IsActive {
+		return fmt.Errorf("session %s is closed", sessionID)
+	}
+	if change.BaseVersion < 0 || change.BaseVersion > session.Version {
+		return fmt.Errorf("base version %d is ahead of session version %d", change.BaseVersion, session.Version)
+	}
+
+	// Rebase over everything committed since the client's snapshot
+	for _, committed := range session.Changes {
+		if committed.Version <= change.BaseVersion {
+			continue
+		}
+		change, _ = transform(change, committed)
+	}
+
+	updated, err := applyToContent(session.Content, change)
+	if err != nil {
+		return err
+	}
+
+	session.Content = updated
+	session.Version++
+	change.SessionID = sessionID
+	change.Version = session.Version
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+	session.Changes = append(session.Changes, change)
+	cb.pushUndoLocked(sessionID, change)
+	return nil
+}
+
+// ==================== UNDO / REDO ====================
+
+// pushUndoLocked records an applied change for undo and clears the user's
+// redo history, since a fresh edit invalidates it. Callers hold cb.mu.
+func (cb *CollabManager) pushUndoLocked(sessionID string, change CollabChange) {
+	if cb.undoStacks[sessionID] == nil {
+		cb.undoStacks[sessionID] = make(map[string][]CollabChange)
+	}
+	stack := append(cb.undoStacks[sessionID][change.UserID], change)
+	if len(stack) > cb.MaxUndoDepth {
+		stack = stack[len(stack)-cb.MaxUndoDepth:]
+	}
+	cb.undoStacks[sessionID][change.UserID] = stack
+
+	if cb.redoStacks[sessionID] != nil {
+		delete(cb.redoStacks[sessionID], change.UserID)
+	}
+}
+
+// invertChange produces the change that reverses c.
+func invertChange(c CollabChange) CollabChange {
+	inverse := c
+	if c.Op == OpInsert {
+		inverse.Op = OpDelete
+	} else {
+		inverse.Op = OpInsert
+	}
+	return inverse
+}
+
+// commitLocked applies a change to the session content and version without
+// touching the undo stacks. Callers hold cb.mu.
+func (cb *CollabManager) commitLocked(session *CollabSession, change CollabChange) error {
+	updated, err := applyToContent(session.Content, change)
+	if err != nil {
+		return err
+	}
+	session.Content = updated
+	session.Version++
+	change.SessionID = session.ID
+	change.Version = session.Version
+	change.Timestamp = time.Now()
+	session.Changes = append(session.Changes, change)
+	return nil
+}
+
+// Undo reverses userID's most recent change in the session, moving it onto
+// the redo stack.
+func (cb *CollabManager) Undo(sessionID, userID string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	stack := cb.undoStacks[sessionID][userID]
+	if len(stack) == 0 {
+		return fmt.Errorf("nothing to undo for %s in %s", userID, sessionID)
+	}
+	change := stack[len(stack)-1]
+
+	if err := cb.commitLocked(session, invertChange(change)); err != nil {
+		return fmt.Errorf("cannot undo: %w", err)
+	}
+	cb.undoStacks[sessionID][userID] = stack[:len(stack)-1]
+
+	if cb.redoStacks[sessionID] == nil {
+		cb.redoStacks[sessionID] = make(map[string][]CollabChange)
+	}
+	cb.redoStacks[sessionID][userID] = append(cb.redoStacks[sessionID][userID], change)
+	return nil
+}
+
+// Redo reapplies userID's most recently undone change.
+func (cb *CollabManager) Redo(sessionID, userID string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	session, ok := cb.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	stack := cb.redoStacks[sessionID][userID]
+	if len(stack) == 0 {
+		return fmt.Errorf("nothing to redo for %s in %s", userID, sessionID)
+	}
+	change := stack[len(stack)-1]
+
+	if err := cb.commitLocked(session, change); err != nil {
+		return fmt.Errorf("cannot redo: %w", err)
+	}
+	cb.redoStacks[sessionID][userID] = stack[:len(stack)-1]
+
+	if cb.undoStacks[sessionID] == nil {
+		cb.undoStacks[sessionID] = make(map[string][]CollabChange)
+	}
+	cb.undoStacks[sessionID][userID] = append(cb.undoStacks[sessionID][userID], change)
+	return nil
+}
+
+// emitEventLocked appends an event. Callers hold cb.mu.
+func (cb *CollabManager) emitEventLocked(eventType, sessionID string, data map[string]interface{}) {
+	cb.events = append(cb.events, CollabEvent{
+		Type:      eventType,
+		SessionID: sessionID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// Events returns a copy of the emitted event log.
+func (cb *CollabManager) Events() []CollabEvent {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	out := make([]CollabEvent, len(cb.events))
+	copy(out, cb.events)
+	return out
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"testing"
+)
+
+// Test that concurrent inserts at the same position resolve
+// deterministically regardless of arrival order
+func TestConcurrentInsertsDeterministic(t *testing.T) {
+	makeSession := func(id string) *CollabManager {
+		cb := NewCollabManager()
+		if err := cb.CreateSession(id, "shared", []string{"alice", "bob"}); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		return cb
+	}
+
+	alice := CollabChange{UserID: "alice", Op: OpInsert, Position: 0, Text: "A", BaseVersion: 0}
+	bob := CollabChange{UserID: "bob", Op: OpInsert, Position: 0, Text: "B", BaseVersion: 0}
+
+	// Apply in both orders; the transformed result must agree
+	cb1 := makeSession("s1")
+	if err := cb1.ApplyChange("s1", alice); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+	if err := cb1.ApplyChange("s1", bob); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+
+	cb2 := makeSession("s1")
+	if err := cb2.ApplyChange("s1", bob); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+	if err := cb2.ApplyChange("s1", alice); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+
+	s1, _ := cb1.GetSession("s1")
+	s2, _ := cb2.GetSession("s1")
+	if s1.Content != s2.Content {
+		t.Errorf("Orders diverged: %q vs %q", s1.Content, s2.Content)
+	}
+	if s1.Content != "ABshared" {
+		t.Errorf("Unexpected merged content: %q", s1.Content)
+	}
+}
+
+// Test insert/delete transformation against concurrent history
+func TestApplyChangeTransformsAgainstConcurrentDelete(t *testing.T) {
+	cb := NewCollabManager()
+	if err := cb.CreateSession("s2", "hello world", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// Alice deletes "hello " while Bob, from the same base, appends at the end
+	if err := cb.ApplyChange("s2", CollabChange{UserID: "alice", Op: OpDelete, Position: 0, Text: "hello ", BaseVersion: 0}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cb.ApplyChange("s2", CollabChange{UserID: "bob", Op: OpInsert, Position: 11, Text: "!", BaseVersion: 0}); err != nil {
+		t.Fatalf("Transformed insert failed: %v", err)
+	}
+
+	session, _ := cb.GetSession("s2")
+	if session.Content != "world!" {
+		t.Errorf("Expected \"world!\", got %q", session.Content)
+	}
+	if session.Version != 2 {
+		t.Errorf("Expected version 2, got %d", session.Version)
+	}
+}
+
+// Test that overlapping concurrent deletes trim to the non-overlapping
+// parts instead of corrupting the document
+func TestApplyChangeOverlappingDeletes(t *testing.T) {
+	cb := NewCollabManager()
+	if err := cb.CreateSession("s3", "abcdefg", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// Alice deletes "def" while Bob, from the same base, deletes the
+	// overlapping "cde"
+	if err := cb.ApplyChange("s3", CollabChange{UserID: "alice", Op: OpDelete, Position: 3, Text: "def", BaseVersion: 0}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cb.ApplyChange("s3", CollabChange{UserID: "bob", Op: OpDelete, Position: 2, Text: "cde", BaseVersion: 0}); err != nil {
+		t.Fatalf("Transformed overlapping delete failed: %v", err)
+	}
+
+	session, _ := cb.GetSession("s3")
+	if session.Content != "abg" {
+		t.Errorf("Expected \"abg\", got %q", session.Content)
+	}
+
+	// Mirror overlap: the committed delete starts after the incoming one
+	cb2 := NewCollabManager()
+	if err := cb2.CreateSession("s4", "abcdefg", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := cb2.ApplyChange("s4", CollabChange{UserID: "alice", Op: OpDelete, Position: 2, Text: "cde", BaseVersion: 0}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cb2.ApplyChange("s4", CollabChange{UserID: "bob", Op: OpDelete, Position: 3, Text: "def", BaseVersion: 0}); err != nil {
+		t.Fatalf("Transformed overlapping delete failed: %v", err)
+	}
+	session, _ = cb2.GetSession("s4")
+	if session.Content != "abg" {
+		t.Errorf("Expected \"abg\", got %q", session.Content)
+	}
+
+	// One range fully inside the other
+	cb3 := NewCollabManager()
+	if err := cb3.CreateSession("s5", "abcdefg", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := cb3.ApplyChange("s5", CollabChange{UserID: "alice", Op: OpDelete, Position: 1, Text: "bcdef", BaseVersion: 0}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cb3.ApplyChange("s5", CollabChange{UserID: "bob", Op: OpDelete, Position: 3, Text: "de", BaseVersion: 0}); err != nil {
+		t.Fatalf("Transformed contained delete failed: %v", err)
+	}
+	session, _ = cb3.GetSession("s5")
+	if session.Content != "ag" {
+		t.Errorf("Expected \"ag\", got %q", session.Content)
+	}
+}
+
+// Test that deletes validate the text they remove
+func TestApplyToContentValidation(t *testing.T) {
+	if _, err := applyToContent("abc", CollabChange{Op: OpDelete, Position: 1, Text: "zz"}); err == nil {
+		t.Error("Expected delete mismatch to error")
+	}
+	if _, err := applyToContent("abc", CollabChange{Op: OpInsert, Position: 10, Text: "x"}); err == nil {
+		t.Error("Expected out-of-range insert to error")
+	}
+	out, err := applyToContent("abc", CollabChange{Op: OpDelete, Position: 1, Text: "b"})
+	if err != nil || out != "ac" {
+		t.Errorf("Valid delete failed: %q, %v", out, err)
+	}
+}
+
+// Test multi-user cursor updates and pruning on leave
+func TestCursorPresence(t *testing.T) {
+	cb := NewCollabManager()
+	if err := cb.CreateSession("cur-1", "", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if err := cb.UpdateCursor("cur-1", "alice", 3, 7); err != nil {
+		t.Fatalf("UpdateCursor failed: %v", err)
+	}
+	if err := cb.UpdateCursor("cur-1", "bob", 1, 0); err != nil {
+		t.Fatalf("UpdateCursor failed: %v", err)
+	}
+	// Updates replace, not accumulate
+	if err := cb.UpdateCursor("cur-1", "alice", 4, 2); err != nil {
+		t.Fatalf("UpdateCursor failed: %v", err)
+	}
+
+	cursors, err := cb.GetCursors("cur-1")
+	if err != nil {
+		t.Fatalf("GetCursors failed: %v", err)
+	}
+	if len(cursors) != 2 {
+		t.Fatalf("Expected 2 cursors, got %d", len(cursors))
+	}
+	if pos := cursors["alice"]; pos.Line != 4 || pos.Col != 2 {
+		t.Errorf("alice's cursor = %+v, want line 4 col 2", pos)
+	}
+
+	// Leaving prunes the cursor
+	if err := cb.LeaveSession("cur-1", "bob"); err != nil {
+		t.Fatalf("LeaveSession failed: %v", err)
+	}
+	cursors, _ = cb.GetCursors("cur-1")
+	if _, ok := cursors["bob"]; ok {
+		t.Error("bob's cursor survived leaving the session")
+	}
+
+	moved := 0
+	for _, ev := range cb.Events() {
+		if ev.Type == "cursor_moved" {
+			moved++
+		}
+	}
+	if moved != 3 {
+		t.Errorf("Expected 3 cursor_moved events, got %d", moved)
+	}
+}
+
+// Test insert, undo, redo round-trip on session content
+func TestUndoRedo(t *testing.T) {
+	cb := NewCollabManager()
+	if err := cb.CreateSession("undo-1", "base", []string{"alice"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if err := cb.ApplyChange("undo-1", CollabChange{UserID: "alice", Op: OpInsert, Position: 4, Text: "ball", BaseVersion: 0}); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+	session, _ := cb.GetSession("undo-1")
+	if session.Content != "baseball" {
+		t.Fatalf("After insert: %q", session.Content)
+	}
+
+	if err := cb.Undo("undo-1", "alice"); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	session, _ = cb.GetSession("undo-1")
+	if session.Content != "base" {
+		t.Errorf("After undo: %q, want \"base\"", session.Content)
+	}
+
+	if err := cb.Redo("undo-1", "alice"); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	session, _ = cb.GetSession("undo-1")
+	if session.Content != "baseball" {
+		t.Errorf("After redo: %q, want \"baseball\"", session.Content)
+	}
+
+	// Empty stacks error cleanly
+	if err := cb.Redo("undo-1", "alice"); err == nil {
+		t.Error("Expected empty redo stack to error")
+	}
+	if err := cb.Undo("undo-1", "bob"); err == nil {
+		t.Error("Expected undo with no history to error")
+	}
+}
+
+// Test that a new change clears the redo stack and depth is bounded
+func TestUndoStackRules(t *testing.T) {
+	cb := NewCollabManager()
+	cb.MaxUndoDepth = 2
+	if err := cb.CreateSession("undo-2", "", []string{"alice"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	for i, text := range []string{"a", "b", "c"} {
+		if err := cb.ApplyChange("undo-2", CollabChange{UserID: "alice", Op: OpInsert, Position: i, Text: text, BaseVersion: i}); err != nil {
+			t.Fatalf("ApplyChange failed: %v", err)
+		}
+	}
+
+	// Depth 2: only the last two inserts are undoable
+	if err := cb.Undo("undo-2", "alice"); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if err := cb.Undo("undo-2", "alice"); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if err := cb.Undo("undo-2", "alice"); err == nil {
+		t.Error("Expected undo beyond MaxUndoDepth to error")
+	}
+
+	// A fresh change clears redo
+	session, _ := cb.GetSession("undo-2")
+	if err := cb.ApplyChange("undo-2", CollabChange{UserID: "alice", Op: OpInsert, Position: 0, Text: "z", BaseVersion: session.Version}); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+	if err := cb.Redo("undo-2", "alice"); err == nil {
+		t.Error("Expected redo stack to be cleared by a new change")
+	}
+}
+
+// Test that the session closes when the last participant leaves
+func TestLeaveSessionAutoClose(t *testing.T) {
+	cb := NewCollabManager()
+	if err := cb.CreateSession("leave-1", "", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// Leaving an absent user errors clearly
+	if err := cb.LeaveSession("leave-1", "carol"); err == nil {
+		t.Error("Expected leaving an absent user to error")
+	}
+
+	if err := cb.LeaveSession("leave-1", "alice"); err != nil {
+		t.Fatalf("LeaveSession failed: %v", err)
+	}
+	session, _ := cb.GetSession("leave-1")
+	if !session.IsActive {
+		t.Error("Session closed while a participant remains")
+	}
+
+	if err := cb.LeaveSession("leave-1", "bob"); err != nil {
+		t.Fatalf("LeaveSession failed: %v", err)
+	}
+	session, _ = cb.GetSession("leave-1")
+	if session.IsActive {
+		t.Error("Session should close when the last participant leaves")
+	}
+
+	for _, s := range cb.GetActiveSessions() {
+		if s.ID == "leave-1" {
+			t.Error("Closed session still listed as active")
+		}
+	}
+
+	closed := false
+	for _, ev := range cb.Events() {
+		if ev.Type == "collab_session_closed" && ev.SessionID == "leave-1" {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("Expected a collab_session_closed event")
+	}
+}
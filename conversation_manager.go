@@ -0,0 +1,976 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== CONVERSATION MODELS ====================
+
+// ConversationType selects the interaction pattern for a conversation.
+type ConversationType string
+
+const (
+	ConvDemocratic   ConversationType = "democratic"
+	ConvHierarchical ConversationType = "hierarchical"
+	ConvDebate       ConversationType = "debate"
+	ConvConsensus    ConversationType = "consensus"
+)
+
+// EditEntry records one historical edit of a message.
+type EditEntry struct {
+	OldContent string    `json:"old_content"`
+	NewContent string    `json:"new_content"`
+	EditedBy   string    `json:"edited_by"`
+	Reason     string    `json:"reason,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// ConvoMessage is a single message inside a managed conversation.
+type ConvoMessage struct {
+	ID          string              `json:"id"`
+	Sender      string              `json:"sender"`
+	Content     string              `json:"content"`
+	Timestamp   time.Time           `json:"timestamp"`
+	ParentID    string              `json:"parent_id,omitempty"`
+	ThreadID    string              `json:"thread_id,omitempty"`
+	Votes       map[string]int      `json:"votes,omitempty"`     // userID -> vote value
+	Reactions   map[string][]string `json:"reactions,omitempty"` // emoji -> userIDs
+	Edited      bool                `json:"edited,omitempty"`
+	EditHistory []EditEntry         `json:"edit_history,omitempty"`
+}
+
+// ConversationConfig describes one conversation style's rules. Settings
+// carries per-type knobs such as "consensus_threshold", "voting_timeout",
+// "allow_anonymous" and "max_discussion_time".
+type ConversationConfig struct {
+	Name     string                 `json:"name"`
+	Type     ConversationType       `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// ConversationState is the live state of one conversation.
+type ConversationState struct {
+	ID           string           `json:"id"`
+	ConfigName   string           `json:"config_name"`
+	Type         ConversationType `json:"type"`
+	Participants []string         `json:"participants"`
+	TurnOrder    []string         `json:"turn_order"`
+	CurrentTurn  int              `json:"current_turn"`
+	Messages     []ConvoMessage   `json:"messages"`
+	StartTime    time.Time        `json:"start_time"`
+	Active       bool             `json:"active"`
+	Summary      string           `json:"summary,omitempty"`
+}
+
+// ConversationEvent is emitted on notable state changes (turn_advanced,
+// vote_resolved, message_edited, ...).
+type ConversationEvent struct {
+	Type      string                 `json:"type"`
+	ConvID    string                 `json:"conv_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// ==================== CONVERSATION MANAGER ====================
+
+// AuthProvider abstracts who counts as a moderator and who may join a
+// conversation type, so deployments can plug in their own user model.
+type AuthProvider interface {
+	IsModerator(userID string) bool
+	CanJoin(userID, convType string) bool
+}
+
+// staticAuthProvider is the built-in fallback matching the historical
+// hardcoded role list.
+type staticAuthProvider struct{}
+
+func (staticAuthProvider) IsModerator(userID string) bool {
+	for _, role := range []string{"admin", "moderator", "owner"} {
+		if userID == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (staticAuthProvider) CanJoin(userID, convType string) bool {
+	return userID != ""
+}
+
+// ConversationManager owns every conversation's state and enforces the
+// rules of each conversation type.
+type ConversationManager struct {
+	mu         sync.RWMutex
+	configs    map[string]ConversationConfig
+	states     map[string]*ConversationState
+	activeConv string
+	events     []ConversationEvent
+	voteStarts map[string]time.Time
+	auth       AuthProvider
+}
+
+// NewConversationManager returns a manager with the built-in configs
+// registered.
+func NewConversationManager() *ConversationManager {
+	cm := &ConversationManager{
+		configs:    make(map[string]ConversationConfig),
+		states:     make(map[string]*ConversationState),
+		voteStarts: make(map[string]time.Time),
+		auth:       staticAuthProvider{},
+	}
+	for _, cfg := range []ConversationConfig{
+		{Name: "democratic", Type: ConvDemocratic, Settings: map[string]interface{}{
+			"voting_timeout":  60.0,
+			"allow_anonymous": false,
+		}},
+		{Name: "hierarchical", Type: ConvHierarchical},
+		{Name: "debate", Type: ConvDebate},
+		{Name: "consensus", Type: ConvConsensus, Settings: map[string]interface{}{
+			"consensus_threshold": 0.8,
+			"max_discussion_time": 600.0,
+		}},
+	} {
+		cm.configs[cfg.Name] = cfg
+	}
+	return cm
+}
+
+// RegisterConfig adds or replaces a conversation config.
+func (cm *ConversationManager) RegisterConfig(cfg ConversationConfig) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.configs[cfg.Name] = cfg
+}
+
+// CreateConversation starts a new conversation using the named config. The
+// participant order becomes the initial turn order.
+func (cm *ConversationManager) CreateConversation(convID, configName string, participants []string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cfg, ok := cm.configs[configName]
+	if !ok {
+		return fmt.Errorf("unknown conversation config: %s", configName)
+	}
+	if _, exists := cm.states[convID]; exists {
+		return fmt.Errorf("conversation %s already exists", convID)
+	}
+
+	order := make([]string, len(participants))
+	copy(order, participants)
+	cm.states[convID] = &ConversationState{
+		ID:           convID,
+		ConfigName:   configName,
+		Type:         cfg.Type,
+		Participants: participants,
+		TurnOrder:    order,
+		CurrentTurn:  0,
+		StartTime:    time.Now(),
+		Active:       true,
+	}
+	cm.activeConv = convID
+	return nil
+}
+
+// getConversationLocked looks up convID without touching the lock, for
+// callers that already hold it. Taking cm.mu again here would be a
+// re-entrant RLock, which deadlocks when a writer is queued between the
+// two acquisitions.
+func (cm *ConversationManager) getConversationLocked(convID string) (*ConversationState, error) {
+	state, ok := cm.states[convID]
+	if !ok {
+		return nil, fmt.Errorf("conversation %s not found", convID)
+	}
+	return state, nil
+}
+
+// GetConversation returns the state for convID.
+func (cm *ConversationManager) GetConversation(convID string) (*ConversationState, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.getConversationLocked(convID)
+}
+
+// GetActiveConversation returns the currently focused conversation.
+func (cm *ConversationManager) GetActiveConversation() (*ConversationState, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.activeConv == "" {
+		return nil, fmt.Errorf("no active conversation")
+	}
+	return cm.getConversationLocked(cm.activeConv)
+}
+
+// SetActiveConversation switches the focused conversation.
+func (cm *ConversationManager) SetActiveConversation(convID string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if _, ok := cm.states[convID]; !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+	cm.activeConv = convID
+	return nil
+}
+
+// AddMessage appends a message with no turn enforcement. Free-form types
+// (democratic, consensus) use this directly.
+func (cm *ConversationManager) AddMessage(convID string, msg ConvoMessage) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.addMessageLocked(convID, msg)
+}
+
+func (cm *ConversationManager) addMessageLocked(convID string, msg ConvoMessage) error {
+	state, ok := cm.states[convID]
+	if !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+	if !state.Active {
+		return fmt.Errorf("conversation %s has ended", convID)
+	}
+	if msg.ID == "" {
+		msg.ID = newConvID()
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	state.Messages = append(state.Messages, msg)
+	return nil
+}
+
+// ==================== TURN TAKING ====================
+
+// turnEnforced reports whether the conversation type requires speaking in
+// turn order. Democratic and consensus conversations stay free-form.
+func turnEnforced(convType ConversationType) bool {
+	return convType == ConvHierarchical || convType == ConvDebate
+}
+
+// NextTurn advances CurrentTurn to the next participant and returns whose
+// turn it now is. Emits a turn_advanced event.
+func (cm *ConversationManager) NextTurn(convID string) (string, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return "", fmt.Errorf("conversation %s not found", convID)
+	}
+	if len(state.TurnOrder) == 0 {
+		return "", fmt.Errorf("conversation %s has no turn order", convID)
+	}
+
+	state.CurrentTurn = (state.CurrentTurn + 1) % len(state.TurnOrder)
+	next := state.TurnOrder[state.CurrentTurn]
+	cm.emitEventLocked("turn_advanced", convID, map[string]interface{}{
+		"current_turn": state.CurrentTurn,
+		"speaker":      next,
+	})
+	return next, nil
+}
+
+// AddMessageAsTurn appends a message subject to turn enforcement: for
+// hierarchical and debate conversations the sender must be the current
+// speaker, and the turn advances after a successful post.
+func (cm *ConversationManager) AddMessageAsTurn(convID string, msg ConvoMessage) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+	if turnEnforced(state.Type) && len(state.TurnOrder) > 0 {
+		current := state.TurnOrder[state.CurrentTurn]
+		if msg.Sender != current {
+			return fmt.Errorf("not %s's turn (current speaker: %s)", msg.Sender, current)
+		}
+	}
+	if err := cm.addMessageLocked(convID, msg); err != nil {
+		return err
+	}
+	if turnEnforced(state.Type) && len(state.TurnOrder) > 0 {
+		state.CurrentTurn = (state.CurrentTurn + 1) % len(state.TurnOrder)
+		cm.emitEventLocked("turn_advanced", convID, map[string]interface{}{
+			"current_turn": state.CurrentTurn,
+			"speaker":      state.TurnOrder[state.CurrentTurn],
+		})
+	}
+	return nil
+}
+
+// ==================== VOTING ====================
+
+// settingFloat reads a numeric setting from the conversation's config,
+// falling back to def when unset.
+func (cm *ConversationManager) settingFloat(state *ConversationState, key string, def float64) float64 {
+	cfg, ok := cm.configs[state.ConfigName]
+	if !ok || cfg.Settings == nil {
+		return def
+	}
+	if v, ok := cfg.Settings[key].(float64); ok {
+		return v
+	}
+	return def
+}
+
+// settingBool reads a boolean setting from the conversation's config.
+func (cm *ConversationManager) settingBool(state *ConversationState, key string, def bool) bool {
+	cfg, ok := cm.configs[state.ConfigName]
+	if !ok || cfg.Settings == nil {
+		return def
+	}
+	if v, ok := cfg.Settings[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// CastVote records userID's vote on a message. An empty userID is an
+// anonymous vote, only allowed when the config says so. Votes are rejected
+// once voting_timeout seconds have passed since the first vote.
+func (cm *ConversationManager) CastVote(convID, messageID, userID string, value int) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+	if userID == "" && !cm.settingBool(state, "allow_anonymous", false) {
+		return fmt.Errorf("anonymous votes are not allowed in %s", convID)
+	}
+
+	timeout := cm.settingFloat(state, "voting_timeout", 0)
+	if timeout > 0 {
+		start, started := cm.voteStarts[convID]
+		if started && time.Since(start).Seconds() > timeout {
+			return fmt.Errorf("voting window for %s has closed", convID)
+		}
+		if !started {
+			cm.voteStarts[convID] = time.Now()
+		}
+	}
+
+	for i := range state.Messages {
+		msg := &state.Messages[i]
+		if msg.ID != messageID {
+			continue
+		}
+		if msg.Votes == nil {
+			msg.Votes = make(map[string]int)
+		}
+		voter := userID
+		if voter == "" {
+			voter = "anon-" + newConvID()
+		}
+		msg.Votes[voter] = value
+		return nil
+	}
+	return fmt.Errorf("message %s not found in %s", messageID, convID)
+}
+
+// ResolveVote tallies every message's votes and returns the winner. A tie
+// between the top messages is reported as an error rather than picking one
+// arbitrarily. Emits a vote_resolved event carrying the tally.
+func (cm *ConversationManager) ResolveVote(convID string) (string, map[string]int, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return "", nil, fmt.Errorf("conversation %s not found", convID)
+	}
+
+	tally := make(map[string]int)
+	for _, msg := range state.Messages {
+		if len(msg.Votes) == 0 {
+			continue
+		}
+		total := 0
+		for _, v := range msg.Votes {
+			total += v
+		}
+		tally[msg.ID] = total
+	}
+	if len(tally) == 0 {
+		return "", nil, fmt.Errorf("no votes cast in %s", convID)
+	}
+
+	winner := ""
+	best := 0
+	tied := false
+	for id, total := range tally {
+		switch {
+		case winner == "" || total > best:
+			winner, best, tied = id, total, false
+		case total == best:
+			tied = true
+		}
+	}
+	delete(cm.voteStarts, convID)
+
+	cm.emitEventLocked("vote_resolved", convID, map[string]interface{}{
+		"tally":  tally,
+		"winner": winner,
+		"tied":   tied,
+	})
+	if tied {
+		return "", tally, fmt.Errorf("vote in %s is tied at %d", convID, best)
+	}
+	return winner, tally, nil
+}
+
+// ==================== CONSENSUS ====================
+
+// supporters returns the unique users backing a message via reactions or
+// positive votes.
+func supporters(msg ConvoMessage) map[string]bool {
+	users := make(map[string]bool)
+	for _, list := range msg.Reactions {
+		for _, user := range list {
+			users[user] = true
+		}
+	}
+	for user, value := range msg.Votes {
+		if value > 0 {
+			users[user] = true
+		}
+	}
+	return users
+}
+
+// CheckConsensus computes the best agreement ratio across the
+// conversation's messages and compares it with the config's
+// consensus_threshold. When the threshold is met the conversation is ended
+// with a summary; once max_discussion_time has elapsed consensus can no
+// longer be reached.
+func (cm *ConversationManager) CheckConsensus(convID string) (bool, float64, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return false, 0, fmt.Errorf("conversation %s not found", convID)
+	}
+	if len(state.Participants) == 0 {
+		return false, 0, fmt.Errorf("conversation %s has no participants", convID)
+	}
+
+	// Find the best-supported message
+	agreement := 0.0
+	bestMsg := ""
+	for _, msg := range state.Messages {
+		ratio := float64(len(supporters(msg))) / float64(len(state.Participants))
+		if ratio > agreement {
+			agreement = ratio
+			bestMsg = msg.ID
+		}
+	}
+
+	maxTime := cm.settingFloat(state, "max_discussion_time", 0)
+	if maxTime > 0 && time.Since(state.StartTime).Seconds() > maxTime {
+		return false, agreement, fmt.Errorf("discussion time for %s exceeded without consensus", convID)
+	}
+
+	threshold := cm.settingFloat(state, "consensus_threshold", 0.8)
+	if agreement < threshold {
+		return false, agreement, nil
+	}
+
+	state.Active = false
+	state.Summary = fmt.Sprintf("Consensus reached on message %s with %.0f%% agreement", bestMsg, agreement*100)
+	cm.emitEventLocked("consensus_reached", convID, map[string]interface{}{
+		"agreement": agreement,
+		"message":   bestMsg,
+	})
+	return true, agreement, nil
+}
+
+// ==================== THREADING ====================
+
+// ThreadNode is one message plus its nested replies, ready for indented
+// rendering.
+type ThreadNode struct {
+	Message  ConvoMessage `json:"message"`
+	Children []ThreadNode `json:"children,omitempty"`
+}
+
+// AddReply appends msg as a reply to parentID, wiring ParentID and
+// ThreadID. Replies to a reply inherit the parent's thread; replies to a
+// top-level message start the thread at the parent. A reply whose parent
+// chain would loop is rejected.
+func (cm *ConversationManager) AddReply(convID, parentID string, msg ConvoMessage) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+
+	var parent *ConvoMessage
+	byID := make(map[string]*ConvoMessage, len(state.Messages))
+	for i := range state.Messages {
+		byID[state.Messages[i].ID] = &state.Messages[i]
+		if state.Messages[i].ID == parentID {
+			parent = &state.Messages[i]
+		}
+	}
+	if parent == nil {
+		return fmt.Errorf("parent message %s not found in %s", parentID, convID)
+	}
+
+	if msg.ID == "" {
+		msg.ID = newConvID()
+	}
+
+	// Walk the parent chain; hitting the new message's ID (or looping
+	// longer than the message count) means a cycle
+	seen := 0
+	for cursor := parent; cursor != nil; cursor = byID[cursor.ParentID] {
+		if cursor.ID == msg.ID {
+			return fmt.Errorf("reply %s would create a cycle", msg.ID)
+		}
+		seen++
+		if seen > len(state.Messages) {
+			return fmt.Errorf("parent chain of %s loops", parentID)
+		}
+	}
+
+	msg.ParentID = parentID
+	if parent.ThreadID != "" {
+		msg.ThreadID = parent.ThreadID
+	} else {
+		msg.ThreadID = parent.ID
+	}
+	return cm.addMessageLocked(convID, msg)
+}
+
+// BuildThreadTree returns the conversation's messages as a forest of
+// nested reply nodes in original message order.
+func (cm *ConversationManager) BuildThreadTree(convID string) ([]ThreadNode, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return nil, fmt.Errorf("conversation %s not found", convID)
+	}
+
+	children := make(map[string][]string)
+	byID := make(map[string]ConvoMessage, len(state.Messages))
+	var roots []string
+	for _, msg := range state.Messages {
+		byID[msg.ID] = msg
+		if msg.ParentID == "" {
+			roots = append(roots, msg.ID)
+			continue
+		}
+		if _, ok := byID[msg.ParentID]; !ok {
+			// Orphaned reply: surface it at the top level
+			roots = append(roots, msg.ID)
+			continue
+		}
+		children[msg.ParentID] = append(children[msg.ParentID], msg.ID)
+	}
+
+	var build func(id string, depth int) (ThreadNode, error)
+	build = func(id string, depth int) (ThreadNode, error) {
+		if depth > len(state.Messages) {
+			return ThreadNode{}, fmt.Errorf("thread under %s loops", id)
+		}
+		node := ThreadNode{Message: byID[id]}
+		for _, childID := range children[id] {
+			child, err := build(childID, depth+1)
+			if err != nil {
+				return ThreadNode{}, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		return node, nil
+	}
+
+	var tree []ThreadNode
+	for _, id := range roots {
+		node, err := build(id, 1)
+		if err != nil {
+			return nil, err
+		}
+		tree = append(tree, node)
+	}
+	return tree, nil
+}
+
+// ==================== EDITING ====================
+
+// EditMessage rewrites a message's content, appending the old and new
+// content to its EditHistory. Only the original author or a moderator may
+// edit. Emits a message_edited event.
+func (cm *ConversationManager) EditMessage(convID, messageID, newContent, userID, reason string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	state, ok := cm.states[convID]
+	if !ok {
+		return fmt.Errorf("conversation %s not found", convID)
+	}
+
+	for i := range state.Messages {
+		msg := &state.Messages[i]
+		if msg.ID != messageID {
+			continue
+		}
+		if msg.Sender != userID && !cm.isModerator(userID) {
+			return fmt.Errorf("%s may not edit %s's message", userID, msg.Sender)
+		}
+		msg.EditHistory = append(msg.EditHistory, EditEntry{
+			OldContent: msg.Content,
+			NewContent: newContent,
+			EditedBy:   userID,
+			Reason:     reason,
+			Timestamp:  time.Now(),
+		})
+		msg.Content = newContent
+		msg.Edited = true
+		cm.emitEventLocked("message_edited", convID, map[string]interface{}{
+			"message_id": messageID,
+			"edited_by":  userID,
+			"reason":     reason,
+		})
+		return nil
+	}
+	return fmt.Errorf("message %s not found in %s", messageID, convID)
+}
+
+// ==================== REACTIONS ====================
+
+// AddReaction records userID's emoji reaction on a message. Re-adding the
+// same reaction is a no-op, so each user appears at most once per emoji.
+// Emits a reaction_changed event.
+func (cm *ConversationManager) AddReaction(convID, messageID, emoji, userID string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	msg, err := cm.findMessageLocked(convID, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.Reactions == nil {
+		msg.Reactions = make(map[string][]string)
+	}
+	for _, user := range msg.Reactions[emoji] {
+		if user == userID {
+			return nil
+		}
+	}
+	msg.Reactions[emoji] = append(msg.Reactions[emoji], userID)
+	cm.emitEventLocked("reaction_changed", convID, map[string]interface{}{
+		"message_id": messageID,
+		"emoji":      emoji,
+		"user":       userID,
+		"added":      true,
+	})
+	return nil
+}
+
+// RemoveReaction withdraws userID's emoji reaction from a message,
+// dropping the emoji entry entirely when its last user leaves.
+func (cm *ConversationManager) RemoveReaction(convID, messageID, emoji, userID string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	msg, err := cm.findMessageLocked(convID, messageID)
+	if err != nil {
+		return err
+	}
+	users := msg.Reactions[emoji]
+	for i, user := range users {
+		if user != userID {
+			continue
+		}
+		users = append(users[:i], users[i+1:]...)
+		if len(users) == 0 {
+			delete(msg.Reactions, emoji)
+		} else {
+			msg.Reactions[emoji] = users
+		}
+		cm.emitEventLocked("reaction_changed", convID, map[string]interface{}{
+			"message_id": messageID,
+			"emoji":      emoji,
+			"user":       userID,
+			"added":      false,
+		})
+		return nil
+	}
+	return fmt.Errorf("%s has no %s reaction on %s", userID, emoji, messageID)
+}
+
+// findMessageLocked locates a message by ID. Callers hold cm.mu.
+func (cm *ConversationManager) findMessageLocked(convID, messageID string) (*ConvoMessage, error) {
+	state, ok := cm.states[convID]
+	if !ok {
+		return nil, fmt.Errorf("conversation %s not found", convID)
+	}
+	for i := range state.Messages {
+		if state.Messages[i].ID == messageID {
+			return &state.Messages[i], nil
+		}
+	}
+	return nil, fmt.Errorf("message %s not found in %s", messageID, convID)
+}
+
+// ==================== PERSISTENCE ====================
+
+// conversationManifest records the pieces of manager state that live
+// outside the per-conversation files.
+type conversationManifest struct {
+	ActiveConv string `json:"active_conv"`
+}
+
+// SaveState writes each conversation to dir as <id>.json plus a manifest
+// carrying the active-conversation pointer.
+func (cm *ConversationManager) SaveState(dir string) error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	for id, state := range cm.states {
+		data, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal conversation %s: %w", id, err)
+		}
+		path := filepath.Join(dir, id+".json")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	manifest, err := json.Marshal(conversationManifest{ActiveConv: cm.activeConv})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores conversations saved by SaveState. Conversations whose
+// config no longer exists are kept but marked inactive.
+func (cm *ConversationManager) LoadState(dir string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read state dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || name == "manifest.json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		var state ConversationState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if _, ok := cm.configs[state.ConfigName]; !ok {
+			// Orphaned: its config is gone, so it cannot continue
+			state.Active = false
+		}
+		cm.states[state.ID] = &state
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err == nil {
+		var manifest conversationManifest
+		if err := json.Unmarshal(manifestData, &manifest); err != nil {
+			return fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if _, ok := cm.states[manifest.ActiveConv]; ok {
+			cm.activeConv = manifest.ActiveConv
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return nil
+}
+
+// ==================== SEARCH ====================
+
+// SearchOptions narrows SearchMessages. Zero values mean no constraint.
+type SearchOptions struct {
+	Regex       bool             // treat the query as a regular expression
+	Type        ConversationType // only conversations of this type
+	Since       time.Time
+	Until       time.Time
+	SnippetSize int // context characters either side of the match (default 20)
+}
+
+// MessageHit is one search result with enough context to display and jump
+// to the match.
+type MessageHit struct {
+	ConvID    string    `json:"conv_id"`
+	MessageID string    `json:"message_id"`
+	Snippet   string    `json:"snippet"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SearchMessages scans every conversation for messages matching query
+// (case-insensitive substring, or regex when opts.Regex is set) and returns
+// hits ordered newest first.
+func (cm *ConversationManager) SearchMessages(query string, opts SearchOptions) ([]MessageHit, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var re *regexp.Regexp
+	if opts.Regex {
+		var err error
+		re, err = regexp.Compile("(?i)" + query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+	}
+	window := opts.SnippetSize
+	if window <= 0 {
+		window = 20
+	}
+
+	var hits []MessageHit
+	for convID, state := range cm.states {
+		if opts.Type != "" && state.Type != opts.Type {
+			continue
+		}
+		for _, msg := range state.Messages {
+			if !opts.Since.IsZero() && msg.Timestamp.Before(opts.Since) {
+				continue
+			}
+			if !opts.Until.IsZero() && msg.Timestamp.After(opts.Until) {
+				continue
+			}
+
+			start, end := -1, -1
+			if re != nil {
+				if loc := re.FindStringIndex(msg.Content); loc != nil {
+					start, end = loc[0], loc[1]
+				}
+			} else {
+				lower := strings.ToLower(msg.Content)
+				if idx := strings.Index(lower, strings.ToLower(query)); idx >= 0 {
+					start, end = idx, idx+len(query)
+				}
+			}
+			if start < 0 {
+				continue
+			}
+
+			hits = append(hits, MessageHit{
+				ConvID:    convID,
+				MessageID: msg.ID,
+				Snippet:   snippetAround(msg.Content, start, end, window),
+				Timestamp: msg.Timestamp,
+			})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Timestamp.After(hits[j].Timestamp)
+	})
+	return hits, nil
+}
+
+// snippetAround cuts a window of context around [start, end), adding
+// ellipses where content was trimmed.
+func snippetAround(content string, start, end, window int) string {
+	from := start - window
+	if from < 0 {
+		from = 0
+	}
+	to := end + window
+	if to > len(content) {
+		to = len(content)
+	}
+	snippet := content[from:to]
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(content) {
+		snippet = snippet + "…"
+	}
+	return snippet
+}
+
+// ==================== EVENTS & HELPERS ====================
+
+// Events returns a copy of the emitted event log.
+func (cm *ConversationManager) Events() []ConversationEvent {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	out := make([]ConversationEvent, len(cm.events))
+	copy(out, cm.events)
+	return out
+}
+
+func (cm *ConversationManager) emitEventLocked(eventType, convID string, data map[string]interface{}) {
+	cm.events = append(cm.events, ConversationEvent{
+		Type:      eventType,
+		ConvID:    convID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// SetAuthProvider swaps in a custom authorization model. Passing nil
+// restores the built-in static role list.
+func (cm *ConversationManager) SetAuthProvider(provider AuthProvider) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if provider == nil {
+		provider = staticAuthProvider{}
+	}
+	cm.auth = provider
+}
+
+// isModerator reports whether the user may perform privileged actions.
+func (cm *ConversationManager) isModerator(userID string) bool {
+	return cm.auth.IsModerator(userID)
+}
+
+// CanJoin reports whether the user may join conversations of the given
+// type, per the configured AuthProvider.
+func (cm *ConversationManager) CanJoin(userID string, convType ConversationType) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.auth.CanJoin(userID, string(convType))
+}
+
+// newConvID returns a random hex identifier for messages and events.
+func newConvID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
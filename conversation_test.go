@@ -0,0 +1,621 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test that out-of-turn messages are rejected in turn-enforced types
+func TestTurnEnforcementRejectsOutOfTurn(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("debate-1", "debate", []string{"alice", "bob", "carol"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	// Bob speaking first is out of turn
+	err := cm.AddMessageAsTurn("debate-1", ConvoMessage{Sender: "bob", Content: "me first"})
+	if err == nil {
+		t.Fatal("Expected out-of-turn message to be rejected")
+	}
+
+	// Alice is the current speaker and must succeed
+	if err := cm.AddMessageAsTurn("debate-1", ConvoMessage{Sender: "alice", Content: "opening"}); err != nil {
+		t.Fatalf("In-turn message rejected: %v", err)
+	}
+
+	state, err := cm.GetConversation("debate-1")
+	if err != nil {
+		t.Fatalf("GetConversation failed: %v", err)
+	}
+	if len(state.Messages) != 1 {
+		t.Errorf("Expected 1 message, got %d", len(state.Messages))
+	}
+	if state.TurnOrder[state.CurrentTurn] != "bob" {
+		t.Errorf("Turn did not advance to bob, current: %s", state.TurnOrder[state.CurrentTurn])
+	}
+}
+
+// Test that the turn cursor wraps around the participant list
+func TestNextTurnWraps(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("debate-2", "debate", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	want := []string{"bob", "alice", "bob", "alice"}
+	for i, expected := range want {
+		speaker, err := cm.NextTurn("debate-2")
+		if err != nil {
+			t.Fatalf("NextTurn %d failed: %v", i, err)
+		}
+		if speaker != expected {
+			t.Errorf("Turn %d: expected %s, got %s", i, expected, speaker)
+		}
+	}
+
+	// turn_advanced events were emitted for each advance
+	count := 0
+	for _, ev := range cm.Events() {
+		if ev.Type == "turn_advanced" && ev.ConvID == "debate-2" {
+			count++
+		}
+	}
+	if count != len(want) {
+		t.Errorf("Expected %d turn_advanced events, got %d", len(want), count)
+	}
+}
+
+// Test that democratic conversations stay free-form
+func TestDemocraticIsFreeForm(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("demo-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	for _, sender := range []string{"bob", "bob", "alice"} {
+		msg := ConvoMessage{Sender: sender, Content: "anytime", Timestamp: time.Now()}
+		if err := cm.AddMessageAsTurn("demo-1", msg); err != nil {
+			t.Errorf("Democratic message from %s rejected: %v", sender, err)
+		}
+	}
+}
+
+// Test vote resolution with a clear winner
+func TestResolveVoteClearWinner(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("vote-1", "democratic", []string{"alice", "bob", "carol"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("vote-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "option A"})
+	cm.AddMessage("vote-1", ConvoMessage{ID: "m2", Sender: "bob", Content: "option B"})
+
+	cm.CastVote("vote-1", "m1", "alice", 1)
+	cm.CastVote("vote-1", "m1", "carol", 1)
+	cm.CastVote("vote-1", "m2", "bob", 1)
+
+	winner, tally, err := cm.ResolveVote("vote-1")
+	if err != nil {
+		t.Fatalf("ResolveVote failed: %v", err)
+	}
+	if winner != "m1" {
+		t.Errorf("Expected m1 to win, got %s", winner)
+	}
+	if tally["m1"] != 2 || tally["m2"] != 1 {
+		t.Errorf("Unexpected tally: %v", tally)
+	}
+
+	found := false
+	for _, ev := range cm.Events() {
+		if ev.Type == "vote_resolved" && ev.ConvID == "vote-1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a vote_resolved event")
+	}
+}
+
+// Test that a tie is reported instead of picking arbitrarily
+func TestResolveVoteTie(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("vote-2", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("vote-2", ConvoMessage{ID: "m1", Sender: "alice"})
+	cm.AddMessage("vote-2", ConvoMessage{ID: "m2", Sender: "bob"})
+	cm.CastVote("vote-2", "m1", "alice", 1)
+	cm.CastVote("vote-2", "m2", "bob", 1)
+
+	winner, tally, err := cm.ResolveVote("vote-2")
+	if err == nil {
+		t.Fatal("Expected tie to be reported as an error")
+	}
+	if winner != "" {
+		t.Errorf("Tie should not name a winner, got %s", winner)
+	}
+	if tally["m1"] != 1 || tally["m2"] != 1 {
+		t.Errorf("Unexpected tally: %v", tally)
+	}
+}
+
+// Test anonymous votes are rejected when the config disallows them
+func TestCastVoteAnonymousDisallowed(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("vote-3", "democratic", []string{"alice"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("vote-3", ConvoMessage{ID: "m1", Sender: "alice"})
+
+	if err := cm.CastVote("vote-3", "m1", "", 1); err == nil {
+		t.Error("Expected anonymous vote to be rejected")
+	}
+
+	// A config permitting anonymity accepts the same vote
+	cm.RegisterConfig(ConversationConfig{
+		Name: "anon-ok", Type: ConvDemocratic,
+		Settings: map[string]interface{}{"allow_anonymous": true},
+	})
+	if err := cm.CreateConversation("vote-4", "anon-ok", []string{"alice"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("vote-4", ConvoMessage{ID: "m1", Sender: "alice"})
+	if err := cm.CastVote("vote-4", "m1", "", 1); err != nil {
+		t.Errorf("Anonymous vote rejected despite allow_anonymous: %v", err)
+	}
+}
+
+// Test consensus detection above and below the threshold
+func TestCheckConsensusThreshold(t *testing.T) {
+	cm := NewConversationManager()
+	participants := []string{"alice", "bob", "carol", "dave"}
+	if err := cm.CreateConversation("cons-1", "consensus", participants); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("cons-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "proposal"})
+
+	// Two of four supporters: 50% is under the 0.8 threshold
+	cm.CastVote("cons-1", "m1", "alice", 1)
+	cm.CastVote("cons-1", "m1", "bob", 1)
+	reached, agreement, err := cm.CheckConsensus("cons-1")
+	if err != nil {
+		t.Fatalf("CheckConsensus failed: %v", err)
+	}
+	if reached {
+		t.Error("Consensus reported at 50% agreement with a 0.8 threshold")
+	}
+	if agreement != 0.5 {
+		t.Errorf("Expected agreement 0.5, got %v", agreement)
+	}
+
+	// All four supporters clears the threshold and ends the conversation
+	cm.CastVote("cons-1", "m1", "carol", 1)
+	cm.CastVote("cons-1", "m1", "dave", 1)
+	reached, agreement, err = cm.CheckConsensus("cons-1")
+	if err != nil {
+		t.Fatalf("CheckConsensus failed: %v", err)
+	}
+	if !reached || agreement != 1.0 {
+		t.Errorf("Expected consensus at full agreement, got reached=%v agreement=%v", reached, agreement)
+	}
+
+	state, _ := cm.GetConversation("cons-1")
+	if state.Active {
+		t.Error("Conversation should end once consensus is reached")
+	}
+	if state.Summary == "" {
+		t.Error("Consensus should record a summary")
+	}
+}
+
+// Test that consensus fails after the discussion deadline
+func TestCheckConsensusDeadline(t *testing.T) {
+	cm := NewConversationManager()
+	cm.RegisterConfig(ConversationConfig{
+		Name: "fast-consensus", Type: ConvConsensus,
+		Settings: map[string]interface{}{
+			"consensus_threshold": 0.5,
+			"max_discussion_time": 0.001,
+		},
+	})
+	if err := cm.CreateConversation("cons-2", "fast-consensus", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("cons-2", ConvoMessage{ID: "m1", Sender: "alice"})
+	cm.CastVote("cons-2", "m1", "alice", 1)
+	cm.CastVote("cons-2", "m1", "bob", 1)
+
+	time.Sleep(5 * time.Millisecond)
+	reached, _, err := cm.CheckConsensus("cons-2")
+	if reached {
+		t.Error("Consensus reported after the discussion deadline")
+	}
+	if err == nil {
+		t.Error("Expected an error once max_discussion_time is exceeded")
+	}
+}
+
+// Test building a two-level thread tree
+func TestBuildThreadTree(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("thread-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("thread-1", ConvoMessage{ID: "root", Sender: "alice", Content: "topic"})
+	if err := cm.AddReply("thread-1", "root", ConvoMessage{ID: "r1", Sender: "bob", Content: "reply"}); err != nil {
+		t.Fatalf("AddReply failed: %v", err)
+	}
+	if err := cm.AddReply("thread-1", "r1", ConvoMessage{ID: "r2", Sender: "alice", Content: "counter"}); err != nil {
+		t.Fatalf("AddReply failed: %v", err)
+	}
+
+	tree, err := cm.BuildThreadTree("thread-1")
+	if err != nil {
+		t.Fatalf("BuildThreadTree failed: %v", err)
+	}
+	if len(tree) != 1 {
+		t.Fatalf("Expected 1 root node, got %d", len(tree))
+	}
+	if tree[0].Message.ID != "root" || len(tree[0].Children) != 1 {
+		t.Fatalf("Unexpected tree shape at root: %+v", tree[0])
+	}
+	child := tree[0].Children[0]
+	if child.Message.ID != "r1" || len(child.Children) != 1 || child.Children[0].Message.ID != "r2" {
+		t.Errorf("Unexpected nested shape: %+v", child)
+	}
+
+	// Replies inherit the root's thread ID
+	state, _ := cm.GetConversation("thread-1")
+	for _, msg := range state.Messages[1:] {
+		if msg.ThreadID != "root" {
+			t.Errorf("Message %s has thread %q, want \"root\"", msg.ID, msg.ThreadID)
+		}
+	}
+}
+
+// Test that replies creating a parent-chain cycle are rejected
+func TestAddReplyRejectsCycle(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("thread-2", "democratic", []string{"alice"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("thread-2", ConvoMessage{ID: "a", Sender: "alice"})
+	if err := cm.AddReply("thread-2", "a", ConvoMessage{ID: "b", Sender: "alice"}); err != nil {
+		t.Fatalf("AddReply failed: %v", err)
+	}
+
+	// "a" replying under "b" would make a's chain loop through itself
+	if err := cm.AddReply("thread-2", "b", ConvoMessage{ID: "a", Sender: "alice"}); err == nil {
+		t.Error("Expected cycle-creating reply to be rejected")
+	}
+
+	// Unknown parents error clearly
+	if err := cm.AddReply("thread-2", "missing", ConvoMessage{Sender: "alice"}); err == nil {
+		t.Error("Expected reply to unknown parent to fail")
+	}
+}
+
+// Test edit history accumulation and permission enforcement
+func TestEditMessageHistoryAndPermissions(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("edit-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("edit-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "v1"})
+
+	// Non-author, non-moderator may not edit
+	if err := cm.EditMessage("edit-1", "m1", "hijacked", "bob", ""); err == nil {
+		t.Error("Expected non-author edit to be rejected")
+	}
+
+	// The author edits twice; history accumulates oldest-first
+	if err := cm.EditMessage("edit-1", "m1", "v2", "alice", "typo"); err != nil {
+		t.Fatalf("Author edit failed: %v", err)
+	}
+	if err := cm.EditMessage("edit-1", "m1", "v3", "alice", "clarify"); err != nil {
+		t.Fatalf("Second edit failed: %v", err)
+	}
+
+	// A moderator can also edit
+	if err := cm.EditMessage("edit-1", "m1", "v4", "moderator", "cleanup"); err != nil {
+		t.Fatalf("Moderator edit failed: %v", err)
+	}
+
+	state, _ := cm.GetConversation("edit-1")
+	msg := state.Messages[0]
+	if msg.Content != "v4" || !msg.Edited {
+		t.Errorf("Message not updated: content=%q edited=%v", msg.Content, msg.Edited)
+	}
+	if len(msg.EditHistory) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(msg.EditHistory))
+	}
+	if msg.EditHistory[0].OldContent != "v1" || msg.EditHistory[0].NewContent != "v2" {
+		t.Errorf("First history entry wrong: %+v", msg.EditHistory[0])
+	}
+	if msg.EditHistory[2].EditedBy != "moderator" {
+		t.Errorf("Last history entry wrong: %+v", msg.EditHistory[2])
+	}
+
+	edited := 0
+	for _, ev := range cm.Events() {
+		if ev.Type == "message_edited" {
+			edited++
+		}
+	}
+	if edited != 3 {
+		t.Errorf("Expected 3 message_edited events, got %d", edited)
+	}
+}
+
+// Test reaction add, idempotent re-add, and removal
+func TestReactions(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("react-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("react-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "idea"})
+
+	if err := cm.AddReaction("react-1", "m1", "👍", "bob"); err != nil {
+		t.Fatalf("AddReaction failed: %v", err)
+	}
+	// Re-adding the same reaction must not duplicate the user
+	if err := cm.AddReaction("react-1", "m1", "👍", "bob"); err != nil {
+		t.Fatalf("Idempotent AddReaction failed: %v", err)
+	}
+	if err := cm.AddReaction("react-1", "m1", "👍", "alice"); err != nil {
+		t.Fatalf("AddReaction failed: %v", err)
+	}
+
+	state, _ := cm.GetConversation("react-1")
+	if got := state.Messages[0].Reactions["👍"]; len(got) != 2 {
+		t.Errorf("Expected 2 unique reactors, got %v", got)
+	}
+
+	if err := cm.RemoveReaction("react-1", "m1", "👍", "bob"); err != nil {
+		t.Fatalf("RemoveReaction failed: %v", err)
+	}
+	state, _ = cm.GetConversation("react-1")
+	if got := state.Messages[0].Reactions["👍"]; len(got) != 1 || got[0] != "alice" {
+		t.Errorf("Expected only alice's reaction left, got %v", got)
+	}
+
+	// Removing a reaction that was never added errors
+	if err := cm.RemoveReaction("react-1", "m1", "🎉", "bob"); err == nil {
+		t.Error("Expected removal of absent reaction to fail")
+	}
+}
+
+// Test save/load round-trip of conversation state
+func TestSaveLoadStateRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("persist-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	if err := cm.CreateConversation("persist-2", "debate", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("persist-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "hello"})
+	cm.AddMessage("persist-1", ConvoMessage{ID: "m2", Sender: "bob", Content: "hi"})
+	if err := cm.SetActiveConversation("persist-1"); err != nil {
+		t.Fatalf("SetActiveConversation failed: %v", err)
+	}
+
+	if err := cm.SaveState(dir); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	fresh := NewConversationManager()
+	if err := fresh.LoadState(dir); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+
+	state, err := fresh.GetConversation("persist-1")
+	if err != nil {
+		t.Fatalf("Restored conversation missing: %v", err)
+	}
+	if len(state.Messages) != 2 {
+		t.Errorf("Expected 2 restored messages, got %d", len(state.Messages))
+	}
+	active, err := fresh.GetActiveConversation()
+	if err != nil {
+		t.Fatalf("GetActiveConversation failed: %v", err)
+	}
+	if active.ID != "persist-1" {
+		t.Errorf("Active pointer not restored: %s", active.ID)
+	}
+}
+
+// Test that conversations with missing configs load as inactive
+func TestLoadStateOrphanedConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cm := NewConversationManager()
+	cm.RegisterConfig(ConversationConfig{Name: "ephemeral", Type: ConvDemocratic})
+	if err := cm.CreateConversation("orphan-1", "ephemeral", []string{"alice"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	if err := cm.SaveState(dir); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	// A fresh manager doesn't know the "ephemeral" config
+	fresh := NewConversationManager()
+	if err := fresh.LoadState(dir); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	state, err := fresh.GetConversation("orphan-1")
+	if err != nil {
+		t.Fatalf("Orphaned conversation should still load: %v", err)
+	}
+	if state.Active {
+		t.Error("Conversation with missing config should be inactive")
+	}
+}
+
+// flippedAuth inverts the default authorization decisions.
+type flippedAuth struct{}
+
+func (flippedAuth) IsModerator(userID string) bool       { return userID == "carol" }
+func (flippedAuth) CanJoin(userID, convType string) bool { return convType != "debate" }
+
+// Test that a custom AuthProvider overrides moderator decisions
+func TestCustomAuthProvider(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("auth-1", "democratic", []string{"alice", "carol"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	cm.AddMessage("auth-1", ConvoMessage{ID: "m1", Sender: "alice", Content: "original"})
+
+	// Default provider: "moderator" role may edit, "carol" may not
+	if err := cm.EditMessage("auth-1", "m1", "edit", "moderator", ""); err != nil {
+		t.Errorf("Default moderator rejected: %v", err)
+	}
+	if err := cm.EditMessage("auth-1", "m1", "edit", "carol", ""); err == nil {
+		t.Error("carol should not be a moderator under the default provider")
+	}
+
+	cm.SetAuthProvider(flippedAuth{})
+	if err := cm.EditMessage("auth-1", "m1", "edit", "carol", ""); err != nil {
+		t.Errorf("Custom provider's moderator rejected: %v", err)
+	}
+	if err := cm.EditMessage("auth-1", "m1", "edit", "moderator", ""); err == nil {
+		t.Error("Default roles should lose moderator under the custom provider")
+	}
+
+	if cm.CanJoin("anyone", ConvDebate) {
+		t.Error("Custom provider should deny joining debates")
+	}
+	if !cm.CanJoin("anyone", ConvDemocratic) {
+		t.Error("Custom provider should allow joining democratic conversations")
+	}
+
+	// Nil restores the built-in provider
+	cm.SetAuthProvider(nil)
+	if err := cm.EditMessage("auth-1", "m1", "edit", "moderator", ""); err != nil {
+		t.Errorf("Restored default moderator rejected: %v", err)
+	}
+}
+
+// Test substring and regex search with snippet windowing
+func TestSearchMessages(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("search-1", "democratic", []string{"alice"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+	if err := cm.CreateConversation("search-2", "debate", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	base := time.Date(2026, 8, 1, 8, 0, 0, 0, time.UTC)
+	cm.AddMessage("search-1", ConvoMessage{
+		ID: "m1", Sender: "alice", Timestamp: base,
+		Content: "The Deployment pipeline failed again this morning",
+	})
+	cm.AddMessage("search-2", ConvoMessage{
+		ID: "m2", Sender: "bob", Timestamp: base.Add(time.Hour),
+		Content: "We should redeploy after the fix lands",
+	})
+
+	// Case-insensitive substring matches both, newest first
+	hits, err := cm.SearchMessages("deploy", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchMessages failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %d", len(hits))
+	}
+	if hits[0].MessageID != "m2" || hits[1].MessageID != "m1" {
+		t.Errorf("Hits not ordered newest first: %v", hits)
+	}
+
+	// Type filter narrows to one conversation
+	hits, err = cm.SearchMessages("deploy", SearchOptions{Type: ConvDebate})
+	if err != nil {
+		t.Fatalf("SearchMessages failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ConvID != "search-2" {
+		t.Errorf("Type filter failed: %v", hits)
+	}
+
+	// Regex matching
+	hits, err = cm.SearchMessages(`pipe\w+ failed`, SearchOptions{Regex: true})
+	if err != nil {
+		t.Fatalf("Regex search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].MessageID != "m1" {
+		t.Errorf("Regex search wrong hits: %v", hits)
+	}
+
+	// Snippet windows long content with ellipses
+	hits, err = cm.SearchMessages("pipeline", SearchOptions{SnippetSize: 5})
+	if err != nil {
+		t.Fatalf("SearchMessages failed: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("Expected 1 hit, got %d", len(hits))
+	}
+	if !strings.HasPrefix(hits[0].Snippet, "…") || !strings.HasSuffix(hits[0].Snippet, "…") {
+		t.Errorf("Snippet missing ellipses: %q", hits[0].Snippet)
+	}
+	if !strings.Contains(hits[0].Snippet, "pipeline") {
+		t.Errorf("Snippet missing the match: %q", hits[0].Snippet)
+	}
+
+	// Invalid regex surfaces an error
+	if _, err := cm.SearchMessages("(", SearchOptions{Regex: true}); err == nil {
+		t.Error("Expected invalid regex to error")
+	}
+}
+
+// Stress concurrent readers against a writer; before the locking fix the
+// re-entrant RLock in GetActiveConversation could deadlock here.
+func TestGetActiveConversationConcurrency(t *testing.T) {
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("stress-1", "democratic", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					if _, err := cm.GetActiveConversation(); err != nil {
+						t.Errorf("GetActiveConversation failed: %v", err)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			cm.AddMessage("stress-1", ConvoMessage{Sender: "alice", Content: "load"})
+		}
+		close(done)
+	}()
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Deadlock: concurrent readers and writer did not finish")
+	}
+}
@@ -12,8 +12,6 @@ import (
 
 // Test nil and invalid inputs
 func TestNilAndInvalidInputs(t *testing.T) {
-	animator := NewUnderwaterAnimator()
-
 	// Test nil time message
 	model := initialModel()
 	updatedModel, cmd := model.Update(nil)
@@ -32,7 +30,7 @@ func TestNilAndInvalidInputs(t *testing.T) {
 	}
 
 	for i, key := range invalidKeys {
-		updatedModel, cmd := model.Update(key)
+		updatedModel, _ := model.Update(key)
 		if updatedModel == nil {
 			t.Errorf("Invalid key %d should return a model", i)
 		}
@@ -47,7 +45,7 @@ func TestNilAndInvalidInputs(t *testing.T) {
 	}
 
 	for i, mouse := range invalidMouse {
-		updatedModel, cmd := model.Update(mouse)
+		updatedModel, _ := model.Update(mouse)
 		if updatedModel == nil {
 			t.Errorf("Invalid mouse %d should return a model", i)
 		}
@@ -62,7 +60,7 @@ func TestNilAndInvalidInputs(t *testing.T) {
 	}
 
 	for i, size := range invalidSizes {
-		updatedModel, cmd := model.Update(size)
+		updatedModel, _ := model.Update(size)
 		if updatedModel == nil {
 			t.Errorf("Invalid size %d should return a model", i)
 		}
@@ -71,13 +69,7 @@ func TestNilAndInvalidInputs(t *testing.T) {
 
 // Test file system errors
 func TestFilesystemErrors(t *testing.T) {
-	// Test with read-only directory
 	tempDir := t.TempDir()
-	readOnlyDir := filepath.Join(tempDir, "readonly")
-	os.Mkdir(readOnlyDir, 0755)
-	os.Chmod(readOnlyDir, 0444) // Read-only
-
-	logger := NewFileLogger(readOnlyDir)
 
 	event := SystemEvent{
 		ID:        "test-event",
@@ -87,14 +79,23 @@ func TestFilesystemErrors(t *testing.T) {
 		Message:   "Test error handling",
 	}
 
-	err := logger.LogEvent(event)
-	if err == nil {
-		t.Error("Should fail when writing to read-only directory")
+	// Test with read-only directory; root bypasses permission bits, so
+	// this check only means anything for ordinary users.
+	if os.Geteuid() != 0 {
+		readOnlyDir := filepath.Join(tempDir, "readonly")
+		os.Mkdir(readOnlyDir, 0755)
+		os.Chmod(readOnlyDir, 0444) // Read-only
+
+		logger := NewFileLogger(readOnlyDir)
+		if err := logger.LogEvent(event); err == nil {
+			t.Error("Should fail when writing to read-only directory")
+		}
+		os.Chmod(readOnlyDir, 0755) // Restore permissions for cleanup
 	}
 
 	// Test with non-existent directory
-	logger = NewFileLogger("/non/existent/directory")
-	err = logger.LogEvent(event)
+	logger := NewFileLogger("/non/existent/directory")
+	err := logger.LogEvent(event)
 	if err == nil {
 		t.Error("Should fail when writing to non-existent directory")
 	}
@@ -115,8 +116,6 @@ func TestFilesystemErrors(t *testing.T) {
 		file.Close()
 	}
 
-	// Clean up
-	os.Chmod(readOnlyDir, 0755) // Restore permissions for cleanup
 }
 
 // Test memory pressure
@@ -129,10 +128,9 @@ func TestMemoryPressure(t *testing.T) {
 		particle := Particle{
 			X:       float64(i % 1000),
 			Y:       float64(i / 1000),
-			SpeedX:  0.1,
-			SpeedY:  0.1,
+			VX:      0.1,
+			VY:      0.1,
 			Color:   getRandomColor(),
-			Type:    "bubble",
 			Opacity: 0.5,
 		}
 		animator.particles = append(animator.particles, particle)
@@ -191,21 +189,21 @@ func TestTimeEdgeCases(t *testing.T) {
 
 	// Test with zero time
 	zeroTime := time.Time{}
-	updatedModel, cmd := model.Update(zeroTime)
+	updatedModel, _ := model.Update(zeroTime)
 	if updatedModel == nil {
 		t.Error("Zero time should return a model")
 	}
 
 	// Test with very old time
 	oldTime := time.Unix(0, 0)
-	updatedModel, cmd = model.Update(oldTime)
+	updatedModel, _ = model.Update(oldTime)
 	if updatedModel == nil {
 		t.Error("Old time should return a model")
 	}
 
 	// Test with very future time
 	futureTime := time.Unix(1<<63-1, 0)
-	updatedModel, cmd = model.Update(futureTime)
+	updatedModel, _ = model.Update(futureTime)
 	if updatedModel == nil {
 		t.Error("Future time should return a model")
 	}
@@ -226,16 +224,16 @@ func TestTimeEdgeCases(t *testing.T) {
 	}
 }
 
-// Test boundary conditions
-func TestBoundaryConditions(t *testing.T) {
+// Test particles sitting on the scene boundaries
+func TestParticleBoundaryConditions(t *testing.T) {
 	animator := NewUnderwaterAnimator()
 
 	// Test particles at boundaries
 	animator.particles = []Particle{
-		{X: 0, Y: 0, SpeedX: -1, SpeedY: -1, Color: "#FF0000", Type: "bubble", Opacity: 1},
-		{X: float64(animator.width), Y: float64(animator.height), SpeedX: 1, SpeedY: 1, Color: "#00FF00", Type: "bubble", Opacity: 1},
-		{X: -1, Y: -1, SpeedX: 1, SpeedY: 1, Color: "#0000FF", Type: "bubble", Opacity: 1},
-		{X: float64(animator.width + 1), Y: float64(animator.height + 1), SpeedX: -1, SpeedY: -1, Color: "#FFFF00", Type: "bubble", Opacity: 1},
+		{X: 0, Y: 0, VX: -1, VY: -1, Color: "#FF0000", Opacity: 1},
+		{X: sceneWidth, Y: sceneHeight, VX: 1, VY: 1, Color: "#00FF00", Opacity: 1},
+		{X: -1, Y: -1, VX: 1, VY: 1, Color: "#0000FF", Opacity: 1},
+		{X: sceneWidth + 1, Y: sceneHeight + 1, VX: -1, VY: -1, Color: "#FFFF00", Opacity: 1},
 	}
 
 	// Should handle boundary particles gracefully
@@ -248,20 +246,6 @@ func TestBoundaryConditions(t *testing.T) {
 	if len(render) == 0 {
 		t.Error("Render should handle boundary particles")
 	}
-
-	// Test with zero-sized animator
-	animator.width = 0
-	animator.height = 0
-
-	err = animator.Update(1.0)
-	if err != nil {
-		t.Errorf("Update failed with zero size: %v", err)
-	}
-
-	render = animator.Render()
-	if len(render) == 0 {
-		t.Error("Render should handle zero size")
-	}
 }
 
 // Test invalid data structures
@@ -295,8 +279,8 @@ func TestInvalidDataStructures(t *testing.T) {
 
 	// Test with invalid particle data
 	animator.particles = []Particle{
-		{X: 0, Y: 0, SpeedX: 0, SpeedY: 0, Color: "", Type: "", Opacity: -1},
-		{X: 0, Y: 0, SpeedX: 0, SpeedY: 0, Color: "invalid", Type: "invalid", Opacity: 2},
+		{X: 0, Y: 0, VX: 0, VY: 0, Color: "", Opacity: -1},
+		{X: 0, Y: 0, VX: 0, VY: 0, Color: "invalid", Opacity: 2},
 	}
 
 	err = animator.Update(0.016)
@@ -414,7 +398,7 @@ func TestExtremeUserInputSequences(t *testing.T) {
 
 	for _, key := range keys {
 		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}}
-		updatedModel, cmd := model.Update(msg)
+		updatedModel, _ := model.Update(msg)
 
 		if updatedModel == nil {
 			t.Errorf("Key '%c' should return a model", key)
@@ -428,7 +412,7 @@ func TestExtremeUserInputSequences(t *testing.T) {
 			X:    i % 100,
 			Y:    i % 50,
 		}
-		updatedModel, cmd := model.Update(msg)
+		updatedModel, _ := model.Update(msg)
 
 		if updatedModel == nil {
 			t.Errorf("Mouse motion %d should return a model", i)
@@ -441,7 +425,7 @@ func TestExtremeUserInputSequences(t *testing.T) {
 			Width:  80 + i,
 			Height: 24 + i,
 		}
-		updatedModel, cmd := model.Update(msg)
+		updatedModel, _ := model.Update(msg)
 
 		if updatedModel == nil {
 			t.Errorf("Window resize %d should return a model", i)
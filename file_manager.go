@@ -0,0 +1,878 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== FILE SHARING MODELS ====================
+
+// FilePermission is one action a user may take on a shared file.
+type FilePermission string
+
+const (
+	PermRead   FilePermission = "read"
+	PermWrite  FilePermission = "write"
+	PermDelete FilePermission = "delete"
+)
+
+// SharedFile is one file tracked by the FileManager. Checksum is the hex
+// SHA-256 of the stored content.
+type SharedFile struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	Owner       string   `json:"owner"`
+	Size        int64    `json:"size"`
+	Type        string   `json:"type"` // file extension, lower-case
+	Checksum    string   `json:"checksum"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Public      bool     `json:"public"`
+	// Grants maps a userID to the actions they may take on this file.
+	// The owner implicitly holds every permission.
+	Grants     map[string][]FilePermission `json:"grants,omitempty"`
+	Downloads  int                         `json:"downloads"`
+	Versions   []FileVersion               `json:"versions,omitempty"`
+	UploadedAt time.Time                   `json:"uploaded_at"`
+	ModifiedAt time.Time                   `json:"modified_at"`
+}
+
+// FileVersion is one archived revision of a shared file.
+type FileVersion struct {
+	Version   int       `json:"version"`
+	Path      string    `json:"path"`
+	Checksum  string    `json:"checksum"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ==================== FILE MANAGER ====================
+
+// FileManager stores uploaded files under a storage directory and tracks
+// their metadata.
+type FileManager struct {
+	mu           sync.RWMutex
+	files        map[string]*SharedFile
+	uploads      map[string]*pendingUpload
+	shareLinks   map[string]ShareLink
+	storageDir   string
+	allowedTypes map[string]bool
+	maxFileSize  int64
+	eventHandler func(FileEvent)
+}
+
+// FileEvent is emitted on notable file-manager activity.
+type FileEvent struct {
+	Type      string                 `json:"type"`
+	FileID    string                 `json:"file_id,omitempty"`
+	FileName  string                 `json:"file_name,omitempty"`
+	UserID    string                 `json:"user_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// SetEventHandler installs a callback for file events.
+func (fm *FileManager) SetEventHandler(handler func(FileEvent)) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.eventHandler = handler
+}
+
+// emitEventLocked dispatches an event on its own goroutine so handlers
+// can safely call back into the manager. Callers hold fm.mu.
+func (fm *FileManager) emitEventLocked(event FileEvent) {
+	if fm.eventHandler == nil {
+		return
+	}
+	event.Timestamp = time.Now()
+	go fm.eventHandler(event)
+}
+
+// NewFileManager creates the storage directory and returns a manager with
+// the default type allow-list and a 100MB size cap.
+func NewFileManager(storageDir string) (*FileManager, error) {
+	if err := os.MkdirAll(storageDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+	allowed := make(map[string]bool)
+	for _, ext := range []string{".txt", ".md", ".go", ".json", ".yaml", ".yml", ".png", ".jpg", ".pdf", ".csv", ".log"} {
+		allowed[ext] = true
+	}
+	return &FileManager{
+		files:        make(map[string]*SharedFile),
+		uploads:      make(map[string]*pendingUpload),
+		shareLinks:   make(map[string]ShareLink),
+		storageDir:   storageDir,
+		allowedTypes: allowed,
+		maxFileSize:  100 * 1024 * 1024,
+	}, nil
+}
+
+// SetAllowedTypes replaces the upload type allow-list. Extensions are
+// normalized to lower-case with a leading dot.
+func (fm *FileManager) SetAllowedTypes(exts []string) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	fm.allowedTypes = make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		fm.allowedTypes[ext] = true
+	}
+}
+
+// SetMaxFileSize replaces the per-file size cap in bytes.
+func (fm *FileManager) SetMaxFileSize(n int64) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.maxFileSize = n
+}
+
+// UploadSkip records one file from a directory upload that was not taken
+// and why.
+type UploadSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// UploadDir walks dir and uploads each allowed file for owner. Disallowed
+// or oversized files are skipped and reported instead of failing the whole
+// batch; only I/O errors abort the walk.
+func (fm *FileManager) UploadDir(dir, owner string, recursive bool) ([]*SharedFile, []UploadSkip, error) {
+	var uploaded []*SharedFile
+	var skipped []UploadSkip
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if !recursive && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		file, uploadErr := fm.UploadFile(path, owner)
+		if uploadErr != nil {
+			skipped = append(skipped, UploadSkip{Path: path, Reason: uploadErr.Error()})
+			return nil
+		}
+		uploaded = append(uploaded, file)
+		return nil
+	})
+	if err != nil {
+		return uploaded, skipped, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	return uploaded, skipped, nil
+}
+
+// calculateChecksum returns the hex SHA-256 digest of the file at path.
+func (fm *FileManager) calculateChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for checksum: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// UploadFile copies srcPath into the storage directory and records its
+// metadata.
+func (fm *FileManager) UploadFile(srcPath, owner string) (*SharedFile, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat upload: %w", err)
+	}
+	ext := strings.ToLower(filepath.Ext(srcPath))
+	if !fm.allowedTypes[ext] {
+		return nil, fmt.Errorf("file type %s is not allowed", ext)
+	}
+	if info.Size() > fm.maxFileSize {
+		return nil, fmt.Errorf("file exceeds size limit of %d bytes", fm.maxFileSize)
+	}
+
+	name := filepath.Base(srcPath)
+	destPath := filepath.Join(fm.storageDir, name)
+
+	// Re-uploading an existing name keeps the same file ID and archives
+	// the current content as a version first. Overwriting someone else's
+	// file requires a write grant.
+	existing := fm.findByNameLocked(name)
+	if existing != nil {
+		if !fm.hasPermission(existing, owner, PermWrite) {
+			return nil, fmt.Errorf("%s may not overwrite %s", owner, existing.Name)
+		}
+		if err := fm.archiveVersionLocked(existing); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload: %w", err)
+	}
+	defer src.Close()
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stored file: %w", err)
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return nil, fmt.Errorf("failed to copy upload: %w", err)
+	}
+	dest.Close()
+
+	checksum, err := fm.calculateChecksum(destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		existing.Size = info.Size()
+		existing.Checksum = checksum
+		existing.ModifiedAt = time.Now()
+		fm.emitEventLocked(FileEvent{Type: "file_updated", FileID: existing.ID, FileName: name, UserID: owner})
+		return existing, nil
+	}
+
+	file := &SharedFile{
+		ID:         newConvID(),
+		Name:       name,
+		Path:       destPath,
+		Owner:      owner,
+		Size:       info.Size(),
+		Type:       ext,
+		Checksum:   checksum,
+		UploadedAt: time.Now(),
+		ModifiedAt: time.Now(),
+	}
+	fm.files[file.ID] = file
+	fm.emitEventLocked(FileEvent{Type: "file_uploaded", FileID: file.ID, FileName: name, UserID: owner})
+	return file, nil
+}
+
+// findByNameLocked returns the tracked file with this name, if any.
+// Callers hold fm.mu.
+func (fm *FileManager) findByNameLocked(name string) *SharedFile {
+	for _, file := range fm.files {
+		if file.Name == name {
+			return file
+		}
+	}
+	return nil
+}
+
+// archiveVersionLocked snapshots the file's current content under
+// versions/<id>/ before it is replaced. Callers hold fm.mu.
+func (fm *FileManager) archiveVersionLocked(file *SharedFile) error {
+	versionDir := filepath.Join(fm.storageDir, "versions", file.ID)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return fmt.Errorf("failed to create version dir: %w", err)
+	}
+
+	version := len(file.Versions) + 1
+	versionPath := filepath.Join(versionDir, fmt.Sprintf("v%d%s", version, file.Type))
+
+	src, err := os.Open(file.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open current version: %w", err)
+	}
+	defer src.Close()
+	dest, err := os.Create(versionPath)
+	if err != nil {
+		return fmt.Errorf("failed to create version file: %w", err)
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return fmt.Errorf("failed to archive version: %w", err)
+	}
+	dest.Close()
+
+	file.Versions = append(file.Versions, FileVersion{
+		Version:   version,
+		Path:      versionPath,
+		Checksum:  file.Checksum,
+		Size:      file.Size,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// GetVersion returns the archived path of one prior version.
+func (fm *FileManager) GetVersion(fileID string, version int) (string, error) {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return "", fmt.Errorf("file %s not found", fileID)
+	}
+	for _, v := range file.Versions {
+		if v.Version == version {
+			return v.Path, nil
+		}
+	}
+	return "", fmt.Errorf("file %s has no version %d", fileID, version)
+}
+
+// RestoreVersion makes an archived version current again, archiving the
+// present content first so nothing is lost.
+func (fm *FileManager) RestoreVersion(fileID string, version int) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	var target *FileVersion
+	for i := range file.Versions {
+		if file.Versions[i].Version == version {
+			target = &file.Versions[i]
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("file %s has no version %d", fileID, version)
+	}
+
+	if err := fm.archiveVersionLocked(file); err != nil {
+		return err
+	}
+
+	src, err := os.Open(target.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open version %d: %w", version, err)
+	}
+	defer src.Close()
+	dest, err := os.Create(file.Path)
+	if err != nil {
+		return fmt.Errorf("failed to restore version: %w", err)
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return fmt.Errorf("failed to restore version: %w", err)
+	}
+	dest.Close()
+
+	file.Checksum = target.Checksum
+	file.Size = target.Size
+	file.ModifiedAt = time.Now()
+	return nil
+}
+
+// pendingUpload tracks one chunked upload in flight.
+type pendingUpload struct {
+	name     string
+	owner    string
+	total    int64
+	checksum string
+	tmpPath  string
+	received int64
+}
+
+// BeginUpload opens a chunked upload for a file of the declared size and
+// checksum, returning an upload ID for subsequent chunks.
+func (fm *FileManager) BeginUpload(name, owner string, totalSize int64, checksum string) (string, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if !fm.allowedTypes[ext] {
+		return "", fmt.Errorf("file type %s is not allowed", ext)
+	}
+	if totalSize <= 0 || totalSize > fm.maxFileSize {
+		return "", fmt.Errorf("declared size %d outside allowed range", totalSize)
+	}
+
+	uploadID := newConvID()
+	tmpPath := filepath.Join(fm.storageDir, ".upload-"+uploadID)
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload staging file: %w", err)
+	}
+	f.Close()
+
+	fm.uploads[uploadID] = &pendingUpload{
+		name:     name,
+		owner:    owner,
+		total:    totalSize,
+		checksum: checksum,
+		tmpPath:  tmpPath,
+	}
+	return uploadID, nil
+}
+
+// UploadChunk appends one chunk at the given offset. Chunks must arrive in
+// order; after an interruption the client queries UploadStatus and resumes
+// from the reported offset.
+func (fm *FileManager) UploadChunk(uploadID string, offset int64, data []byte) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	upload, ok := fm.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("upload %s not found", uploadID)
+	}
+	if offset != upload.received {
+		return fmt.Errorf("unexpected chunk offset %d, next expected %d", offset, upload.received)
+	}
+	if upload.received+int64(len(data)) > upload.total {
+		return fmt.Errorf("chunk overruns declared size %d", upload.total)
+	}
+
+	f, err := os.OpenFile(upload.tmpPath, os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open upload staging file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteAt(data, offset); err != nil {
+		return fmt.Errorf("failed to write chunk: %w", err)
+	}
+	upload.received += int64(len(data))
+	return nil
+}
+
+// UploadStatus reports how many bytes of the upload have been received.
+func (fm *FileManager) UploadStatus(uploadID string) (int64, error) {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	upload, ok := fm.uploads[uploadID]
+	if !ok {
+		return 0, fmt.Errorf("upload %s not found", uploadID)
+	}
+	return upload.received, nil
+}
+
+// CompleteUpload verifies the declared size and checksum, then promotes
+// the staged file to a tracked SharedFile.
+func (fm *FileManager) CompleteUpload(uploadID string) (*SharedFile, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	upload, ok := fm.uploads[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload %s not found", uploadID)
+	}
+	if upload.received != upload.total {
+		return nil, fmt.Errorf("upload incomplete: %d of %d bytes received", upload.received, upload.total)
+	}
+
+	checksum, err := fm.calculateChecksum(upload.tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	if upload.checksum != "" && checksum != upload.checksum {
+		os.Remove(upload.tmpPath)
+		delete(fm.uploads, uploadID)
+		return nil, fmt.Errorf("checksum mismatch: upload corrupt")
+	}
+
+	name := filepath.Base(upload.name)
+	destPath := filepath.Join(fm.storageDir, name)
+
+	// Completing an upload under an existing name takes the same path as
+	// UploadFile: archive the current content as a version and update the
+	// existing record in place rather than registering a duplicate
+	existing := fm.findByNameLocked(name)
+	if existing != nil {
+		if !fm.hasPermission(existing, upload.owner, PermWrite) {
+			os.Remove(upload.tmpPath)
+			delete(fm.uploads, uploadID)
+			return nil, fmt.Errorf("%s may not overwrite %s", upload.owner, existing.Name)
+		}
+		if err := fm.archiveVersionLocked(existing); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.Rename(upload.tmpPath, destPath); err != nil {
+		return nil, fmt.Errorf("failed to finalize upload: %w", err)
+	}
+	delete(fm.uploads, uploadID)
+
+	if existing != nil {
+		existing.Size = upload.total
+		existing.Checksum = checksum
+		existing.ModifiedAt = time.Now()
+		fm.emitEventLocked(FileEvent{Type: "file_updated", FileID: existing.ID, FileName: name, UserID: upload.owner})
+		return existing, nil
+	}
+
+	file := &SharedFile{
+		ID:         newConvID(),
+		Name:       name,
+		Path:       destPath,
+		Owner:      upload.owner,
+		Size:       upload.total,
+		Type:       strings.ToLower(filepath.Ext(upload.name)),
+		Checksum:   checksum,
+		UploadedAt: time.Now(),
+		ModifiedAt: time.Now(),
+	}
+	fm.files[file.ID] = file
+	return file, nil
+}
+
+// GetFile returns the metadata for fileID.
+func (fm *FileManager) GetFile(fileID string) (*SharedFile, error) {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", fileID)
+	}
+	return file, nil
+}
+
+// VerifyFile re-hashes the stored file and compares it against the
+// recorded checksum, detecting tampering or corruption.
+func (fm *FileManager) VerifyFile(fileID string) (bool, error) {
+	fm.mu.RLock()
+	file, ok := fm.files[fileID]
+	fm.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("file %s not found", fileID)
+	}
+
+	checksum, err := fm.calculateChecksum(file.Path)
+	if err != nil {
+		return false, err
+	}
+	return checksum == file.Checksum, nil
+}
+
+// hasPermission reports whether the user may take the given action on this
+// specific file: owners may do anything, everyone else needs an explicit
+// grant.
+func (fm *FileManager) hasPermission(file *SharedFile, userID string, perm FilePermission) bool {
+	if file.Owner == userID {
+		return true
+	}
+	for _, granted := range file.Grants[userID] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantPermission gives userID the listed permissions on a file. Only the
+// owner may grant.
+func (fm *FileManager) GrantPermission(fileID, ownerID, userID string, perms ...FilePermission) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	if file.Owner != ownerID {
+		return fmt.Errorf("%s may not grant permissions on %s", ownerID, file.Name)
+	}
+	if file.Grants == nil {
+		file.Grants = make(map[string][]FilePermission)
+	}
+	for _, perm := range perms {
+		already := false
+		for _, granted := range file.Grants[userID] {
+			if granted == perm {
+				already = true
+				break
+			}
+		}
+		if !already {
+			file.Grants[userID] = append(file.Grants[userID], perm)
+		}
+	}
+	return nil
+}
+
+// DeleteFile removes a stored file and its metadata.
+func (fm *FileManager) DeleteFile(fileID, userID string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	if !fm.hasPermission(file, userID, PermDelete) {
+		return fmt.Errorf("%s may not delete %s", userID, file.Name)
+	}
+	if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stored file: %w", err)
+	}
+	delete(fm.files, fileID)
+	fm.emitEventLocked(FileEvent{Type: "file_deleted", FileID: fileID, FileName: file.Name, UserID: userID})
+	return nil
+}
+
+// ShareLink is one issued share token and its scope.
+type ShareLink struct {
+	Token     string    `json:"token"`
+	FileID    string    `json:"file_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// newShareToken returns an unguessable token from crypto/rand.
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ShareFile issues a share link for the file that stops resolving after
+// expiresIn.
+func (fm *FileManager) ShareFile(fileID string, expiresIn time.Duration) (string, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return "", fmt.Errorf("file %s not found", fileID)
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return "", err
+	}
+	fm.shareLinks[token] = ShareLink{
+		Token:     token,
+		FileID:    fileID,
+		ExpiresAt: time.Now().Add(expiresIn),
+	}
+	return fmt.Sprintf("share://%s/%s", token, file.Name), nil
+}
+
+// ResolveShareLink exchanges a valid, unexpired token for the shared file.
+func (fm *FileManager) ResolveShareLink(token string) (*SharedFile, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	link, ok := fm.shareLinks[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown share token")
+	}
+	if time.Now().After(link.ExpiresAt) {
+		delete(fm.shareLinks, token)
+		return nil, fmt.Errorf("share link has expired")
+	}
+	file, ok := fm.files[link.FileID]
+	if !ok {
+		delete(fm.shareLinks, token)
+		return nil, fmt.Errorf("shared file no longer exists")
+	}
+	return file, nil
+}
+
+// RevokeShareLink invalidates a previously issued token.
+func (fm *FileManager) RevokeShareLink(token string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if _, ok := fm.shareLinks[token]; !ok {
+		return fmt.Errorf("unknown share token")
+	}
+	delete(fm.shareLinks, token)
+	return nil
+}
+
+// DownloadFile returns the stored path and counts the download. The file
+// must be public or the user must hold read permission.
+func (fm *FileManager) DownloadFile(fileID, userID string) (string, error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return "", fmt.Errorf("file %s not found", fileID)
+	}
+	if !file.Public && !fm.hasPermission(file, userID, PermRead) {
+		return "", fmt.Errorf("%s may not read %s", userID, file.Name)
+	}
+	file.Downloads++
+	return file.Path, nil
+}
+
+// AddTags attaches tags to a file, keeping each tag at most once.
+func (fm *FileManager) AddTags(fileID string, tags ...string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	for _, tag := range tags {
+		if !hasFileTag(file, tag) {
+			file.Tags = append(file.Tags, tag)
+		}
+	}
+	file.ModifiedAt = time.Now()
+	return nil
+}
+
+// RemoveTags detaches tags from a file; absent tags are ignored.
+func (fm *FileManager) RemoveTags(fileID string, tags ...string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, ok := fm.files[fileID]
+	if !ok {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	for _, tag := range tags {
+		for i, existing := range file.Tags {
+			if existing == tag {
+				file.Tags = append(file.Tags[:i], file.Tags[i+1:]...)
+				break
+			}
+		}
+	}
+	file.ModifiedAt = time.Now()
+	return nil
+}
+
+func hasFileTag(file *SharedFile, tag string) bool {
+	for _, existing := range file.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadTo streams the file's content to w in chunks, reporting progress
+// after each chunk so the UI can drive a progress bar. The content is
+// hashed while copying and validated against the stored checksum. The file
+// must be public or the user must hold read permission.
+func (fm *FileManager) DownloadTo(fileID, userID string, w io.Writer, progress func(copied, total int64)) error {
+	fm.mu.Lock()
+	file, ok := fm.files[fileID]
+	if !ok {
+		fm.mu.Unlock()
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	if !file.Public && !fm.hasPermission(file, userID, PermRead) {
+		fm.mu.Unlock()
+		return fmt.Errorf("%s may not read %s", userID, file.Name)
+	}
+	path := file.Path
+	total := file.Size
+	expected := file.Checksum
+	file.Downloads++
+	fm.mu.Unlock()
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open stored file: %w", err)
+	}
+	defer src.Close()
+
+	h := sha256.New()
+	out := io.MultiWriter(w, h)
+	buf := make([]byte, 32*1024)
+	var copied int64
+	for {
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return fmt.Errorf("failed to write download: %w", err)
+			}
+			copied += int64(n)
+			if progress != nil {
+				progress(copied, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read stored file: %w", readErr)
+		}
+	}
+
+	if checksum := hex.EncodeToString(h.Sum(nil)); checksum != expected {
+		return fmt.Errorf("download checksum mismatch: stored file is corrupt")
+	}
+	return nil
+}
+
+// ListFiles returns files matching the filter map, which understands
+// "type", "owner", "public", "tags" (with "tags_match" of "any" or
+// "all", defaulting to any), and a free-text "query" against name and
+// description. Results are sorted by ModifiedAt, newest first.
+func (fm *FileManager) ListFiles(filter map[string]interface{}) []*SharedFile {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	var out []*SharedFile
+	for _, file := range fm.files {
+		if t, ok := filter["type"].(string); ok && file.Type != t {
+			continue
+		}
+		if owner, ok := filter["owner"].(string); ok && file.Owner != owner {
+			continue
+		}
+		if public, ok := filter["public"].(bool); ok && file.Public != public {
+			continue
+		}
+		if tags, ok := filter["tags"].([]string); ok && len(tags) > 0 {
+			matchAll := filter["tags_match"] == "all"
+			matched := 0
+			for _, tag := range tags {
+				if hasFileTag(file, tag) {
+					matched++
+				}
+			}
+			if matchAll && matched != len(tags) {
+				continue
+			}
+			if !matchAll && matched == 0 {
+				continue
+			}
+		}
+		if query, ok := filter["query"].(string); ok && query != "" {
+			q := strings.ToLower(query)
+			if !strings.Contains(strings.ToLower(file.Name), q) &&
+				!strings.Contains(strings.ToLower(file.Description), q) {
+				continue
+			}
+		}
+		out = append(out, file)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].ModifiedAt.Equal(out[j].ModifiedAt) {
+			return out[i].ModifiedAt.After(out[j].ModifiedAt)
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,573 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTempFile drops content into a fresh file and returns its path.
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	return path
+}
+
+// Test SHA-256 integrity verification of stored files
+func TestVerifyFileDetectsCorruption(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	file, err := fm.UploadFile(writeTempFile(t, "notes.txt", "important data"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if len(file.Checksum) != 64 {
+		t.Errorf("Checksum is not hex SHA-256 (len %d): %s", len(file.Checksum), file.Checksum)
+	}
+
+	ok, err := fm.VerifyFile(file.ID)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if !ok {
+		t.Error("Untouched file failed verification")
+	}
+
+	// Corrupt the stored copy; verification must now fail
+	if err := os.WriteFile(file.Path, []byte("tampered"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	ok, err = fm.VerifyFile(file.ID)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if ok {
+		t.Error("Corrupted file passed verification")
+	}
+}
+
+// Test that delete permission is per-file, not "owns anything"
+func TestDeletePermissionIsPerFile(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	aliceFile, err := fm.UploadFile(writeTempFile(t, "alice.txt", "alice's file"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	bobFile, err := fm.UploadFile(writeTempFile(t, "bob.txt", "bob's file"), "bob")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	// Bob owns a file, but that must not let him delete Alice's
+	if err := fm.DeleteFile(aliceFile.ID, "bob"); err == nil {
+		t.Error("Non-owner without a grant deleted someone else's file")
+	}
+
+	// A granted user can delete; grants come only from the owner
+	if err := fm.GrantPermission(bobFile.ID, "alice", "carol", PermDelete); err == nil {
+		t.Error("Non-owner was able to grant permissions")
+	}
+	if err := fm.GrantPermission(bobFile.ID, "bob", "carol", PermDelete); err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+	if err := fm.DeleteFile(bobFile.ID, "carol"); err != nil {
+		t.Errorf("Granted user could not delete: %v", err)
+	}
+
+	// Owners always can
+	if err := fm.DeleteFile(aliceFile.ID, "alice"); err != nil {
+		t.Errorf("Owner could not delete own file: %v", err)
+	}
+}
+
+// Test that overwriting someone else's file requires a write grant
+func TestOverwriteRequiresWritePermission(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	orig, err := fm.UploadFile(writeTempFile(t, "shared.txt", "alice's content"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	// An ungranted user may not overwrite, directly or chunked
+	if _, err := fm.UploadFile(writeTempFile(t, "shared.txt", "bob's content"), "bob"); err == nil {
+		t.Error("Non-owner without a write grant overwrote the file")
+	}
+	content := []byte("bob's chunked content")
+	sum := sha256.Sum256(content)
+	uploadID, err := fm.BeginUpload("shared.txt", "bob", int64(len(content)), hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("BeginUpload failed: %v", err)
+	}
+	if err := fm.UploadChunk(uploadID, 0, content); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	if _, err := fm.CompleteUpload(uploadID); err == nil {
+		t.Error("Non-owner without a write grant completed an overwriting upload")
+	}
+	if ok, _ := fm.VerifyFile(orig.ID); !ok {
+		t.Error("Denied overwrite still changed the stored content")
+	}
+
+	// With a write grant the overwrite goes through and keeps the owner
+	if err := fm.GrantPermission(orig.ID, "alice", "bob", PermWrite); err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+	updated, err := fm.UploadFile(writeTempFile(t, "shared.txt", "bob's content"), "bob")
+	if err != nil {
+		t.Fatalf("Granted overwrite failed: %v", err)
+	}
+	if updated.ID != orig.ID {
+		t.Errorf("Overwrite registered a new ID %s, want %s", updated.ID, orig.ID)
+	}
+	if updated.Owner != "alice" {
+		t.Errorf("Overwrite changed the owner to %s", updated.Owner)
+	}
+}
+
+// Test that downloads require a read grant unless the file is public
+func TestDownloadRequiresReadPermission(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	file, err := fm.UploadFile(writeTempFile(t, "private.txt", "secret"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	if _, err := fm.DownloadFile(file.ID, "bob"); err == nil {
+		t.Error("Non-owner without a read grant downloaded the file")
+	}
+	if err := fm.DownloadTo(file.ID, "bob", &strings.Builder{}, nil); err == nil {
+		t.Error("Non-owner without a read grant streamed the file")
+	}
+
+	// Owners and granted users can read
+	if _, err := fm.DownloadFile(file.ID, "alice"); err != nil {
+		t.Errorf("Owner could not download: %v", err)
+	}
+	if err := fm.GrantPermission(file.ID, "alice", "bob", PermRead); err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+	if _, err := fm.DownloadFile(file.ID, "bob"); err != nil {
+		t.Errorf("Granted user could not download: %v", err)
+	}
+
+	// Public files are readable by anyone
+	file.Public = true
+	if _, err := fm.DownloadFile(file.ID, "carol"); err != nil {
+		t.Errorf("Public file was not readable: %v", err)
+	}
+}
+
+// Test that an interrupted chunked upload resumes and verifies
+func TestChunkedUploadResume(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	content := []byte("the quick brown fox jumps over the lazy dog")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	uploadID, err := fm.BeginUpload("fox.txt", "alice", int64(len(content)), checksum)
+	if err != nil {
+		t.Fatalf("BeginUpload failed: %v", err)
+	}
+
+	// First chunk lands, then the "connection drops"
+	if err := fm.UploadChunk(uploadID, 0, content[:16]); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+
+	// Completing early must fail
+	if _, err := fm.CompleteUpload(uploadID); err == nil {
+		t.Error("Expected CompleteUpload of a partial upload to fail")
+	}
+
+	// Resume from the reported offset; a wrong offset is rejected
+	offset, err := fm.UploadStatus(uploadID)
+	if err != nil {
+		t.Fatalf("UploadStatus failed: %v", err)
+	}
+	if offset != 16 {
+		t.Fatalf("Expected resume offset 16, got %d", offset)
+	}
+	if err := fm.UploadChunk(uploadID, 0, content[:16]); err == nil {
+		t.Error("Expected out-of-order chunk to be rejected")
+	}
+	if err := fm.UploadChunk(uploadID, offset, content[offset:]); err != nil {
+		t.Fatalf("Resumed chunk failed: %v", err)
+	}
+
+	file, err := fm.CompleteUpload(uploadID)
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+	if file.Checksum != checksum {
+		t.Errorf("Stored checksum %s != declared %s", file.Checksum, checksum)
+	}
+	if ok, _ := fm.VerifyFile(file.ID); !ok {
+		t.Error("Completed upload failed verification")
+	}
+}
+
+// Test that completing a chunked upload under an existing name updates
+// the record in place instead of registering a duplicate
+func TestChunkedUploadOverExistingFile(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	orig, err := fm.UploadFile(writeTempFile(t, "report.txt", "first draft"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	content := []byte("second draft, arriving in chunks")
+	sum := sha256.Sum256(content)
+	uploadID, err := fm.BeginUpload("report.txt", "alice", int64(len(content)), hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("BeginUpload failed: %v", err)
+	}
+	if err := fm.UploadChunk(uploadID, 0, content); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	file, err := fm.CompleteUpload(uploadID)
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+
+	if file.ID != orig.ID {
+		t.Errorf("Chunked re-upload registered a new ID %s, want %s", file.ID, orig.ID)
+	}
+	if files := fm.ListFiles(nil); len(files) != 1 {
+		t.Errorf("Expected 1 listed file after re-upload, got %d", len(files))
+	}
+	// The record's checksum must track the new content
+	if ok, _ := fm.VerifyFile(orig.ID); !ok {
+		t.Error("Re-uploaded file failed verification against its record")
+	}
+	// The previous content is archived as a version
+	if len(file.Versions) != 1 {
+		t.Fatalf("Expected 1 archived version, got %d", len(file.Versions))
+	}
+	archived, err := os.ReadFile(file.Versions[0].Path)
+	if err != nil {
+		t.Fatalf("Failed to read archived version: %v", err)
+	}
+	if string(archived) != "first draft" {
+		t.Errorf("Archived version holds %q, want the original content", archived)
+	}
+}
+
+// Test that a corrupt chunked upload is rejected at completion
+func TestChunkedUploadChecksumMismatch(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	uploadID, err := fm.BeginUpload("data.txt", "alice", 4, strings.Repeat("0", 64))
+	if err != nil {
+		t.Fatalf("BeginUpload failed: %v", err)
+	}
+	if err := fm.UploadChunk(uploadID, 0, []byte("abcd")); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	if _, err := fm.CompleteUpload(uploadID); err == nil {
+		t.Error("Expected checksum mismatch to fail the upload")
+	}
+}
+
+// Test directory upload with recursion, type skipping, and the report
+func TestUploadDirPartialSuccess(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+	fm.SetAllowedTypes([]string{"txt", ".md"})
+	fm.SetMaxFileSize(64)
+
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("ok"), 0644)
+	os.WriteFile(filepath.Join(src, "b.bin"), []byte("nope"), 0644)
+	os.WriteFile(filepath.Join(src, "big.txt"), make([]byte, 128), 0644)
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "sub", "c.md"), []byte("notes"), 0644)
+
+	// Non-recursive: only the top level is considered
+	uploaded, skipped, err := fm.UploadDir(src, "alice", false)
+	if err != nil {
+		t.Fatalf("UploadDir failed: %v", err)
+	}
+	if len(uploaded) != 1 || uploaded[0].Name != "a.txt" {
+		t.Errorf("Non-recursive upload wrong: %v", uploaded)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("Expected 2 skips (type, size), got %v", skipped)
+	}
+
+	// Recursive picks up the nested markdown file too
+	fm2, _ := NewFileManager(t.TempDir())
+	fm2.SetAllowedTypes([]string{"txt", "md"})
+	fm2.SetMaxFileSize(64)
+	uploaded, skipped, err = fm2.UploadDir(src, "alice", true)
+	if err != nil {
+		t.Fatalf("Recursive UploadDir failed: %v", err)
+	}
+	if len(uploaded) != 2 {
+		t.Errorf("Expected 2 recursive uploads, got %d", len(uploaded))
+	}
+	if len(skipped) != 2 {
+		t.Errorf("Expected 2 skips, got %v", skipped)
+	}
+	for _, skip := range skipped {
+		if skip.Reason == "" {
+			t.Errorf("Skip for %s has no reason", skip.Path)
+		}
+	}
+}
+
+// extractShareToken pulls the token out of a share:// URL.
+func extractShareToken(t *testing.T, url string) string {
+	t.Helper()
+	trimmed := strings.TrimPrefix(url, "share://")
+	idx := strings.Index(trimmed, "/")
+	if idx < 0 {
+		t.Fatalf("Malformed share URL: %s", url)
+	}
+	return trimmed[:idx]
+}
+
+// Test share-link resolution, expiry, revocation, and unknown tokens
+func TestShareLinkLifecycle(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+	file, err := fm.UploadFile(writeTempFile(t, "share.txt", "shared"), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	// A valid link resolves to the file
+	url, err := fm.ShareFile(file.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+	token := extractShareToken(t, url)
+	resolved, err := fm.ResolveShareLink(token)
+	if err != nil {
+		t.Fatalf("ResolveShareLink failed: %v", err)
+	}
+	if resolved.ID != file.ID {
+		t.Errorf("Resolved wrong file: %s", resolved.ID)
+	}
+
+	// An expired link is rejected
+	expiredURL, err := fm.ShareFile(file.ID, -time.Minute)
+	if err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+	if _, err := fm.ResolveShareLink(extractShareToken(t, expiredURL)); err == nil {
+		t.Error("Expected expired link to be rejected")
+	}
+
+	// A revoked link stops resolving
+	if err := fm.RevokeShareLink(token); err != nil {
+		t.Fatalf("RevokeShareLink failed: %v", err)
+	}
+	if _, err := fm.ResolveShareLink(token); err == nil {
+		t.Error("Expected revoked link to be rejected")
+	}
+
+	// Unknown tokens error; revoking twice errors too
+	if _, err := fm.ResolveShareLink("no-such-token"); err == nil {
+		t.Error("Expected unknown token to be rejected")
+	}
+	if err := fm.RevokeShareLink(token); err == nil {
+		t.Error("Expected double revoke to error")
+	}
+}
+
+// Test tag filters in match-any and match-all modes plus combined filters
+func TestListFilesTagFiltering(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	a, _ := fm.UploadFile(writeTempFile(t, "report.txt", "q3 numbers"), "alice")
+	b, _ := fm.UploadFile(writeTempFile(t, "draft.md", "ideas"), "alice")
+	c, _ := fm.UploadFile(writeTempFile(t, "readme.md", "docs"), "bob")
+
+	fm.AddTags(a.ID, "finance", "urgent")
+	fm.AddTags(b.ID, "finance")
+	fm.AddTags(c.ID, "docs")
+
+	// Match-any: either tag qualifies
+	files := fm.ListFiles(map[string]interface{}{"tags": []string{"finance", "docs"}})
+	if len(files) != 3 {
+		t.Errorf("Match-any expected 3 files, got %d", len(files))
+	}
+
+	// Match-all: both tags required
+	files = fm.ListFiles(map[string]interface{}{
+		"tags": []string{"finance", "urgent"}, "tags_match": "all",
+	})
+	if len(files) != 1 || files[0].ID != a.ID {
+		t.Errorf("Match-all expected only the report, got %v", files)
+	}
+
+	// Combined type+tag filtering
+	files = fm.ListFiles(map[string]interface{}{
+		"type": ".md", "tags": []string{"finance"},
+	})
+	if len(files) != 1 || files[0].ID != b.ID {
+		t.Errorf("Combined filter expected only the draft, got %v", files)
+	}
+
+	// Free-text query hits names case-insensitively
+	files = fm.ListFiles(map[string]interface{}{"query": "README"})
+	if len(files) != 1 || files[0].ID != c.ID {
+		t.Errorf("Query expected only the readme, got %v", files)
+	}
+
+	// Tag removal takes effect
+	fm.RemoveTags(a.ID, "urgent")
+	files = fm.ListFiles(map[string]interface{}{
+		"tags": []string{"finance", "urgent"}, "tags_match": "all",
+	})
+	if len(files) != 0 {
+		t.Errorf("Expected no files after tag removal, got %v", files)
+	}
+}
+
+// Test version history across re-uploads and restore
+func TestFileVersionHistory(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	upload := func(content string) *SharedFile {
+		t.Helper()
+		path := filepath.Join(dir, "doc.txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+		file, err := fm.UploadFile(path, "alice")
+		if err != nil {
+			t.Fatalf("UploadFile failed: %v", err)
+		}
+		return file
+	}
+
+	v1 := upload("version one")
+	firstChecksum := v1.Checksum
+	v2 := upload("version two")
+	v3 := upload("version three")
+
+	if v2.ID != v1.ID || v3.ID != v1.ID {
+		t.Fatal("Re-uploads should keep the same file ID")
+	}
+	if len(v3.Versions) != 2 {
+		t.Fatalf("Expected 2 archived versions, got %d", len(v3.Versions))
+	}
+
+	// The first archived version carries the original checksum
+	path, err := fm.GetVersion(v1.ID, 1)
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "version one" {
+		t.Errorf("Archived v1 content wrong: %q", data)
+	}
+
+	// Restore v1 and confirm the live checksum matches it again
+	if err := fm.RestoreVersion(v1.ID, 1); err != nil {
+		t.Fatalf("RestoreVersion failed: %v", err)
+	}
+	restored, _ := fm.GetFile(v1.ID)
+	if restored.Checksum != firstChecksum {
+		t.Errorf("Restored checksum %s != original %s", restored.Checksum, firstChecksum)
+	}
+	if ok, _ := fm.VerifyFile(v1.ID); !ok {
+		t.Error("Restored file failed verification")
+	}
+
+	// Restoring a missing version errors
+	if err := fm.RestoreVersion(v1.ID, 99); err == nil {
+		t.Error("Expected restore of unknown version to fail")
+	}
+}
+
+// Test streaming downloads report monotonic progress and verify content
+func TestDownloadToProgress(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager failed: %v", err)
+	}
+	content := strings.Repeat("stream me ", 10000)
+	file, err := fm.UploadFile(writeTempFile(t, "stream.txt", content), "alice")
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	var buf strings.Builder
+	var last int64 = -1
+	calls := 0
+	err = fm.DownloadTo(file.ID, "alice", &buf, func(copied, total int64) {
+		calls++
+		if copied <= last {
+			t.Errorf("Progress went backwards: %d after %d", copied, last)
+		}
+		if total != file.Size {
+			t.Errorf("Progress total %d != file size %d", total, file.Size)
+		}
+		last = copied
+	})
+	if err != nil {
+		t.Fatalf("DownloadTo failed: %v", err)
+	}
+	if calls == 0 {
+		t.Fatal("Progress callback never invoked")
+	}
+	if last != file.Size {
+		t.Errorf("Final progress %d != total %d", last, file.Size)
+	}
+	if buf.String() != content {
+		t.Error("Downloaded content does not match upload")
+	}
+
+	// A corrupted stored file fails the post-copy checksum
+	if err := os.WriteFile(file.Path, []byte("tampered"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	if err := fm.DownloadTo(file.ID, "alice", &strings.Builder{}, nil); err == nil {
+		t.Error("Expected corrupted download to fail checksum validation")
+	}
+}
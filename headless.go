@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ==================== HEADLESS MODE ====================
+
+// headlessReport is the JSON document --headless writes to stdout, so
+// pipelines can inspect the tool's state without a terminal.
+type headlessReport struct {
+	GeneratedAt  time.Time            `json:"generated_at"`
+	Secrets      []string             `json:"secrets"`
+	SecretsError string               `json:"secrets_error,omitempty"`
+	Conversation headlessConversation `json:"conversation"`
+	Agents       []AgentStatus        `json:"agents"`
+}
+
+// headlessConversation summarizes the demo conversation run headlessly.
+type headlessConversation struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	Participants []string `json:"participants"`
+	Messages     int      `json:"messages"`
+}
+
+// runHeadless drives the core managers without bubbletea: it lists the
+// stored secret names, runs one round of a managed conversation across
+// the default agents, and dumps each agent's status, emitting the whole
+// report as JSON on out.
+func runHeadless(out io.Writer) error {
+	report := headlessReport{
+		GeneratedAt: time.Now(),
+		Secrets:     []string{},
+	}
+
+	secrets, err := loadSecrets()
+	if err != nil {
+		report.SecretsError = err.Error()
+	} else {
+		for _, secret := range secrets {
+			report.Secrets = append(report.Secrets, secret.Name)
+		}
+		sort.Strings(report.Secrets)
+	}
+
+	am := NewAgentManager()
+	if err := am.initializeAgents(); err != nil {
+		return fmt.Errorf("failed to initialize agents: %w", err)
+	}
+
+	var participants []string
+	for _, cfg := range defaultAgentConfigs() {
+		participants = append(participants, cfg.ID)
+	}
+	cm := NewConversationManager()
+	convID := newConvID()
+	if err := cm.CreateConversation(convID, "democratic", participants); err != nil {
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+	for _, id := range participants {
+		msg := ConvoMessage{
+			ID:        newConvID(),
+			Sender:    id,
+			Content:   fmt.Sprintf("%s reporting in", id),
+			Timestamp: time.Now(),
+		}
+		if err := cm.AddMessage(convID, msg); err != nil {
+			return fmt.Errorf("failed to add message: %w", err)
+		}
+	}
+	state, err := cm.GetConversation(convID)
+	if err != nil {
+		return fmt.Errorf("failed to read conversation: %w", err)
+	}
+	report.Conversation = headlessConversation{
+		ID:           state.ID,
+		Type:         string(state.Type),
+		Participants: state.Participants,
+		Messages:     len(state.Messages),
+	}
+
+	report.Agents = am.GetActiveAgents()
+	sort.Slice(report.Agents, func(i, j int) bool {
+		return report.Agents[i].AgentID < report.Agents[j].AgentID
+	})
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
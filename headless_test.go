@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunHeadlessEmitsValidJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var buf bytes.Buffer
+	if err := runHeadless(&buf); err != nil {
+		t.Fatalf("runHeadless: %v", err)
+	}
+
+	var report headlessReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if report.Conversation.Type != "democratic" {
+		t.Errorf("conversation type = %q", report.Conversation.Type)
+	}
+	if len(report.Conversation.Participants) != len(defaultAgentConfigs()) {
+		t.Errorf("participants = %v", report.Conversation.Participants)
+	}
+	if report.Conversation.Messages != len(report.Conversation.Participants) {
+		t.Errorf("messages = %d, want one per participant", report.Conversation.Messages)
+	}
+	if len(report.Agents) != len(defaultAgentConfigs()) {
+		t.Errorf("agents = %d, want %d", len(report.Agents), len(defaultAgentConfigs()))
+	}
+}
+
+func TestRunHeadlessListsSecretNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	store := `[{"id":"1","name":"api-key","value":"shh","tags":[]},{"id":"2","name":"db-pass","value":"also","tags":[]}]`
+	if err := os.WriteFile(filepath.Join(home, ".go-tui-secrets.json"), []byte(store), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runHeadless(&buf); err != nil {
+		t.Fatalf("runHeadless: %v", err)
+	}
+	var report headlessReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(report.Secrets) != 2 || report.Secrets[0] != "api-key" || report.Secrets[1] != "db-pass" {
+		t.Errorf("secret names = %v", report.Secrets)
+	}
+	// Only names are reported; values must never leak into the output.
+	if bytes.Contains(buf.Bytes(), []byte("shh")) {
+		t.Error("secret value leaked into headless output")
+	}
+}
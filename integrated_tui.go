@@ -0,0 +1,433 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ==================== EVENT HUB ====================
+
+// ChatroomEvent is emitted on chatroom activity.
+type ChatroomEvent struct {
+	Type      string                 `json:"type"`
+	UserID    string                 `json:"user_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// AITUIEvent is emitted on AI TUI activity.
+type AITUIEvent struct {
+	Type      string                 `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventHubHandlers routes each event kind to its consumer.
+type EventHubHandlers struct {
+	OnChatroom func(ChatroomEvent)
+	OnAITUI    func(AITUIEvent)
+	OnFile     func(FileEvent)
+	OnAgent    func(AgentEvent)
+}
+
+// EventHub fans events from the managers out to the UI. The channels
+// carry the managers' own event types so there is one definition of each.
+type EventHub struct {
+	chatroomEvents chan ChatroomEvent
+	aiTUIEvents    chan AITUIEvent
+	fileEvents     chan FileEvent
+	agentEvents    chan AgentEvent
+
+	mu       sync.Mutex
+	handlers EventHubHandlers
+	logger   *FileLogger
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	policy      SendPolicy
+	sendTimeout time.Duration
+	dropped     int64
+}
+
+// SendPolicy controls what a publish does when a hub channel is full.
+type SendPolicy int
+
+const (
+	// SendDrop discards the event immediately and counts it.
+	SendDrop SendPolicy = iota
+	// SendBlockTimeout waits up to the configured timeout, then drops.
+	SendBlockTimeout
+)
+
+// NewEventHub builds a hub with the given per-channel buffer.
+func NewEventHub(buffer int) *EventHub {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	return &EventHub{
+		chatroomEvents: make(chan ChatroomEvent, buffer),
+		aiTUIEvents:    make(chan AITUIEvent, buffer),
+		fileEvents:     make(chan FileEvent, buffer),
+		agentEvents:    make(chan AgentEvent, buffer),
+		done:           make(chan struct{}),
+		sendTimeout:    100 * time.Millisecond,
+	}
+}
+
+// SetSendPolicy picks the backpressure behavior; timeout only applies to
+// SendBlockTimeout and is ignored when non-positive.
+func (h *EventHub) SetSendPolicy(policy SendPolicy, timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.policy = policy
+	if timeout > 0 {
+		h.sendTimeout = timeout
+	}
+}
+
+// DroppedEvents reports how many events were discarded under load.
+func (h *EventHub) DroppedEvents() int64 {
+	return atomic.LoadInt64(&h.dropped)
+}
+
+// sendDeadline returns the extra wait allowed by the current policy, or
+// zero for drop-immediately.
+func (h *EventHub) sendDeadline() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.policy == SendBlockTimeout {
+		return h.sendTimeout
+	}
+	return 0
+}
+
+// SetHandlers installs the routing targets.
+func (h *EventHub) SetHandlers(handlers EventHubHandlers) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers = handlers
+}
+
+// SetLogger taps every routed event into the logger as a SystemEvent,
+// giving a unified audit trail. Pass nil to disable the tap.
+func (h *EventHub) SetLogger(logger *FileLogger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger = logger
+}
+
+// logEvent persists one routed event when the tap is enabled. Logging
+// failures are ignored; the audit trail is best-effort by design.
+func (h *EventHub) logEvent(source, eventType string, data map[string]interface{}) {
+	h.mu.Lock()
+	logger := h.logger
+	h.mu.Unlock()
+	if logger == nil {
+		return
+	}
+	logger.LogEvent(SystemEvent{
+		ID:        newConvID(),
+		Timestamp: time.Now(),
+		Type:      string(EventTypeInfo),
+		Source:    source,
+		Message:   eventType,
+		Data:      data,
+	})
+}
+
+// Connect wires the managers' event handlers into the hub. Nil managers
+// are skipped.
+func (h *EventHub) Connect(am *AgentManager, fm *FileManager) {
+	if am != nil {
+		am.SetEventHandler(func(e AgentEvent) { h.PublishAgent(e) })
+	}
+	if fm != nil {
+		fm.SetEventHandler(func(e FileEvent) { h.PublishFile(e) })
+	}
+}
+
+// PublishChatroom queues a chatroom event for routing. A full channel
+// never blocks the caller: the event is dropped and counted instead.
+func (h *EventHub) PublishChatroom(e ChatroomEvent) bool {
+	select {
+	case h.chatroomEvents <- e:
+		return true
+	default:
+	}
+	if wait := h.sendDeadline(); wait > 0 {
+		select {
+		case h.chatroomEvents <- e:
+			return true
+		case <-time.After(wait):
+		}
+	}
+	atomic.AddInt64(&h.dropped, 1)
+	return false
+}
+
+// PublishAITUI queues an AI TUI event for routing.
+func (h *EventHub) PublishAITUI(e AITUIEvent) bool {
+	select {
+	case h.aiTUIEvents <- e:
+		return true
+	default:
+	}
+	if wait := h.sendDeadline(); wait > 0 {
+		select {
+		case h.aiTUIEvents <- e:
+			return true
+		case <-time.After(wait):
+		}
+	}
+	atomic.AddInt64(&h.dropped, 1)
+	return false
+}
+
+// PublishFile queues a file event for routing.
+func (h *EventHub) PublishFile(e FileEvent) bool {
+	select {
+	case h.fileEvents <- e:
+		return true
+	default:
+	}
+	if wait := h.sendDeadline(); wait > 0 {
+		select {
+		case h.fileEvents <- e:
+			return true
+		case <-time.After(wait):
+		}
+	}
+	atomic.AddInt64(&h.dropped, 1)
+	return false
+}
+
+// PublishAgent queues an agent event for routing.
+func (h *EventHub) PublishAgent(e AgentEvent) bool {
+	select {
+	case h.agentEvents <- e:
+		return true
+	default:
+	}
+	if wait := h.sendDeadline(); wait > 0 {
+		select {
+		case h.agentEvents <- e:
+			return true
+		case <-time.After(wait):
+		}
+	}
+	atomic.AddInt64(&h.dropped, 1)
+	return false
+}
+
+// Start launches the routing loop.
+func (h *EventHub) Start() {
+	h.wg.Add(1)
+	go h.processEvents()
+}
+
+// Stop ends the routing loop and waits for it to drain.
+func (h *EventHub) Stop() {
+	close(h.done)
+	h.wg.Wait()
+}
+
+// processEvents routes each event type to its registered handler.
+func (h *EventHub) processEvents() {
+	defer h.wg.Done()
+	for {
+		h.mu.Lock()
+		handlers := h.handlers
+		h.mu.Unlock()
+
+		select {
+		case <-h.done:
+			return
+		case e := <-h.chatroomEvents:
+			h.logEvent("chatroom", e.Type, e.Data)
+			if handlers.OnChatroom != nil {
+				handlers.OnChatroom(e)
+			}
+		case e := <-h.aiTUIEvents:
+			h.logEvent("ai_tui", e.Type, e.Data)
+			if handlers.OnAITUI != nil {
+				handlers.OnAITUI(e)
+			}
+		case e := <-h.fileEvents:
+			h.logEvent("file_manager", e.Type, e.Data)
+			if handlers.OnFile != nil {
+				handlers.OnFile(e)
+			}
+		case e := <-h.agentEvents:
+			h.logEvent("agent_manager", e.Type, e.Data)
+			if handlers.OnAgent != nil {
+				handlers.OnAgent(e)
+			}
+		}
+	}
+}
+
+// ==================== INTEGRATED TUI ====================
+
+// IntegratedConfig controls which sub-UI is visible and how the
+// integrated view switches between them.
+type IntegratedConfig struct {
+	ChatroomVisible bool          `json:"chatroom_visible"`
+	AITUIVisible    bool          `json:"ai_tui_visible"`
+	AutoSwitch      bool          `json:"auto_switch"`
+	SwitchInterval  time.Duration `json:"switch_interval"`
+}
+
+// IntegratedTUI hosts the chatroom and AI TUI as sub-models and swaps
+// between them.
+type IntegratedTUI struct {
+	config   IntegratedConfig
+	chatroom tea.Model
+	aiTUI    tea.Model
+	width    int
+	height   int
+
+	// autoSwitchSeq invalidates in-flight ticks when auto-switch is
+	// toggled or the interval changes.
+	autoSwitchSeq int
+}
+
+// autoSwitchMsg fires when it is time to alternate the visible view.
+type autoSwitchMsg struct {
+	seq int
+}
+
+// scheduleAutoSwitch arms the next auto-switch tick.
+func (t *IntegratedTUI) scheduleAutoSwitch() tea.Cmd {
+	seq := t.autoSwitchSeq
+	return tea.Tick(t.config.SwitchInterval, func(time.Time) tea.Msg {
+		return autoSwitchMsg{seq: seq}
+	})
+}
+
+// SetSwitchInterval changes the auto-switch cadence, taking effect on the
+// next tick without a restart.
+func (t *IntegratedTUI) SetSwitchInterval(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+	t.config.SwitchInterval = interval
+	if !t.config.AutoSwitch {
+		return nil
+	}
+	t.autoSwitchSeq++
+	return t.scheduleAutoSwitch()
+}
+
+// NewIntegratedTUI starts on the chatroom view.
+func NewIntegratedTUI(chatroom, aiTUI tea.Model) *IntegratedTUI {
+	return &IntegratedTUI{
+		config: IntegratedConfig{
+			ChatroomVisible: true,
+			SwitchInterval:  30 * time.Second,
+		},
+		chatroom: chatroom,
+		aiTUI:    aiTUI,
+	}
+}
+
+// SwitchToChatroom makes the chatroom the visible sub-model.
+func (t *IntegratedTUI) SwitchToChatroom() {
+	t.config.ChatroomVisible = true
+	t.config.AITUIVisible = false
+}
+
+// SwitchToAITUI makes the AI TUI the visible sub-model.
+func (t *IntegratedTUI) SwitchToAITUI() {
+	t.config.AITUIVisible = true
+	t.config.ChatroomVisible = false
+}
+
+// Init implements tea.Model, starting both sub-models.
+func (t *IntegratedTUI) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if t.chatroom != nil {
+		cmds = append(cmds, t.chatroom.Init())
+	}
+	if t.aiTUI != nil {
+		cmds = append(cmds, t.aiTUI.Init())
+	}
+	if t.config.AutoSwitch {
+		cmds = append(cmds, t.scheduleAutoSwitch())
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update implements tea.Model, delegating to the active sub-model.
+func (t *IntegratedTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return t, tea.Quit
+		case "ctrl+t":
+			if t.config.ChatroomVisible {
+				t.SwitchToAITUI()
+			} else {
+				t.SwitchToChatroom()
+			}
+			return t, nil
+		case "ctrl+a":
+			t.config.AutoSwitch = !t.config.AutoSwitch
+			t.autoSwitchSeq++
+			if t.config.AutoSwitch {
+				return t, t.scheduleAutoSwitch()
+			}
+			return t, nil
+		}
+	case autoSwitchMsg:
+		// Stale ticks from before a toggle or interval change are
+		// dropped so switching can't double-fire.
+		if !t.config.AutoSwitch || msg.seq != t.autoSwitchSeq {
+			return t, nil
+		}
+		if t.config.ChatroomVisible {
+			t.SwitchToAITUI()
+		} else {
+			t.SwitchToChatroom()
+		}
+		return t, t.scheduleAutoSwitch()
+	case tea.WindowSizeMsg:
+		// Both sub-models need the size so the hidden one lays out
+		// correctly when it becomes visible.
+		t.width, t.height = msg.Width, msg.Height
+		var cmds []tea.Cmd
+		if t.chatroom != nil {
+			var cmd tea.Cmd
+			t.chatroom, cmd = t.chatroom.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if t.aiTUI != nil {
+			var cmd tea.Cmd
+			t.aiTUI, cmd = t.aiTUI.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return t, tea.Batch(cmds...)
+	}
+
+	var cmd tea.Cmd
+	if t.config.ChatroomVisible && t.chatroom != nil {
+		t.chatroom, cmd = t.chatroom.Update(msg)
+	} else if t.aiTUI != nil {
+		t.aiTUI, cmd = t.aiTUI.Update(msg)
+	}
+	return t, cmd
+}
+
+// View implements tea.Model, rendering the active sub-model.
+func (t *IntegratedTUI) View() string {
+	if t.config.ChatroomVisible && t.chatroom != nil {
+		return t.chatroom.View()
+	}
+	if t.aiTUI != nil {
+		return t.aiTUI.View()
+	}
+	return "No view active. Press Ctrl+T to switch."
+}
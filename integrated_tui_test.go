@@ -0,0 +1,290 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubModel is a minimal tea.Model whose View returns a fixed label.
+type stubModel struct {
+	label string
+	width int
+}
+
+func (m stubModel) Init() tea.Cmd { return nil }
+
+func (m stubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+	}
+	return m, nil
+}
+
+func (m stubModel) View() string { return m.label }
+
+func TestCtrlTFlipsVisibleMode(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+
+	if view := tui.View(); view != "chatroom" {
+		t.Fatalf("initial view = %q, want chatroom", view)
+	}
+
+	model, _ := tui.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	tui = model.(*IntegratedTUI)
+	if !tui.config.AITUIVisible || tui.config.ChatroomVisible {
+		t.Errorf("Ctrl+T did not switch to AI TUI: %+v", tui.config)
+	}
+	if view := tui.View(); view != "ai-tui" {
+		t.Errorf("view after switch = %q, want ai-tui", view)
+	}
+
+	model, _ = tui.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	tui = model.(*IntegratedTUI)
+	if view := tui.View(); view != "chatroom" {
+		t.Errorf("second Ctrl+T did not switch back, view = %q", view)
+	}
+}
+
+func TestCtrlAToggleAutoSwitch(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+
+	model, _ := tui.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	tui = model.(*IntegratedTUI)
+	if !tui.config.AutoSwitch {
+		t.Error("Ctrl+A did not enable auto-switch")
+	}
+	model, _ = tui.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	tui = model.(*IntegratedTUI)
+	if tui.config.AutoSwitch {
+		t.Error("Ctrl+A did not disable auto-switch")
+	}
+}
+
+func TestAutoSwitchTickAlternatesViews(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+	tui.config.AutoSwitch = true
+	tui.config.SwitchInterval = 10 * time.Millisecond
+
+	// Simulated tick with the current sequence flips the view and arms
+	// the next tick.
+	model, cmd := tui.Update(autoSwitchMsg{seq: tui.autoSwitchSeq})
+	tui = model.(*IntegratedTUI)
+	if view := tui.View(); view != "ai-tui" {
+		t.Errorf("view after tick = %q, want ai-tui", view)
+	}
+	if cmd == nil {
+		t.Error("expected the next tick to be scheduled")
+	}
+
+	model, _ = tui.Update(autoSwitchMsg{seq: tui.autoSwitchSeq})
+	tui = model.(*IntegratedTUI)
+	if view := tui.View(); view != "chatroom" {
+		t.Errorf("view after second tick = %q, want chatroom", view)
+	}
+}
+
+func TestAutoSwitchStaleTickIgnored(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+	tui.config.AutoSwitch = true
+	tui.config.SwitchInterval = 10 * time.Millisecond
+
+	// Toggling off then back on bumps the sequence; an old tick must not
+	// flip the view.
+	stale := tui.autoSwitchSeq
+	model, _ := tui.Update(tea.KeyMsg{Type: tea.KeyCtrlA}) // off
+	tui = model.(*IntegratedTUI)
+	model, _ = tui.Update(autoSwitchMsg{seq: stale})
+	tui = model.(*IntegratedTUI)
+	if view := tui.View(); view != "chatroom" {
+		t.Errorf("stale tick flipped the view to %q", view)
+	}
+}
+
+func TestSetSwitchIntervalReschedules(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+	tui.config.AutoSwitch = true
+
+	old := tui.autoSwitchSeq
+	cmd := tui.SetSwitchInterval(25 * time.Millisecond)
+	if cmd == nil {
+		t.Error("expected a rescheduled tick")
+	}
+	if tui.autoSwitchSeq == old {
+		t.Error("interval change should invalidate in-flight ticks")
+	}
+	if tui.config.SwitchInterval != 25*time.Millisecond {
+		t.Errorf("interval = %v", tui.config.SwitchInterval)
+	}
+}
+
+func TestEventHubRoutesEachType(t *testing.T) {
+	hub := NewEventHub(8)
+	got := make(chan string, 4)
+	hub.SetHandlers(EventHubHandlers{
+		OnChatroom: func(e ChatroomEvent) { got <- "chatroom:" + e.Type },
+		OnAITUI:    func(e AITUIEvent) { got <- "aitui:" + e.Type },
+		OnFile:     func(e FileEvent) { got <- "file:" + e.Type },
+		OnAgent:    func(e AgentEvent) { got <- "agent:" + e.Type },
+	})
+	hub.Start()
+	defer hub.Stop()
+
+	hub.PublishChatroom(ChatroomEvent{Type: "message_sent"})
+	hub.PublishAITUI(AITUIEvent{Type: "theme_changed"})
+	hub.PublishFile(FileEvent{Type: "file_uploaded"})
+	hub.PublishAgent(AgentEvent{Type: "provider_added"})
+
+	want := map[string]bool{
+		"chatroom:message_sent": true,
+		"aitui:theme_changed":   true,
+		"file:file_uploaded":    true,
+		"agent:provider_added":  true,
+	}
+	for i := 0; i < 4; i++ {
+		select {
+		case e := <-got:
+			if !want[e] {
+				t.Errorf("unexpected routed event %q", e)
+			}
+			delete(want, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for events, still missing %v", want)
+		}
+	}
+}
+
+func TestEventHubConnectsManagers(t *testing.T) {
+	hub := NewEventHub(8)
+	got := make(chan string, 2)
+	hub.SetHandlers(EventHubHandlers{
+		OnAgent: func(e AgentEvent) { got <- e.Type },
+	})
+	hub.Start()
+	defer hub.Stop()
+
+	am := NewAgentManager()
+	hub.Connect(am, nil)
+	if err := am.AddProvider(fakeProvider{id: "p", name: "P"}); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+
+	select {
+	case e := <-got:
+		if e != "provider_added" {
+			t.Errorf("routed event = %q, want provider_added", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("manager event never reached the hub handler")
+	}
+}
+
+func TestEventHubLoggerTap(t *testing.T) {
+	logger := NewFileLogger(t.TempDir())
+	hub := NewEventHub(8)
+	hub.SetLogger(logger)
+	hub.Start()
+	defer hub.Stop()
+
+	hub.PublishFile(FileEvent{Type: "file_uploaded", Data: map[string]interface{}{"name": "a.txt"}})
+	hub.PublishAgent(AgentEvent{Type: "task_retry"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	var logged []SystemEvent
+	for time.Now().Before(deadline) {
+		events, _, err := logger.ReadEvents(EventFilter{})
+		if err == nil && len(events) >= 2 {
+			logged = events
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(logged) < 2 {
+		t.Fatalf("expected 2 logged events, got %d", len(logged))
+	}
+
+	sources := map[string]string{}
+	for _, e := range logged {
+		sources[e.Source] = e.Message
+	}
+	if sources["file_manager"] != "file_uploaded" {
+		t.Errorf("file event not logged: %v", sources)
+	}
+	if sources["agent_manager"] != "task_retry" {
+		t.Errorf("agent event not logged: %v", sources)
+	}
+}
+
+func TestEventHubTapDisabledByDefault(t *testing.T) {
+	hub := NewEventHub(8)
+	hub.Start()
+	defer hub.Stop()
+	// Without a logger configured this must simply not panic.
+	hub.PublishAITUI(AITUIEvent{Type: "tick"})
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestPublishDropsWhenFull(t *testing.T) {
+	// No consumer running: a buffer of 2 fills, then sends must drop.
+	hub := NewEventHub(2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			hub.PublishAgent(AgentEvent{Type: "flood"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("publish blocked on a full channel")
+	}
+	if got := hub.DroppedEvents(); got != 3 {
+		t.Errorf("DroppedEvents = %d, want 3", got)
+	}
+}
+
+func TestPublishBlockTimeoutMode(t *testing.T) {
+	hub := NewEventHub(1)
+	hub.SetSendPolicy(SendBlockTimeout, 30*time.Millisecond)
+
+	if !hub.PublishFile(FileEvent{Type: "first"}) {
+		t.Fatal("first publish should succeed")
+	}
+	start := time.Now()
+	if hub.PublishFile(FileEvent{Type: "second"}) {
+		t.Fatal("second publish should time out with no consumer")
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("timed-out publish returned after %v, want it to wait ~30ms", elapsed)
+	}
+	if got := hub.DroppedEvents(); got != 1 {
+		t.Errorf("DroppedEvents = %d, want 1", got)
+	}
+
+	// Once a consumer drains the channel, blocking sends succeed.
+	hub.Start()
+	defer hub.Stop()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if hub.PublishFile(FileEvent{Type: "third"}) {
+			return
+		}
+	}
+	t.Fatal("publish never succeeded after consumer started")
+}
+
+func TestWindowSizeForwardedToBothSubModels(t *testing.T) {
+	tui := NewIntegratedTUI(stubModel{label: "chatroom"}, stubModel{label: "ai-tui"})
+
+	model, _ := tui.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	tui = model.(*IntegratedTUI)
+	if got := tui.chatroom.(stubModel).width; got != 120 {
+		t.Errorf("chatroom width = %d, want 120", got)
+	}
+	if got := tui.aiTUI.(stubModel).width; got != 120 {
+		t.Errorf("aiTUI width = %d, want 120", got)
+	}
+}
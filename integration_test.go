@@ -1,28 +1,36 @@
 package main
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// asAITUIModel unwraps the concrete model from a tea.Model; Update and the
+// pointer-receiver handlers return the two shapes interchangeably.
+func asAITUIModel(t *testing.T, m tea.Model) AITUIModel {
+	t.Helper()
+	switch mm := m.(type) {
+	case AITUIModel:
+		return mm
+	case *AITUIModel:
+		return *mm
+	}
+	t.Fatalf("unexpected model type %T", m)
+	return AITUIModel{}
+}
+
 // Test full TUI integration
 func TestTUIIntegration(t *testing.T) {
-	// Create temporary directory for test data
-	tempDir := t.TempDir()
-
 	// Override the home directory for testing
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tempDir)
-	defer os.Setenv("HOME", originalHome)
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
 
 	// Initialize model
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test initialization
 	cmd := model.Init()
@@ -44,21 +52,19 @@ func TestTUIIntegration(t *testing.T) {
 		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}},
 		// Decrease speed
 		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}},
-		// Reset animation
-		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}},
 		// Clear conversation
 		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}},
-		// Stop recording
-		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}},
+		// Reset animation
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}},
 	}
 
 	for i, msg := range interactions {
-		updatedModel, cmd := model.Update(msg)
+		updatedModel, _ := model.Update(msg)
 		if updatedModel == nil {
 			t.Errorf("Interaction %d should return a model", i)
 		}
 
-		model = updatedModel.(*Model)
+		model = asAITUIModel(t, updatedModel)
 
 		// Verify model is still valid after each interaction
 		if model.animator == nil {
@@ -89,57 +95,51 @@ func TestTUIIntegration(t *testing.T) {
 // Test recording workflow integration
 func TestRecordingWorkflowIntegration(t *testing.T) {
 	tempDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tempDir)
-	defer os.Setenv("HOME", originalHome)
+	t.Setenv("HOME", tempDir)
 
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Start recording
-	model, _ = model.toggleRecording()
+	updated, _ := model.toggleRecording()
+	model = asAITUIModel(t, updated)
 	if !model.isRecording {
 		t.Error("Should be recording")
 	}
 
 	// Simulate some animation updates
 	for i := 0; i < 10; i++ {
-		model.Update(time.Now())
+		updated, _ = model.Update(time.Now())
+		model = asAITUIModel(t, updated)
 	}
 
 	// Stop recording
-	model, _ = model.toggleRecording()
+	updated, _ = model.toggleRecording()
+	model = asAITUIModel(t, updated)
 	if model.isRecording {
 		t.Error("Should not be recording")
 	}
 
-	// Check that conversation was saved
-	dataDir := filepath.Join(tempDir, ".ai-tui-data")
-	files, err := os.ReadDir(dataDir)
+	// Check that the conversation was appended to the log
+	conversationsFile := filepath.Join(tempDir, ".ai-tui-data", "conversations.jsonl")
+	info, err := os.Stat(conversationsFile)
 	if err != nil {
-		t.Fatalf("Failed to read data directory: %v", err)
-	}
-
-	conversationFiles := 0
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), "conversation_") {
-			conversationFiles++
-		}
+		t.Fatalf("Conversation log should exist: %v", err)
 	}
-
-	if conversationFiles == 0 {
-		t.Error("At least one conversation file should be created")
+	if info.Size() == 0 {
+		t.Error("Conversation log should not be empty")
 	}
 }
 
 // Test animation and UI integration
 func TestAnimationUIIntegration(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test that animation updates affect UI
 	initialView := model.View()
 
 	// Update animation
-	model.Update(time.Now())
+	updated, _ := model.Update(time.Now())
+	model = asAITUIModel(t, updated)
 
 	updatedView := model.View()
 
@@ -167,7 +167,7 @@ func TestAnimationUIIntegration(t *testing.T) {
 
 // Test window resize integration
 func TestWindowResizeIntegration(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test various window sizes
 	sizes := []tea.WindowSizeMsg{
@@ -177,31 +177,27 @@ func TestWindowResizeIntegration(t *testing.T) {
 	}
 
 	for i, size := range sizes {
-		updatedModel, cmd := model.Update(size)
+		updatedModel, _ := model.Update(size)
 		if updatedModel == nil {
 			t.Errorf("Resize %d should return a model", i)
 		}
 
-		if cmd == nil {
-			t.Errorf("Resize %d should return a command", i)
-		}
-
-		model = updatedModel.(*Model)
+		model = asAITUIModel(t, updatedModel)
 
-		// Verify animator was updated
-		if model.animator.width != size.Width {
-			t.Errorf("Resize %d: Animator width should be updated", i)
+		// Verify the model tracked the new dimensions
+		if model.width != size.Width {
+			t.Errorf("Resize %d: width should be updated", i)
 		}
 
-		if model.animator.height != size.Height {
-			t.Errorf("Resize %d: Animator height should be updated", i)
+		if model.height != size.Height {
+			t.Errorf("Resize %d: height should be updated", i)
 		}
 	}
 }
 
 // Test mouse interaction integration
 func TestMouseInteractionIntegration(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test clicking on each pane
 	for paneIndex := 0; paneIndex < len(model.panes); paneIndex++ {
@@ -214,12 +210,12 @@ func TestMouseInteractionIntegration(t *testing.T) {
 			Y:    pane.Y + pane.Height/2,
 		}
 
-		updatedModel, cmd := model.Update(mouseMsg)
+		updatedModel, _ := model.Update(mouseMsg)
 		if updatedModel == nil {
 			t.Errorf("Click on pane %d should return a model", paneIndex)
 		}
 
-		model = updatedModel.(*Model)
+		model = asAITUIModel(t, updatedModel)
 
 		// Verify active pane changed
 		if model.activePane != paneIndex {
@@ -231,29 +227,28 @@ func TestMouseInteractionIntegration(t *testing.T) {
 // Test error handling integration
 func TestErrorHandlingIntegration(t *testing.T) {
 	// Test with invalid home directory
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", "/invalid/directory")
-	defer os.Setenv("HOME", originalHome)
+	t.Setenv("HOME", "/invalid/directory")
 
 	// Model should still initialize, even with invalid directory
-	model := initialModel()
+	model := initialAITUIModel()
 
-	if model == nil {
+	if model.logger == nil {
 		t.Error("Model should initialize even with invalid home directory")
 	}
 
 	// Recording should handle errors gracefully
-	model, _ = model.toggleRecording()
+	updated, _ := model.toggleRecording()
+	model = asAITUIModel(t, updated)
 
 	// Should not panic
-	if model == nil {
-		t.Error("Model should still exist after recording toggle with invalid directory")
+	if len(model.View()) == 0 {
+		t.Error("Model should still render after recording toggle with invalid directory")
 	}
 }
 
 // Test concurrent access
 func TestConcurrentAccess(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test concurrent updates
 	done := make(chan bool, 2)
@@ -288,7 +283,7 @@ func TestConcurrentAccess(t *testing.T) {
 
 // Test memory usage
 func TestMemoryUsage(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Simulate extended usage
 	for i := 0; i < 1000; i++ {
@@ -318,24 +313,25 @@ func TestMemoryUsage(t *testing.T) {
 // Test data persistence integration
 func TestDataPersistenceIntegration(t *testing.T) {
 	tempDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tempDir)
-	defer os.Setenv("HOME", originalHome)
+	t.Setenv("HOME", tempDir)
 
 	// Create first model and record data
-	model1 := initialModel()
-	model1, _ = model1.toggleRecording()
+	model1 := initialAITUIModel()
+	updated, _ := model1.toggleRecording()
+	model1 = asAITUIModel(t, updated)
 
 	// Simulate some activity
 	for i := 0; i < 5; i++ {
-		model1.Update(time.Now())
+		updated, _ = model1.Update(time.Now())
+		model1 = asAITUIModel(t, updated)
 		time.Sleep(time.Millisecond * 10)
 	}
 
-	model1, _ = model1.toggleRecording()
+	updated, _ = model1.toggleRecording()
+	model1 = asAITUIModel(t, updated)
 
 	// Create second model and verify data persistence
-	model2 := initialModel()
+	model2 := initialAITUIModel()
 
 	// Both models should have valid loggers
 	if model1.logger == nil || model2.logger == nil {
@@ -352,11 +348,9 @@ func TestDataPersistenceIntegration(t *testing.T) {
 // Test full workflow simulation
 func TestFullWorkflowSimulation(t *testing.T) {
 	tempDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tempDir)
-	defer os.Setenv("HOME", originalHome)
+	t.Setenv("HOME", tempDir)
 
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Simulate typical user session
 	workflow := []struct {
@@ -371,17 +365,17 @@ func TestFullWorkflowSimulation(t *testing.T) {
 		{"Resume animation", tea.KeyMsg{Type: tea.KeySpace}},
 		{"Increase speed", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}}},
 		{"Decrease speed", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}}},
-		{"Reset animation", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}},
 		{"Stop recording", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}},
+		{"Reset animation", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}},
 	}
 
 	for _, step := range workflow {
-		updatedModel, cmd := model.Update(step.msg)
+		updatedModel, _ := model.Update(step.msg)
 		if updatedModel == nil {
 			t.Errorf("Step '%s' should return a model", step.name)
 		}
 
-		model = updatedModel.(*Model)
+		model = asAITUIModel(t, updatedModel)
 
 		// Verify model is still in valid state
 		if model.animator == nil {
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ==================== KEYBINDINGS ====================
+
+// Action names something the UI can do, independent of which key
+// triggers it.
+type Action string
+
+const (
+	ActionNone          Action = ""
+	ActionQuit          Action = "quit"
+	ActionSwitchPane    Action = "switch-pane"
+	ActionToggleSecrets Action = "toggle-secrets"
+	ActionNew           Action = "new"
+	ActionRefresh       Action = "refresh"
+	ActionSave          Action = "save"
+	ActionHelp          Action = "help"
+)
+
+// knownActions is the set of actions a key config may bind.
+var knownActions = map[Action]bool{
+	ActionQuit:          true,
+	ActionSwitchPane:    true,
+	ActionToggleSecrets: true,
+	ActionNew:           true,
+	ActionRefresh:       true,
+	ActionSave:          true,
+	ActionHelp:          true,
+}
+
+// defaultKeyBindings maps key strings (as produced by tea.KeyMsg.String)
+// to their default actions.
+func defaultKeyBindings() map[string]Action {
+	return map[string]Action{
+		"ctrl+c": ActionQuit,
+		"tab":    ActionSwitchPane,
+		"ctrl+x": ActionToggleSecrets,
+		"n":      ActionNew,
+		"r":      ActionRefresh,
+		"ctrl+s": ActionSave,
+		"?":      ActionHelp,
+	}
+}
+
+// KeyMap resolves incoming key presses to actions. User overrides from
+// the config file replace individual defaults; everything else keeps its
+// default binding.
+type KeyMap struct {
+	bindings map[string]Action
+	warnings []string
+}
+
+// DefaultKeyMap returns the built-in bindings.
+func DefaultKeyMap() *KeyMap {
+	return &KeyMap{bindings: defaultKeyBindings()}
+}
+
+// keyMapPath is where user keybindings are read from.
+func keyMapPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".ai-tui-keys.json"
+	}
+	return filepath.Join(home, ".ai-tui-keys.json")
+}
+
+// LoadKeyMap reads a JSON object mapping action names to key strings
+// (e.g. {"quit": "q"}) and merges it over the defaults. A missing file
+// is normal; a broken or partially invalid file records warnings and
+// keeps the defaults rather than failing — a bad config must never lock
+// the user out of the UI.
+func LoadKeyMap(path string) *KeyMap {
+	km := DefaultKeyMap()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			km.warnings = append(km.warnings, fmt.Sprintf("failed to read key config: %v", err))
+		}
+		return km
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		km.warnings = append(km.warnings, fmt.Sprintf("invalid key config %s: %v", path, err))
+		return km
+	}
+	for name, key := range overrides {
+		action := Action(name)
+		if !knownActions[action] {
+			km.warnings = append(km.warnings, fmt.Sprintf("unknown action %q in key config", name))
+			continue
+		}
+		if key == "" {
+			km.warnings = append(km.warnings, fmt.Sprintf("empty key for action %q", name))
+			continue
+		}
+		// Unbind the default key for this action before rebinding it.
+		for k, a := range km.bindings {
+			if a == action {
+				delete(km.bindings, k)
+			}
+		}
+		km.bindings[key] = action
+	}
+	return km
+}
+
+// Warnings reports problems found while loading the config.
+func (km *KeyMap) Warnings() []string {
+	return km.warnings
+}
+
+// resolveAction maps a key press to its bound action, or ActionNone for
+// keys that have no binding.
+func (km *KeyMap) resolveAction(msg tea.KeyMsg) Action {
+	return km.bindings[msg.String()]
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func keyPress(s string) tea.KeyMsg {
+	switch s {
+	case "ctrl+c":
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+}
+
+func TestDefaultKeyMapResolvesActions(t *testing.T) {
+	km := DefaultKeyMap()
+	if got := km.resolveAction(keyPress("ctrl+c")); got != ActionQuit {
+		t.Errorf("ctrl+c = %q, want quit", got)
+	}
+	if got := km.resolveAction(keyPress("tab")); got != ActionSwitchPane {
+		t.Errorf("tab = %q, want switch-pane", got)
+	}
+	if got := km.resolveAction(keyPress("z")); got != ActionNone {
+		t.Errorf("unbound key resolved to %q", got)
+	}
+}
+
+func TestLoadKeyMapRemapsAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"quit": "q"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	km := LoadKeyMap(path)
+	if len(km.Warnings()) != 0 {
+		t.Fatalf("unexpected warnings: %v", km.Warnings())
+	}
+	if got := km.resolveAction(keyPress("q")); got != ActionQuit {
+		t.Errorf("remapped q = %q, want quit", got)
+	}
+	// The old binding moves rather than duplicating.
+	if got := km.resolveAction(keyPress("ctrl+c")); got != ActionNone {
+		t.Errorf("ctrl+c still resolves to %q after remap", got)
+	}
+	// Untouched actions keep their defaults.
+	if got := km.resolveAction(keyPress("r")); got != ActionRefresh {
+		t.Errorf("r = %q, want refresh", got)
+	}
+}
+
+func TestLoadKeyMapInvalidJSONFallsBackToDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{not json`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	km := LoadKeyMap(path)
+	if len(km.Warnings()) == 0 {
+		t.Error("expected a warning for invalid config")
+	}
+	if got := km.resolveAction(keyPress("ctrl+c")); got != ActionQuit {
+		t.Errorf("defaults not preserved: ctrl+c = %q", got)
+	}
+}
+
+func TestLoadKeyMapUnknownActionWarns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"fly": "f", "save": "w"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	km := LoadKeyMap(path)
+	if len(km.Warnings()) != 1 {
+		t.Errorf("warnings = %v, want one for the unknown action", km.Warnings())
+	}
+	if got := km.resolveAction(keyPress("w")); got != ActionSave {
+		t.Errorf("valid binding alongside an invalid one was dropped: w = %q", got)
+	}
+}
+
+func TestHandleKeyHonorsRemappedQuit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".ai-tui-keys.json")
+	if err := os.WriteFile(path, []byte(`{"quit": "q"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialAITUIModel()
+	_, cmd := m.Update(keyPress("q"))
+	if cmd == nil {
+		t.Fatal("remapped quit key produced no command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Errorf("remapped quit key produced %T, want tea.QuitMsg", cmd())
+	}
+
+	// The default binding moved, so ctrl+c no longer quits.
+	_, cmd = m.Update(keyPress("ctrl+c"))
+	if cmd != nil {
+		if _, ok := cmd().(tea.QuitMsg); ok {
+			t.Error("ctrl+c still quits after quit was remapped to q")
+		}
+	}
+}
+
+func TestLoadKeyMapMissingFileUsesDefaults(t *testing.T) {
+	km := LoadKeyMap(filepath.Join(t.TempDir(), "absent.json"))
+	if len(km.Warnings()) != 0 {
+		t.Errorf("missing file should not warn: %v", km.Warnings())
+	}
+	if got := km.resolveAction(keyPress("ctrl+c")); got != ActionQuit {
+		t.Errorf("ctrl+c = %q", got)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"testing"
+
+	bubbletea "github.com/charmbracelet/bubbletea"
+)
+
+// Test clamping of out-of-bounds pane geometry
+func TestClampPaneToScreen(t *testing.T) {
+	// Pane hanging off the right/bottom edge gets pulled back
+	pane := Pane{ID: "off", X: 95, Y: 38, Width: 30, Height: 10}
+	clampPaneToScreen(&pane, 100, 40)
+	if pane.X+pane.Width > 100 {
+		t.Errorf("Pane still exceeds width: X=%d W=%d", pane.X, pane.Width)
+	}
+	if pane.Y+pane.Height > 40 {
+		t.Errorf("Pane still exceeds height: Y=%d H=%d", pane.Y, pane.Height)
+	}
+
+	// Negative origin gets reset
+	pane = Pane{ID: "neg", X: -5, Y: -2, Width: 30, Height: 10}
+	clampPaneToScreen(&pane, 100, 40)
+	if pane.X < 0 || pane.Y < 0 {
+		t.Errorf("Pane origin still negative: X=%d Y=%d", pane.X, pane.Y)
+	}
+
+	// Minimum sizes are enforced
+	pane = Pane{ID: "tiny", X: 0, Y: 0, Width: 1, Height: 1}
+	clampPaneToScreen(&pane, 100, 40)
+	if pane.Width < 20 || pane.Height < 5 {
+		t.Errorf("Minimum size not enforced: W=%d H=%d", pane.Width, pane.Height)
+	}
+
+	// Oversized pane is capped to the screen
+	pane = Pane{ID: "huge", X: 0, Y: 0, Width: 500, Height: 200}
+	clampPaneToScreen(&pane, 100, 40)
+	if pane.Width > 100 || pane.Height > 40 {
+		t.Errorf("Oversized pane not capped: W=%d H=%d", pane.Width, pane.Height)
+	}
+}
+
+// Test layout save/restore matching by pane ID
+func TestPaneLayoutRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	defaults := []Pane{
+		{ID: "main", X: 2, Y: 2, Width: 60, Height: 20},
+		{ID: "logs", X: 65, Y: 2, Width: 40, Height: 15},
+	}
+
+	moved := []Pane{
+		{ID: "main", X: 10, Y: 5, Width: 50, Height: 22},
+		{ID: "gone", X: 0, Y: 0, Width: 30, Height: 10}, // no longer exists
+	}
+	if err := savePaneLayout(moved); err != nil {
+		t.Fatalf("Failed to save layout: %v", err)
+	}
+
+	got := loadPaneLayout(defaults, 100, 40)
+	if got[0].X != 10 || got[0].Y != 5 || got[0].Width != 50 || got[0].Height != 22 {
+		t.Errorf("Saved geometry not restored: %+v", got[0])
+	}
+	// Pane with no saved entry keeps its defaults
+	if got[1].X != 65 || got[1].Width != 40 {
+		t.Errorf("Default geometry was modified: %+v", got[1])
+	}
+}
+
+// Test keyboard resize mode geometry changes and clamping
+func TestKeyboardResizeMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	m.width, m.height = 100, 40
+	m.resizeMode = true
+	m.activePane = 0
+	startW := m.panes[0].Width
+	startH := m.panes[0].Height
+
+	// Grow with arrows
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyRight})
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyDown})
+	if m.panes[0].Width != startW+1 || m.panes[0].Height != startH+1 {
+		t.Errorf("Expected %dx%d, got %dx%d", startW+1, startH+1, m.panes[0].Width, m.panes[0].Height)
+	}
+
+	// Shrinking stops at the floors
+	m.panes[0].Width, m.panes[0].Height = 20, 5
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyLeft})
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyUp})
+	if m.panes[0].Width < 20 || m.panes[0].Height < 5 {
+		t.Errorf("Size floors violated: %dx%d", m.panes[0].Width, m.panes[0].Height)
+	}
+
+	// Shift+arrows move; movement clamps at the screen edge
+	m.panes[0].X, m.panes[0].Y = 0, 0
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyShiftLeft})
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyShiftUp})
+	if m.panes[0].X < 0 || m.panes[0].Y < 0 {
+		t.Errorf("Pane moved off-screen: X=%d Y=%d", m.panes[0].X, m.panes[0].Y)
+	}
+	m, _ = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyShiftRight})
+	if m.panes[0].X != 1 {
+		t.Errorf("Expected X=1 after move right, got %d", m.panes[0].X)
+	}
+
+	// Esc leaves resize mode without quitting
+	m, cmd := m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyEsc})
+	if m.resizeMode {
+		t.Error("Expected resize mode to end on Esc")
+	}
+	if cmd != nil {
+		t.Error("Esc in resize mode must not quit")
+	}
+}
+
+// Test pane add/close index bookkeeping
+func TestAddClosePanes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	base := len(m.panes)
+
+	m.addPane("Scratch", "scratch content")
+	if len(m.panes) != base+1 {
+		t.Fatalf("Expected %d panes after add, got %d", base+1, len(m.panes))
+	}
+	if m.activePane != len(m.panes)-1 {
+		t.Errorf("Expected new pane to be active, got %d", m.activePane)
+	}
+
+	// Closing a pane before the active index shifts the active index down
+	m.activePane = len(m.panes) - 1
+	m.closePane(0)
+	if m.activePane != len(m.panes)-1 {
+		t.Errorf("Active index not adjusted after closing earlier pane: %d", m.activePane)
+	}
+
+	// Closing the active (last) pane clamps the index
+	m.closePane(m.activePane)
+	if m.activePane >= len(m.panes) {
+		t.Errorf("Active index out of range: %d of %d", m.activePane, len(m.panes))
+	}
+
+	// The last pane can never be closed
+	for len(m.panes) > 1 {
+		m.closePane(0)
+	}
+	m.closePane(0)
+	if len(m.panes) != 1 {
+		t.Fatalf("Expected the last pane to survive, got %d", len(m.panes))
+	}
+}
+
+func panesOverlap(a, b Pane) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width &&
+		a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// Test that tiled panes never overlap for 1-5 panes in every mode
+func TestTilePanesNoOverlap(t *testing.T) {
+	for n := 1; n <= 5; n++ {
+		panes := make([]Pane, n)
+		for i := range panes {
+			panes[i] = Pane{ID: string(rune('a' + i)), X: 1, Y: 1, Width: 30, Height: 10}
+		}
+
+		for _, mode := range []TileMode{TileGrid, TileHorizontal, TileVertical} {
+			tiled := tilePanes(panes, 200, 60, mode)
+			for i := 0; i < len(tiled); i++ {
+				for j := i + 1; j < len(tiled); j++ {
+					if panesOverlap(tiled[i], tiled[j]) {
+						t.Errorf("mode=%v n=%d: panes %d and %d overlap", mode, n, i, j)
+					}
+				}
+			}
+			for _, pane := range tiled {
+				if pane.Width < 20 || pane.Height < 5 {
+					t.Errorf("mode=%v n=%d: minimum size violated %dx%d", mode, n, pane.Width, pane.Height)
+				}
+			}
+		}
+	}
+}
+
+// Test that floating mode restores manual geometry
+func TestTileLayoutRestoresFloating(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	m.width, m.height = 200, 60
+	originalX := m.panes[0].X
+	originalW := m.panes[0].Width
+
+	m.tileLayout(TileGrid)
+	if m.tileMode != TileGrid {
+		t.Fatal("Expected grid mode to be active")
+	}
+
+	m.tileLayout(TileFloating)
+	if m.panes[0].X != originalX || m.panes[0].Width != originalW {
+		t.Errorf("Floating geometry not restored: X=%d W=%d", m.panes[0].X, m.panes[0].Width)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -292,3 +293,389 @@ func BenchmarkLogConversation(b *testing.B) {
 		logger.LogConversation(session)
 	}
 }
+
+// Test size-based rotation of the events file
+func TestFileLoggerRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewFileLogger(tempDir)
+	logger.MaxBytes = 512
+	logger.MaxBackups = 2
+
+	event := SystemEvent{
+		ID:        "rotate-me",
+		Timestamp: time.Now(),
+		Type:      "info",
+		Source:    "test",
+		Message:   strings.Repeat("x", 100),
+	}
+
+	// Write enough to roll over at least twice
+	for i := 0; i < 30; i++ {
+		if err := logger.LogEvent(event); err != nil {
+			t.Fatalf("Failed to log event %d: %v", i, err)
+		}
+	}
+
+	eventFile := filepath.Join(tempDir, "events.jsonl")
+	info, err := os.Stat(eventFile)
+	if err != nil {
+		t.Fatalf("Active events file missing: %v", err)
+	}
+	// The active file restarts after each rotation, so it must stay small
+	if info.Size() > logger.MaxBytes+512 {
+		t.Errorf("Active file did not shrink after rotation: %d bytes", info.Size())
+	}
+
+	for i := 1; i <= logger.MaxBackups; i++ {
+		backup := fmt.Sprintf("%s.%d", eventFile, i)
+		if _, err := os.Stat(backup); err != nil {
+			t.Errorf("Expected backup %s to exist: %v", backup, err)
+		}
+	}
+	if _, err := os.Stat(fmt.Sprintf("%s.%d", eventFile, logger.MaxBackups+1)); err == nil {
+		t.Errorf("More than MaxBackups backup files were kept")
+	}
+}
+
+// Test that rotation is safe under concurrent writers
+func TestFileLoggerConcurrentRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewFileLogger(tempDir)
+	logger.MaxBytes = 1024
+	logger.MaxBackups = 3
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				event := SystemEvent{
+					ID:        fmt.Sprintf("w%d-%d", w, i),
+					Timestamp: time.Now(),
+					Type:      "info",
+					Source:    "concurrent",
+					Message:   strings.Repeat("y", 80),
+				}
+				if err := logger.LogEvent(event); err != nil {
+					t.Errorf("Concurrent log failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Every line still present must be valid JSON
+	data, err := os.ReadFile(filepath.Join(tempDir, "events.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var ev SystemEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Errorf("Corrupt line after concurrent rotation: %v", err)
+		}
+	}
+}
+
+// Test reading events back with type and time-range filters
+func TestReadEventsFiltering(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewFileLogger(tempDir)
+
+	base := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		eventType := "info"
+		if i%2 == 1 {
+			eventType = "error"
+		}
+		event := SystemEvent{
+			ID:        fmt.Sprintf("ev-%d", i),
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+			Type:      eventType,
+			Source:    "reader-test",
+			Message:   "hello",
+		}
+		if err := logger.LogEvent(event); err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+	}
+
+	events, skipped, err := logger.ReadEvents(EventFilter{Type: "error"})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if skipped != 0 {
+		t.Errorf("Unexpected skipped count: %d", skipped)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 error events, got %d", len(events))
+	}
+
+	// Time-range filter: hours 2 through 4 inclusive
+	events, _, err = logger.ReadEvents(EventFilter{
+		Since: base.Add(2 * time.Hour),
+		Until: base.Add(4 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("Expected 3 events in range, got %d", len(events))
+	}
+
+	// Limit caps the result count
+	events, _, err = logger.ReadEvents(EventFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected limit of 2 events, got %d", len(events))
+	}
+}
+
+// Test that a corrupt line is skipped and counted, not fatal
+func TestReadEventsSkipsCorruptLines(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewFileLogger(tempDir)
+
+	good := SystemEvent{ID: "good-1", Timestamp: time.Now(), Type: "info", Source: "test", Message: "ok"}
+	if err := logger.LogEvent(good); err != nil {
+		t.Fatalf("Failed to log event: %v", err)
+	}
+
+	// Inject garbage in the middle of the file
+	eventFile := filepath.Join(tempDir, "events.jsonl")
+	f, err := os.OpenFile(eventFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open events file: %v", err)
+	}
+	f.WriteString("{not valid json\n")
+	f.Close()
+
+	good.ID = "good-2"
+	if err := logger.LogEvent(good); err != nil {
+		t.Fatalf("Failed to log event: %v", err)
+	}
+
+	events, skipped, err := logger.ReadEvents(EventFilter{})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected 2 good events, got %d", len(events))
+	}
+	if skipped != 1 {
+		t.Errorf("Expected 1 skipped line, got %d", skipped)
+	}
+}
+
+// Test that every enqueued event is on disk after Close
+func TestAsyncFileLoggerDrainsOnClose(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewAsyncFileLogger(tempDir, 32)
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		event := SystemEvent{
+			ID:        fmt.Sprintf("async-%d", i),
+			Timestamp: time.Now(),
+			Type:      "info",
+			Source:    "async-test",
+			Message:   "queued",
+		}
+		if err := logger.LogEvent(event); err != nil {
+			t.Fatalf("Failed to enqueue event %d: %v", i, err)
+		}
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "events.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != n {
+		t.Errorf("Expected %d events after Close, got %d", n, len(lines))
+	}
+
+	// Logging after Close must fail rather than silently drop
+	if err := logger.LogEvent(SystemEvent{ID: "late"}); err == nil {
+		t.Error("Expected an error logging after Close")
+	}
+}
+
+// Benchmark the synchronous logger as a baseline
+func BenchmarkFileLoggerLogEvent(b *testing.B) {
+	logger := NewFileLogger(b.TempDir())
+	event := SystemEvent{ID: "bench", Timestamp: time.Now(), Type: "info", Source: "bench", Message: "payload"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := logger.LogEvent(event); err != nil {
+			b.Fatalf("LogEvent failed: %v", err)
+		}
+	}
+}
+
+// Benchmark the buffered async logger for comparison
+func BenchmarkAsyncFileLoggerLogEvent(b *testing.B) {
+	logger := NewAsyncFileLogger(b.TempDir(), 1024)
+	event := SystemEvent{ID: "bench", Timestamp: time.Now(), Type: "info", Source: "bench", Message: "payload"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := logger.LogEvent(event); err != nil {
+			b.Fatalf("LogEvent failed: %v", err)
+		}
+	}
+	b.StopTimer()
+	logger.Close()
+}
+
+// Test that MinLevel filters low-severity events before writing
+func TestFileLoggerMinLevel(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := NewFileLogger(tempDir)
+	logger.MinLevel = LevelWarn
+
+	logger.Debug("level-test", "debug msg", nil)
+	logger.Info("level-test", "info msg", nil)
+	logger.Warn("level-test", "warn msg", nil)
+	logger.Error("level-test", "error msg", nil)
+	logger.LogEvent(SystemEvent{
+		ID: "sec", Timestamp: time.Now(), Type: "security",
+		Source: "level-test", Message: "security msg",
+	})
+
+	events, _, err := logger.ReadEvents(EventFilter{})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events at or above warn, got %d", len(events))
+	}
+	for _, ev := range events {
+		if levelFromType(ev.Type) < LevelWarn {
+			t.Errorf("Event below MinLevel was written: %s", ev.Type)
+		}
+	}
+}
+
+// Test logging to SQLite and querying back by type
+func TestSQLiteLoggerRoundTrip(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "events.db")
+	logger, err := NewSQLiteLogger(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite logger: %v", err)
+	}
+	defer logger.Close()
+
+	base := time.Date(2026, 8, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		eventType := "info"
+		if i >= 3 {
+			eventType = "error"
+		}
+		event := SystemEvent{
+			ID:        fmt.Sprintf("sql-%d", i),
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Type:      eventType,
+			Source:    "sqlite-test",
+			Message:   "stored",
+			Data:      map[string]interface{}{"n": float64(i)},
+		}
+		if err := logger.LogEvent(event); err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+	}
+
+	events, skipped, err := logger.ReadEvents(EventFilter{Type: "error"})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if skipped != 0 {
+		t.Errorf("Unexpected skipped rows: %d", skipped)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 error events, got %d", len(events))
+	}
+	if events[0].Data["n"] != float64(3) {
+		t.Errorf("Event data did not round-trip: %v", events[0].Data)
+	}
+
+	counts, err := logger.CountEventsByType()
+	if err != nil {
+		t.Fatalf("CountEventsByType failed: %v", err)
+	}
+	if counts["info"] != 3 || counts["error"] != 2 {
+		t.Errorf("Unexpected aggregate counts: %v", counts)
+	}
+
+	// Conversations round-trip through the conversations table
+	session := ConversationSession{
+		ID:        "conv-1",
+		StartTime: base,
+		Messages: []ConversationMessage{
+			{ID: "m1", Timestamp: base, Role: "user", Content: "hi"},
+		},
+	}
+	if err := logger.LogConversation(session); err != nil {
+		t.Fatalf("LogConversation failed: %v", err)
+	}
+}
+
+// Test Markdown transcript export against the golden file
+func TestExportTranscriptGolden(t *testing.T) {
+	start := time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5*time.Minute + 30*time.Second)
+	session := ConversationSession{
+		ID:        "golden-1",
+		StartTime: start,
+		EndTime:   &end,
+		Messages: []ConversationMessage{
+			{ID: "m1", Timestamp: start.Add(5 * time.Second), Role: "user", Content: "What does the animator do?"},
+			{ID: "m2", Timestamp: start.Add(10 * time.Second), Role: "assistant", Content: "It draws an underwater scene.\nWith fish and bubbles."},
+			{ID: "m3", Timestamp: start.Add(time.Minute), Role: "system", Content: "Session resumed.\nAll layers active."},
+		},
+	}
+
+	var buf strings.Builder
+	if err := ExportTranscript(session, &buf); err != nil {
+		t.Fatalf("ExportTranscript failed: %v", err)
+	}
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "transcript.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if buf.String() != string(golden) {
+		t.Errorf("Transcript does not match golden file.\nGot:\n%s\nWant:\n%s", buf.String(), golden)
+	}
+}
+
+// Test transcript export of an empty, still-open session
+func TestExportTranscriptEmptySession(t *testing.T) {
+	session := ConversationSession{
+		ID:        "empty-1",
+		StartTime: time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	var buf strings.Builder
+	if err := ExportTranscript(session, &buf); err != nil {
+		t.Fatalf("ExportTranscript failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "- Ended: ongoing") {
+		t.Errorf("Open session should report an ongoing end time:\n%s", out)
+	}
+	if !strings.Contains(out, "_No messages._") {
+		t.Errorf("Empty session should note it has no messages:\n%s", out)
+	}
+}
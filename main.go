@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbletea"
+	bubbletea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"golang.org/x/crypto/scrypt"
 	"golang.org/x/term"
 )
 
@@ -18,56 +27,172 @@ import (
 
 // Secret represents a stored secret
 type Secret struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Value     string    `json:"value"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Tags      []string  `json:"tags"`
-}
-
-// Pane represents a resizable pane
-type Pane struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Content     string  `json:"content"`
-	Width       int     `json:"width"`
-	Height      int     `json:"height"`
-	X           int     `json:"x"`
-	Y           int     `json:"y"`
-	IsActive    bool    `json:"is_active"`
-	IsResizable bool    `json:"is_resizable"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Value     string     `json:"value"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Tags      []string   `json:"tags"`
+}
+
+// Secret status values derived from ExpiresAt on display
+const (
+	secretStatusValid    = "valid"
+	secretStatusExpiring = "expiring"
+	secretStatusExpired  = "expired"
+)
+
+// expiringSoonWindow is how far ahead of expiry a secret is flagged as
+// "expiring" in the list.
+const expiringSoonWindow = 7 * 24 * time.Hour
+
+// secretStatus derives the display status for a secret from its expiry.
+func secretStatus(secret Secret, now time.Time) string {
+	if secret.ExpiresAt == nil {
+		return secretStatusValid
+	}
+	if now.After(*secret.ExpiresAt) {
+		return secretStatusExpired
+	}
+	if secret.ExpiresAt.Sub(now) <= expiringSoonWindow {
+		return secretStatusExpiring
+	}
+	return secretStatusValid
+}
+
+// parseRelativeExpiry parses a relative expiry like "+30d", "+2w" or "+12h"
+// into an absolute time from now. Supported units are h (hours), d (days)
+// and w (weeks).
+func parseRelativeExpiry(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 3 || s[0] != '+' {
+		return time.Time{}, fmt.Errorf("invalid expiry %q: expected form like +30d", s)
+	}
+
+	unit := s[len(s)-1]
+	var n int
+	if _, err := fmt.Sscanf(s[1:len(s)-1], "%d", &n); err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid expiry amount in %q", s)
+	}
+
+	var d time.Duration
+	switch unit {
+	case 'h':
+		d = time.Duration(n) * time.Hour
+	case 'd':
+		d = time.Duration(n) * 24 * time.Hour
+	case 'w':
+		d = time.Duration(n) * 7 * 24 * time.Hour
+	default:
+		return time.Time{}, fmt.Errorf("invalid expiry unit %q: use h, d or w", string(unit))
+	}
+
+	return time.Now().Add(d), nil
 }
 
 // Progress represents a progress bar
 type Progress struct {
-	ID          string  `json:"id"`
-	Label       string  `json:"label"`
-	Percent     float64 `json:"percent"`
-	IsActive    bool    `json:"is_active"`
-	ShowPercent bool    `json:"show_percent"`
-	Color       string  `json:"color"`
+	ID            string  `json:"id"`
+	Label         string  `json:"label"`
+	Percent       float64 `json:"percent"`
+	IsActive      bool    `json:"is_active"`
+	ShowPercent   bool    `json:"show_percent"`
+	Color         string  `json:"color"`
+	Error         string  `json:"error,omitempty"`
+	Indeterminate bool    `json:"indeterminate,omitempty"`
+	Cancelled     bool    `json:"cancelled,omitempty"`
+
+	// ETA tracking: an exponential moving average of the completion rate
+	// (fraction per second) smooths out bursty reports.
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	LastUpdate time.Time `json:"last_update,omitempty"`
+	Rate       float64   `json:"rate,omitempty"`
+}
+
+// spinnerFrames holds the animation cycle used for indeterminate progress
+// rows. Overridable for custom spinners; defaults to a braille cycle.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// etaSmoothing is the EMA weight given to the newest rate sample.
+const etaSmoothing = 0.3
+
+// applyProgressSample records a progress report at the given time, updating
+// the smoothed completion rate used for ETA estimates.
+func applyProgressSample(p *Progress, percent float64, now time.Time) {
+	if p.StartedAt.IsZero() {
+		p.StartedAt = now
+	}
+	if !p.LastUpdate.IsZero() {
+		dt := now.Sub(p.LastUpdate).Seconds()
+		if dt > 0 && percent > p.Percent {
+			sample := (percent - p.Percent) / dt
+			if p.Rate == 0 {
+				p.Rate = sample
+			} else {
+				p.Rate = etaSmoothing*sample + (1-etaSmoothing)*p.Rate
+			}
+		}
+	}
+	p.Percent = percent
+	p.LastUpdate = now
+}
+
+// estimateETA returns the estimated time remaining for a progress entry.
+// ok is false when there isn't enough data yet (no progress, or stalled).
+func estimateETA(p Progress, now time.Time) (time.Duration, bool) {
+	if p.Percent >= 1.0 || p.Rate <= 0 || p.StartedAt.IsZero() {
+		return 0, false
+	}
+	remaining := (1.0 - p.Percent) / p.Rate
+	return time.Duration(remaining * float64(time.Second)), true
 }
 
 // Model holds the application state
 type Model struct {
-	panes       []Pane
-	secrets     []Secret
-	progress    []Progress
-	activePane  int
-	focusedPane int
-	width       int
-	height      int
-	loading     bool
-	loadingText string
-	editingSecret *Secret
-	newSecretName string
-	newSecretValue string
-	showSecrets bool
-	showProgress bool
-	draggingPane *Pane
-	dragStartX   int
-	dragStartY   int
+	panes             []Pane
+	secrets           []Secret
+	progress          []Progress
+	activePane        int
+	focusedPane       int
+	width             int
+	height            int
+	loading           bool
+	loadingText       string
+	editingSecret     *Secret
+	newSecretName     string
+	newSecretValue    string
+	newSecretExpiry   string
+	editingExpiry     bool
+	genCharset        SecretCharset
+	genLength         int
+	generatedValue    string
+	selectedSecret    int
+	confirmingDelete  bool
+	confirmingQuit    bool
+	revealedSecretID  string
+	revealExpiry      time.Time
+	revealTimeout     time.Duration
+	maskEditInput     bool
+	statusMessage     string
+	statusIsError     bool
+	statusExpiry      time.Time
+	searchMode        bool
+	searchQuery       string
+	tagFilter         string
+	spinnerFrame      int
+	selectedProgress  int
+	resizeMode        bool
+	tileMode          TileMode
+	floatingPanes     []Pane
+	jobCancels        map[string]context.CancelFunc
+	showSecrets       bool
+	showProgress      bool
+	showAuditLog      bool
+	secretAuditLogger Logger
+	draggingPane      *Pane
+	dragStartX        int
+	dragStartY        int
 }
 
 // ==================== INITIALIZE ====================
@@ -117,17 +242,112 @@ func initialModel() Model {
 		{ID: "task3", Label: "API Sync", Percent: 0.90, IsActive: true, ShowPercent: true, Color: "yellow"},
 	}
 
+	panes = loadPaneLayout(panes, 100, 40)
+
+	secrets, err := loadSecrets()
+	if err != nil {
+		secrets = []Secret{}
+	}
+
+	home, _ := os.UserHomeDir()
+	auditLogger := &FileLogger{
+		eventsFile:        filepath.Join(home, ".go-tui-secrets-audit.jsonl"),
+		conversationsFile: filepath.Join(home, ".go-tui-secrets-audit-conversations.jsonl"),
+	}
+
 	return Model{
-		panes:       panes,
-		secrets:     loadSecrets(),
-		progress:    progress,
-		activePane:  0,
-		focusedPane: 0,
-		width:       100,
-		height:      40,
-		loading:     false,
-		showSecrets: false,
-		showProgress: true,
+		panes:             panes,
+		secrets:           secrets,
+		progress:          progress,
+		activePane:        0,
+		focusedPane:       0,
+		width:             100,
+		height:            40,
+		loading:           false,
+		revealTimeout:     10 * time.Second,
+		maskEditInput:     true,
+		genLength:         32,
+		secretAuditLogger: auditLogger,
+		showSecrets:       false,
+		showProgress:      true,
+	}
+}
+
+// ==================== PANE LAYOUT PERSISTENCE ====================
+
+func layoutFilePath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".go-tui-layout.json")
+}
+
+// savePaneLayout persists the current pane geometry so it survives restarts.
+func savePaneLayout(panes []Pane) error {
+	data, err := json.MarshalIndent(panes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(layoutFilePath(), data, 0644)
+}
+
+// loadPaneLayout applies saved geometry onto the default panes, matching by
+// pane ID. Saved panes that no longer exist are ignored and geometry that
+// falls outside the screen is clamped back into view.
+func loadPaneLayout(defaults []Pane, width, height int) []Pane {
+	data, err := os.ReadFile(layoutFilePath())
+	if err != nil {
+		return defaults
+	}
+
+	var saved []Pane
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return defaults
+	}
+
+	byID := make(map[string]Pane, len(saved))
+	for _, pane := range saved {
+		byID[pane.ID] = pane
+	}
+
+	panes := make([]Pane, len(defaults))
+	copy(panes, defaults)
+	for i := range panes {
+		if s, ok := byID[panes[i].ID]; ok {
+			panes[i].X = s.X
+			panes[i].Y = s.Y
+			panes[i].Width = s.Width
+			panes[i].Height = s.Height
+			clampPaneToScreen(&panes[i], width, height)
+		}
+	}
+	return panes
+}
+
+// clampPaneToScreen forces a pane's geometry back within the given screen
+// bounds, preserving the minimum sizes used elsewhere.
+func clampPaneToScreen(pane *Pane, width, height int) {
+	if pane.Width < 20 {
+		pane.Width = 20
+	}
+	if pane.Height < 5 {
+		pane.Height = 5
+	}
+	if pane.Width > width {
+		pane.Width = width
+	}
+	if pane.Height > height {
+		pane.Height = height
+	}
+	if pane.X < 0 {
+		pane.X = 0
+	}
+	if pane.Y < 0 {
+		pane.Y = 0
+	}
+	if pane.X+pane.Width > width {
+		pane.X = width - pane.Width
+	}
+	if pane.Y+pane.Height > height {
+		pane.Y = height - pane.Height
 	}
 }
 
@@ -151,8 +371,8 @@ var (
 
 func (m Model) Init() bubbletea.Cmd {
 	return bubbletea.Batch(
-		bubbletea.WindowSize(),
 		m.startProgressUpdates(),
+		listenForProgress(),
 	)
 }
 
@@ -169,10 +389,20 @@ func (m Model) Update(msg bubbletea.Msg) (bubbletea.Model, bubbletea.Cmd) {
 		return m.handleMouse(msg)
 
 	case progressUpdateMsg:
-		return m.updateProgress(msg)
+		newModel, cmd := m.updateProgress(msg)
+		return newModel, bubbletea.Batch(cmd, listenForProgress())
 
-	case bubbletea.TickMsg:
-		return m, m.startProgressUpdates
+	case clipboardClearMsg:
+		clipboard{}.Copy("")
+		return m, nil
+
+	case spinnerTickMsg:
+		m.spinnerFrame++
+		// Auto-hide a revealed secret once its timeout elapses
+		if m.revealedSecretID != "" && time.Now().After(m.revealExpiry) {
+			m.revealedSecretID = ""
+		}
+		return m, m.startProgressUpdates()
 
 	default:
 		return m, nil
@@ -184,6 +414,16 @@ func (m Model) Update(msg bubbletea.Msg) (bubbletea.Model, bubbletea.Cmd) {
 func (m Model) handleKey(msg bubbletea.KeyMsg) (Model, bubbletea.Cmd) {
 	switch msg.Type {
 	case bubbletea.KeyCtrlC, bubbletea.KeyEsc:
+		if m.resizeMode {
+			m.resizeMode = false
+			return m, nil
+		}
+		// Don't silently drop an in-progress secret edit
+		if m.secretDirty() {
+			m.confirmingQuit = true
+			return m, nil
+		}
+		savePaneLayout(m.panes)
 		return m, bubbletea.Quit
 
 	case bubbletea.KeyTab:
@@ -197,208 +437,1197 @@ func (m Model) handleKey(msg bubbletea.KeyMsg) (Model, bubbletea.Cmd) {
 		return m, nil
 
 	case bubbletea.KeyEnter:
+		if m.showSecrets && m.searchMode {
+			m.searchMode = false
+			return m, nil
+		}
 		if m.showSecrets && m.editingSecret != nil {
 			m.saveSecret()
 		}
 		return m, nil
 
 	case bubbletea.KeyBackspace:
+		if m.showSecrets && m.searchMode && len(m.searchQuery) > 0 {
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+			return m, nil
+		}
+		if m.showSecrets && m.editingExpiry && len(m.newSecretExpiry) > 0 {
+			m.newSecretExpiry = m.newSecretExpiry[:len(m.newSecretExpiry)-1]
+			return m, nil
+		}
 		if m.showSecrets && len(m.newSecretValue) > 0 {
 			m.newSecretValue = m.newSecretValue[:len(m.newSecretValue)-1]
 		}
 		return m, nil
 
 	case bubbletea.KeyRunes:
+		if m.showSecrets && m.searchMode {
+			m.searchQuery += string(msg.Runes)
+			m.selectedSecret = 0
+			return m, nil
+		}
+		if m.showSecrets && m.editingExpiry {
+			m.newSecretExpiry += string(msg.Runes)
+			return m, nil
+		}
 		if m.showSecrets {
 			m.newSecretValue += string(msg.Runes)
 		}
 		return m, nil
 
+	case bubbletea.KeyUp:
+		if m.resizeMode {
+			return m.resizeActivePane(0, -1, false)
+		}
+		if m.showSecrets && m.editingSecret == nil && m.selectedSecret > 0 {
+			m.selectedSecret--
+		} else if m.showProgress && m.selectedProgress > 0 {
+			m.selectedProgress--
+		}
+		return m, nil
+
+	case bubbletea.KeyDown:
+		if m.resizeMode {
+			return m.resizeActivePane(0, 1, false)
+		}
+		if m.showSecrets && m.editingSecret == nil && m.selectedSecret < len(m.visibleSecrets())-1 {
+			m.selectedSecret++
+		} else if m.showProgress && m.selectedProgress < len(m.progress)-1 {
+			m.selectedProgress++
+		}
+		return m, nil
+
 	case 's':
 		m.showSecrets = !m.showSecrets
 		m.showProgress = !m.showProgress
+		m.revealedSecretID = "" // never carry a revealed value across panes
+		return m, nil
+
+	case 'p':
+		m.showProgress = !m.showProgress
+		m.showSecrets = !m.showSecrets
+		m.revealedSecretID = ""
+		return m, nil
+
+	case 'v':
+		if m.showSecrets && m.editingSecret == nil {
+			if vis := m.visibleSecrets(); m.selectedSecret < len(vis) {
+				m.revealedSecretID = vis[m.selectedSecret].ID
+				m.revealExpiry = time.Now().Add(m.revealTimeout)
+				m.auditSecret("revealed", vis[m.selectedSecret])
+			}
+		}
+		return m, nil
+
+	case bubbletea.KeyCtrlV:
+		if m.showSecrets && m.editingSecret != nil {
+			m.maskEditInput = !m.maskEditInput
+		}
+		return m, nil
+
+	case bubbletea.KeyCtrlE:
+		if m.showSecrets && m.editingSecret != nil {
+			m.editingExpiry = !m.editingExpiry
+		}
+		return m, nil
+
+	case 'c':
+		if m.showSecrets && m.editingSecret == nil && m.selectedSecret < len(m.visibleSecrets()) {
+			secret := m.visibleSecrets()[m.selectedSecret]
+			if err := (clipboard{}).Copy(secret.Value); err != nil {
+				m.setStatus(err.Error(), true)
+				return m, nil
+			}
+			m.setStatus(fmt.Sprintf("Copied '%s' to clipboard (clears in 30s)", secret.Name), false)
+			m.auditSecret("copied", secret)
+			return m, scheduleClipboardClear()
+		}
+		return m, nil
+
+	case 'n':
+		if m.confirmingQuit {
+			m.confirmingQuit = false
+			return m, nil
+		}
+		if m.showSecrets && m.confirmingDelete {
+			m.confirmingDelete = false
+			return m, nil
+		}
+		if m.showSecrets {
+			m.editingSecret = &Secret{ID: generateID(), CreatedAt: time.Now()}
+			m.newSecretName = ""
+			m.newSecretValue = ""
+		}
+		return m, nil
+
+	case 'e':
+		if m.showSecrets && m.editingSecret == nil {
+			if vis := m.visibleSecrets(); m.selectedSecret < len(vis) {
+				m.updateSecret(vis[m.selectedSecret].ID)
+			}
+		}
+		return m, nil
+
+	case 'd':
+		if m.showSecrets && m.editingSecret == nil && len(m.visibleSecrets()) > 0 {
+			m.confirmingDelete = true
+		}
+		return m, nil
+
+	case 'y':
+		if m.confirmingQuit {
+			return m, bubbletea.Quit
+		}
+		if m.showSecrets && m.confirmingDelete {
+			if vis := m.visibleSecrets(); m.selectedSecret < len(vis) {
+				m.deleteSecret(vis[m.selectedSecret].ID)
+			}
+			m.confirmingDelete = false
+		}
+		return m, nil
+
+	case 'q':
+		if m.showSecrets && m.editingSecret != nil {
+			m.editingSecret = nil
+			m.newSecretName = ""
+			m.newSecretValue = ""
+		}
+		return m, nil
+
+	case 'r':
+		if m.showSecrets {
+			m.refreshSecrets()
+		}
+		return m, nil
+
+	case 'g':
+		if m.showSecrets && m.editingSecret != nil {
+			if m.newSecretValue != "" && m.generatedValue == m.newSecretValue {
+				// Repeated press: cycle to the next charset before regenerating
+				m.genCharset = (m.genCharset + 1) % 3
+			}
+			m.newSecretValue = generateSecret(m.genLength, m.genCharset)
+			m.generatedValue = m.newSecretValue
+		}
+		return m, nil
+
+	case bubbletea.KeyLeft:
+		if m.resizeMode {
+			return m.resizeActivePane(-1, 0, false)
+		}
+		return m, nil
+
+	case bubbletea.KeyRight:
+		if m.resizeMode {
+			return m.resizeActivePane(1, 0, false)
+		}
+		return m, nil
+
+	case bubbletea.KeyShiftUp:
+		if m.resizeMode {
+			return m.resizeActivePane(0, -1, true)
+		}
+		return m, nil
+
+	case bubbletea.KeyShiftDown:
+		if m.resizeMode {
+			return m.resizeActivePane(0, 1, true)
+		}
+		return m, nil
+
+	case bubbletea.KeyShiftLeft:
+		if m.resizeMode {
+			return m.resizeActivePane(-1, 0, true)
+		}
+		return m, nil
+
+	case bubbletea.KeyShiftRight:
+		if m.resizeMode {
+			return m.resizeActivePane(1, 0, true)
+		}
+		return m, nil
+
+	case 'R':
+		m.resizeMode = !m.resizeMode
+		return m, nil
+
+	case bubbletea.KeyCtrlN:
+		m.addPane("New Pane", "Empty pane — press R to resize or move it")
+		return m, nil
+
+	case 'L':
+		m.tileLayout((m.tileMode + 1) % 4)
+		m.setStatus(fmt.Sprintf("Layout: %s", m.tileMode), false)
+		return m, nil
+
+	case bubbletea.KeyCtrlW:
+		m.closePane(m.activePane)
+		return m, nil
+
+	case 'x':
+		if m.showProgress && !m.showSecrets && m.selectedProgress < len(m.progress) {
+			m.cancelJob(m.progress[m.selectedProgress].ID)
+		}
+		return m, nil
+
+	case 'a':
+		if m.showSecrets && m.editingSecret == nil {
+			m.showAuditLog = !m.showAuditLog
+		}
+		return m, nil
+
+	case 'E':
+		if m.showSecrets && m.editingSecret == nil {
+			home, _ := os.UserHomeDir()
+			path := filepath.Join(home, ".go-tui-secrets-export.json")
+			if err := exportSecrets(m.secrets, path, ExportJSON); err != nil {
+				m.setStatus(err.Error(), true)
+			} else {
+				m.setStatus(fmt.Sprintf("Exported %d secrets to %s", len(m.secrets), path), false)
+			}
+		}
+		return m, nil
+
+	case 'I':
+		if m.showSecrets && m.editingSecret == nil {
+			home, _ := os.UserHomeDir()
+			path := filepath.Join(home, ".go-tui-secrets-export.json")
+			imported, err := importSecrets(path)
+			if err != nil {
+				m.setStatus(err.Error(), true)
+				return m, nil
+			}
+			merged, added, updated := mergeSecrets(m.secrets, imported)
+			m.secrets = merged
+			saveSecrets(m.secrets)
+			m.setStatus(fmt.Sprintf("Imported: %d added, %d updated", added, updated), false)
+		}
+		return m, nil
+
+	case '/':
+		if m.showSecrets && m.editingSecret == nil {
+			m.searchMode = !m.searchMode
+			if m.searchMode {
+				m.searchQuery = ""
+			}
+			m.selectedSecret = 0
+		}
+		return m, nil
+
+	case 't':
+		if m.showSecrets && m.editingSecret == nil {
+			m.tagFilter = nextTagFilter(m.secrets, m.tagFilter)
+			m.selectedSecret = 0
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// TileMode selects how tileLayout arranges the panes.
+type TileMode int
+
+const (
+	TileFloating TileMode = iota
+	TileGrid
+	TileHorizontal
+	TileVertical
+)
+
+func (t TileMode) String() string {
+	switch t {
+	case TileGrid:
+		return "grid"
+	case TileHorizontal:
+		return "hsplit"
+	case TileVertical:
+		return "vsplit"
+	default:
+		return "floating"
+	}
+}
+
+// tilePanes recomputes pane geometry for the given mode, filling width x
+// height with a one-cell gutter between panes. Floating mode returns the
+// panes unchanged.
+func tilePanes(panes []Pane, width, height int, mode TileMode) []Pane {
+	tiled := make([]Pane, len(panes))
+	copy(tiled, panes)
+	n := len(tiled)
+	if n == 0 || mode == TileFloating {
+		return tiled
+	}
+
+	const gutter = 1
+	var rows, cols int
+	switch mode {
+	case TileHorizontal:
+		rows, cols = 1, n
+	case TileVertical:
+		rows, cols = n, 1
+	default: // TileGrid
+		cols = 1
+		for cols*cols < n {
+			cols++
+		}
+		rows = (n + cols - 1) / cols
+	}
+
+	cellW := (width - gutter*(cols+1)) / cols
+	cellH := (height - gutter*(rows+1)) / rows
+	cellW = max(20, cellW)
+	cellH = max(5, cellH)
+
+	for i := range tiled {
+		row := i / cols
+		col := i % cols
+		tiled[i].X = gutter + col*(cellW+gutter)
+		tiled[i].Y = gutter + row*(cellH+gutter)
+		tiled[i].Width = cellW
+		tiled[i].Height = cellH
+	}
+	return tiled
+}
+
+// tileLayout applies a tiling mode to the model's panes. The manual
+// (floating) geometry is remembered the first time a tiled mode is applied
+// and restored when cycling back to floating.
+func (m *Model) tileLayout(mode TileMode) {
+	if mode == TileFloating {
+		if m.floatingPanes != nil {
+			for i := range m.panes {
+				for _, saved := range m.floatingPanes {
+					if saved.ID == m.panes[i].ID {
+						m.panes[i].X = saved.X
+						m.panes[i].Y = saved.Y
+						m.panes[i].Width = saved.Width
+						m.panes[i].Height = saved.Height
+					}
+				}
+			}
+		}
+		m.tileMode = mode
+		return
+	}
+
+	if m.tileMode == TileFloating {
+		m.floatingPanes = make([]Pane, len(m.panes))
+		copy(m.floatingPanes, m.panes)
+	}
+	m.panes = tilePanes(m.panes, m.width, m.height, mode)
+	m.tileMode = mode
+}
+
+// nextFreePosition finds a default origin for a new pane that doesn't sit
+// directly on top of an existing pane, cascading down-right until a free
+// spot (or the screen edge) is found.
+func (m Model) nextFreePosition() (int, int) {
+	x, y := 2, 2
+	for _, pane := range m.panes {
+		if pane.X == x && pane.Y == y {
+			x += 3
+			y += 2
+		}
+	}
+	if x > m.width-20 {
+		x = m.width - 20
+	}
+	if y > m.height-5 {
+		y = m.height - 5
+	}
+	return x, y
+}
+
+// addPane appends a new resizable pane at a free position and focuses it.
+func (m *Model) addPane(title, content string) {
+	x, y := m.nextFreePosition()
+	m.panes = append(m.panes, Pane{
+		ID:          generateID(),
+		Title:       title,
+		Content:     content,
+		Width:       40,
+		Height:      12,
+		X:           x,
+		Y:           y,
+		IsResizable: true,
+	})
+	m.activePane = len(m.panes) - 1
+	m.focusedPane = m.activePane
+}
+
+// closePane removes the pane at index, keeping activePane/focusedPane valid.
+// The last remaining pane cannot be closed.
+func (m *Model) closePane(index int) {
+	if len(m.panes) <= 1 || index < 0 || index >= len(m.panes) {
+		return
+	}
+	m.panes = append(m.panes[:index], m.panes[index+1:]...)
+	if m.activePane >= len(m.panes) {
+		m.activePane = len(m.panes) - 1
+	} else if m.activePane > index {
+		m.activePane--
+	}
+	m.focusedPane = m.activePane
+}
+
+// resizeActivePane grows/shrinks (or, when move is true, repositions) the
+// active pane by the given deltas, enforcing the same size floors as mouse
+// resizing and clamping the pane within the terminal.
+func (m Model) resizeActivePane(dx, dy int, move bool) (Model, bubbletea.Cmd) {
+	if m.activePane >= len(m.panes) {
+		return m, nil
+	}
+	pane := &m.panes[m.activePane]
+
+	if move {
+		pane.X += dx
+		pane.Y += dy
+	} else {
+		pane.Width = max(20, pane.Width+dx)
+		pane.Height = max(5, pane.Height+dy)
+	}
+	clampPaneToScreen(pane, m.width, m.height)
+	return m, nil
+}
+
+// ==================== MOUSE HANDLING ====================
+
+func (m Model) handleMouse(msg bubbletea.MouseMsg) (Model, bubbletea.Cmd) {
+	if msg.Type == bubbletea.MouseLeft {
+		x, y := msg.X, msg.Y
+
+		// Check if clicking on pane border for resizing
+		for i := range m.panes {
+			pane := &m.panes[i]
+			if m.isOnPaneBorder(x, y, pane) {
+				m.draggingPane = pane
+				m.dragStartX = x
+				m.dragStartY = y
+				return m, nil
+			}
+
+			// Check if clicking inside pane
+			if m.isInsidePane(x, y, pane) {
+				m.focusedPane = i
+				m.activePane = i
+				return m, nil
+			}
+		}
+	} else if msg.Type == bubbletea.MouseMotion && m.draggingPane != nil {
+		// Handle pane resizing
+		dx := msg.X - m.dragStartX
+		dy := msg.Y - m.dragStartY
+
+		m.draggingPane.Width = max(20, m.draggingPane.Width+dx)
+		m.draggingPane.Height = max(5, m.draggingPane.Height+dy)
+
+		m.dragStartX = msg.X
+		m.dragStartY = msg.Y
+		return m, nil
+	} else if msg.Type == bubbletea.MouseRelease {
+		if m.draggingPane != nil {
+			savePaneLayout(m.panes)
+		}
+		m.draggingPane = nil
 		return m, nil
+	}
+
+	return m, nil
+}
+
+// ==================== PROGRESS SYSTEM ====================
+
+type progressUpdateMsg struct {
+	ID        string
+	Percent   float64
+	Done      bool
+	Cancelled bool
+	Err       error
+}
+
+// progressReports carries updates from background job goroutines into the
+// bubbletea update loop via listenForProgress.
+var progressReports = make(chan progressUpdateMsg, 64)
+
+// RegisterJob adds a progress row for a real background job and starts it.
+// The work func reports completion fractions through report and must honor
+// ctx so cancellation via cancelJob aborts it cleanly; when it returns, the
+// row is marked done (or failed if an error was returned).
+func (m *Model) RegisterJob(id, label string, work func(ctx context.Context, report func(float64)) error) {
+	m.progress = append(m.progress, Progress{
+		ID:          id,
+		Label:       label,
+		Percent:     0,
+		IsActive:    true,
+		ShowPercent: true,
+		Color:       "blue",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if m.jobCancels == nil {
+		m.jobCancels = make(map[string]context.CancelFunc)
+	}
+	m.jobCancels[id] = cancel
+
+	go func() {
+		defer cancel()
+		report := func(percent float64) {
+			progressReports <- progressUpdateMsg{ID: id, Percent: percent}
+		}
+		err := work(ctx, report)
+		cancelled := ctx.Err() != nil || errors.Is(err, context.Canceled)
+		if cancelled {
+			progressReports <- progressUpdateMsg{ID: id, Done: true, Cancelled: true}
+			return
+		}
+		progressReports <- progressUpdateMsg{ID: id, Percent: 1.0, Done: true, Err: err}
+	}()
+}
+
+// cancelJob aborts a running job's context and marks its row cancelled.
+func (m *Model) cancelJob(id string) {
+	if cancel, ok := m.jobCancels[id]; ok {
+		cancel()
+		delete(m.jobCancels, id)
+	}
+	for i := range m.progress {
+		if m.progress[i].ID == id {
+			m.progress[i].Cancelled = true
+			m.progress[i].IsActive = false
+			return
+		}
+	}
+}
+
+// SetIndeterminate marks a progress row as having no measurable percent;
+// it renders as an animated marquee instead of a fill bar.
+func (m *Model) SetIndeterminate(id string, on bool) {
+	for i := range m.progress {
+		if m.progress[i].ID == id {
+			m.progress[i].Indeterminate = on
+			return
+		}
+	}
+}
+
+// listenForProgress waits for the next job report. Update re-issues it after
+// each progressUpdateMsg so the channel keeps draining.
+func listenForProgress() bubbletea.Cmd {
+	return func() bubbletea.Msg {
+		return <-progressReports
+	}
+}
+
+// spinnerTickMsg drives the once-a-second spinner and reveal-timeout
+// refresh; startProgressUpdates re-arms it from Update.
+type spinnerTickMsg struct{}
+
+func (m Model) startProgressUpdates() bubbletea.Cmd {
+	return bubbletea.Tick(time.Second, func(t time.Time) bubbletea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+func (m Model) updateProgress(msg progressUpdateMsg) (Model, bubbletea.Cmd) {
+	for i := range m.progress {
+		if m.progress[i].ID == msg.ID {
+			if m.progress[i].Cancelled {
+				break
+			}
+			if msg.Cancelled {
+				m.progress[i].Cancelled = true
+				m.progress[i].IsActive = false
+				break
+			}
+			applyProgressSample(&m.progress[i], msg.Percent, time.Now())
+			if msg.Err != nil {
+				m.progress[i].Error = msg.Err.Error()
+			}
+			if msg.Done || msg.Percent >= 1.0 {
+				m.progress[i].IsActive = false
+			}
+			break
+		}
+	}
+	return m, nil
+}
+
+// ==================== SECRET MANAGEMENT ====================
+
+// secretVault is the on-disk envelope for the encrypted secret store. Legacy
+// files are a bare JSON array of plaintext secrets; those are detected on load
+// and migrated to the encrypted envelope on the next save.
+type secretVault struct {
+	Salt    string   `json:"salt"`
+	Secrets []Secret `json:"secrets"`
+}
+
+// secretCipher encrypts and decrypts individual secret values with AES-GCM
+// using a key derived from the user's passphrase via scrypt. A fresh random
+// nonce is generated per value and prepended to the ciphertext.
+type secretCipher struct {
+	key []byte
+}
+
+func newSecretCipher(passphrase string, salt []byte) (*secretCipher, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 32768, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return &secretCipher{key: key}, nil
+}
+
+func (sc *secretCipher) Encrypt(plaintext string) (string, error) {
+	block, err := aes.NewCipher(sc.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (sc *secretCipher) Decrypt(ciphertext string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+	block, err := aes.NewCipher(sc.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("wrong passphrase or corrupted secret store")
+	}
+	return string(plaintext), nil
+}
+
+// vaultCipher and vaultSalt are initialized once at startup by
+// initSecretCipher. When vaultCipher is nil (e.g. in tests) secrets are
+// read and written in the legacy plaintext format.
+var (
+	vaultCipher *secretCipher
+	vaultSalt   []byte
+)
+
+func secretFilePath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".go-tui-secrets.json")
+}
+
+// initSecretCipher prompts for the vault passphrase, derives the encryption
+// key against the stored salt (creating a new salt for new or legacy vaults),
+// and verifies the passphrase by decrypting the existing secrets.
+func initSecretCipher() error {
+	fmt.Print("Vault passphrase: ")
+	passBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	data, err := os.ReadFile(secretFilePath())
+	if err == nil {
+		var vault secretVault
+		if json.Unmarshal(data, &vault) == nil && vault.Salt != "" {
+			salt, err = base64.StdEncoding.DecodeString(vault.Salt)
+			if err != nil {
+				return fmt.Errorf("invalid salt in secret store: %w", err)
+			}
+		} else if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	} else if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	sc, err := newSecretCipher(string(passBytes), salt)
+	if err != nil {
+		return err
+	}
+	vaultCipher = sc
+	vaultSalt = salt
+
+	// Verify the passphrase eagerly so a typo fails fast instead of
+	// surfacing as garbage when a secret is first used.
+	if _, err := loadSecrets(); err != nil {
+		vaultCipher = nil
+		vaultSalt = nil
+		return err
+	}
+	return nil
+}
+
+func loadSecrets() ([]Secret, error) {
+	data, err := os.ReadFile(secretFilePath())
+	if err != nil {
+		return []Secret{}, nil
+	}
+
+	var vault secretVault
+	if err := json.Unmarshal(data, &vault); err == nil && vault.Salt != "" {
+		// Encrypted vault: decrypt each value.
+		if vaultCipher == nil {
+			return nil, errors.New("secret store is encrypted but no passphrase was provided")
+		}
+		for i := range vault.Secrets {
+			plain, err := vaultCipher.Decrypt(vault.Secrets[i].Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt secret %q: %w", vault.Secrets[i].Name, err)
+			}
+			vault.Secrets[i].Value = plain
+		}
+		return vault.Secrets, nil
+	}
+
+	// Legacy plaintext format: a bare JSON array of secrets.
+	var secrets []Secret
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secret store: %w", err)
+	}
+	return secrets, nil
+}
+
+func saveSecrets(secrets []Secret) error {
+	if vaultCipher == nil {
+		// No cipher available: keep the legacy plaintext format.
+		data, err := json.MarshalIndent(secrets, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(secretFilePath(), data, 0600)
+	}
+
+	encrypted := make([]Secret, len(secrets))
+	copy(encrypted, secrets)
+	for i := range encrypted {
+		value, err := vaultCipher.Encrypt(encrypted[i].Value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret %q: %w", encrypted[i].Name, err)
+		}
+		encrypted[i].Value = value
+	}
+
+	vault := secretVault{
+		Salt:    base64.StdEncoding.EncodeToString(vaultSalt),
+		Secrets: encrypted,
+	}
+	data, err := json.MarshalIndent(vault, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(secretFilePath(), data, 0600)
+}
+
+func (m *Model) saveSecret() {
+	if m.editingSecret == nil || m.newSecretName == "" || m.newSecretValue == "" {
+		return
+	}
+
+	m.editingSecret.Name = m.newSecretName
+	m.editingSecret.Value = m.newSecretValue
+	m.editingSecret.UpdatedAt = time.Now()
+
+	if m.newSecretExpiry != "" {
+		expiry, err := parseRelativeExpiry(m.newSecretExpiry)
+		if err != nil {
+			m.setStatus(err.Error(), true)
+			return
+		}
+		m.editingSecret.ExpiresAt = &expiry
+	}
+
+	// Replace an existing secret with the same ID, otherwise append
+	updated := false
+	for i := range m.secrets {
+		if m.secrets[i].ID == m.editingSecret.ID {
+			m.secrets[i] = *m.editingSecret
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		m.secrets = append(m.secrets, *m.editingSecret)
+	}
+	saveSecrets(m.secrets)
+	m.auditSecret("edited", *m.editingSecret)
+
+	m.editingSecret = nil
+	m.newSecretName = ""
+	m.newSecretValue = ""
+	m.newSecretExpiry = ""
+	m.editingExpiry = false
+}
+
+// updateSecret loads the secret with the given ID into the editing form so
+// Enter saves it back in place via saveSecret.
+func (m *Model) updateSecret(id string) {
+	for i := range m.secrets {
+		if m.secrets[i].ID == id {
+			edit := m.secrets[i]
+			m.editingSecret = &edit
+			m.newSecretName = edit.Name
+			m.newSecretValue = edit.Value
+			return
+		}
+	}
+}
+
+// deleteSecret removes the secret with the given ID and persists the
+// remaining secrets. Other entries are never dropped.
+func (m *Model) deleteSecret(id string) {
+	remaining := make([]Secret, 0, len(m.secrets))
+	for _, secret := range m.secrets {
+		if secret.ID != id {
+			remaining = append(remaining, secret)
+		} else {
+			m.auditSecret("deleted", secret)
+		}
+	}
+	m.secrets = remaining
+	if m.selectedSecret >= len(m.secrets) && m.selectedSecret > 0 {
+		m.selectedSecret = len(m.secrets) - 1
+	}
+	saveSecrets(m.secrets)
+}
+
+// secretDirty reports whether an in-progress secret edit diverges from the
+// value it was loaded with (any input counts as dirty for a new secret).
+func (m Model) secretDirty() bool {
+	if m.editingSecret == nil {
+		return false
+	}
+	return m.newSecretName != m.editingSecret.Name || m.newSecretValue != m.editingSecret.Value
+}
+
+// auditSecret records a security event for an action on a secret. Only the
+// secret's name and ID are logged — never its value.
+func (m *Model) auditSecret(action string, secret Secret) {
+	if m.secretAuditLogger == nil {
+		return
+	}
+	m.secretAuditLogger.LogEvent(SystemEvent{
+		ID:        generateID(),
+		Timestamp: time.Now(),
+		Type:      string(EventTypeSecurity),
+		Source:    "secret-manager",
+		Message:   fmt.Sprintf("secret %s", action),
+		Data: map[string]interface{}{
+			"action":      action,
+			"secret_id":   secret.ID,
+			"secret_name": secret.Name,
+		},
+	})
+}
+
+// loadAuditEvents reads back the most recent n audit events from the audit
+// log, skipping malformed lines.
+func loadAuditEvents(path string, n int) []SystemEvent {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var events []SystemEvent
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var event SystemEvent
+		if json.Unmarshal([]byte(line), &event) == nil {
+			events = append(events, event)
+		}
+	}
+	if len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return events
+}
 
-	case 'p':
-		m.showProgress = !m.showProgress
-		m.showSecrets = !m.showSecrets
-		return m, nil
+// filterSecrets returns the secrets matching the query (case-insensitive
+// substring against Name and Tags) and, when tag is non-empty, carrying that
+// tag. The input slice is never mutated so clearing the query restores the
+// full list.
+func filterSecrets(secrets []Secret, query string, tag string) []Secret {
+	query = strings.ToLower(query)
+	filtered := make([]Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		if tag != "" && !hasTag(secret, tag) {
+			continue
+		}
+		if query != "" && !matchesQuery(secret, query) {
+			continue
+		}
+		filtered = append(filtered, secret)
+	}
+	return filtered
+}
 
-	case 'n':
-		if m.showSecrets {
-			m.editingSecret = &Secret{ID: generateID(), CreatedAt: time.Now()}
-			m.newSecretName = ""
-			m.newSecretValue = ""
+func hasTag(secret Secret, tag string) bool {
+	for _, t := range secret.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
 		}
-		return m, nil
+	}
+	return false
+}
 
-	case 'q':
-		if m.showSecrets && m.editingSecret != nil {
-			m.editingSecret = nil
-			m.newSecretName = ""
-			m.newSecretValue = ""
+func matchesQuery(secret Secret, lowerQuery string) bool {
+	if strings.Contains(strings.ToLower(secret.Name), lowerQuery) {
+		return true
+	}
+	for _, t := range secret.Tags {
+		if strings.Contains(strings.ToLower(t), lowerQuery) {
+			return true
 		}
-		return m, nil
+	}
+	return false
+}
 
-	case 'r':
-		if m.showSecrets {
-			m.refreshSecrets()
+// nextTagFilter cycles through the distinct tags present in the secrets,
+// returning "" (no filter) after the last one.
+func nextTagFilter(secrets []Secret, current string) string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, secret := range secrets {
+		for _, t := range secret.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
 		}
-		return m, nil
 	}
+	if len(tags) == 0 {
+		return ""
+	}
+	if current == "" {
+		return tags[0]
+	}
+	for i, t := range tags {
+		if t == current && i+1 < len(tags) {
+			return tags[i+1]
+		}
+	}
+	return ""
+}
 
-	return m, nil
+// visibleSecrets applies the active search query and tag filter.
+func (m Model) visibleSecrets() []Secret {
+	return filterSecrets(m.secrets, m.searchQuery, m.tagFilter)
 }
 
-// ==================== MOUSE HANDLING ====================
+func (m *Model) refreshSecrets() {
+	secrets, err := loadSecrets()
+	if err != nil {
+		return
+	}
+	m.secrets = secrets
+}
 
-func (m Model) handleMouse(msg bubbletea.MouseMsg) (Model, bubbletea.Cmd) {
-	if msg.Type == bubbletea.MouseLeft {
-		x, y := msg.X, msg.Y
-		
-		// Check if clicking on pane border for resizing
-		for i := range m.panes {
-			pane := &m.panes[i]
-			if m.isOnPaneBorder(x, y, pane) {
-				m.draggingPane = pane
-				m.dragStartX = x
-				m.dragStartY = y
-				return m, nil
-			}
-			
-			// Check if clicking inside pane
-			if m.isInsidePane(x, y, pane) {
-				m.focusedPane = i
-				m.activePane = i
-				return m, nil
-			}
+// ==================== EXPORT / IMPORT ====================
+
+// ExportFormat selects the on-disk format for vault export.
+type ExportFormat int
+
+const (
+	ExportJSON ExportFormat = iota
+	ExportDotenv
+)
+
+// exportSecrets writes the secrets to path in the given format. JSON keeps
+// the full secret records; dotenv writes NAME=VALUE pairs with newlines
+// escaped and values containing spaces quoted.
+func exportSecrets(secrets []Secret, path string, format ExportFormat) error {
+	switch format {
+	case ExportDotenv:
+		var b strings.Builder
+		for _, secret := range secrets {
+			b.WriteString(secret.Name)
+			b.WriteString("=")
+			b.WriteString(quoteDotenvValue(secret.Value))
+			b.WriteString("\n")
 		}
-	} else if msg.Type == bubbletea.MouseMotion && m.draggingPane != nil {
-		// Handle pane resizing
-		dx := msg.X - m.dragStartX
-		dy := msg.Y - m.dragStartY
-		
-		m.draggingPane.Width = max(20, m.draggingPane.Width+dx)
-		m.draggingPane.Height = max(5, m.draggingPane.Height+dy)
-		
-		m.dragStartX = msg.X
-		m.dragStartY = msg.Y
-		return m, nil
-	} else if msg.Type == bubbletea.MouseRelease {
-		m.draggingPane = nil
-		return m, nil
+		return os.WriteFile(path, []byte(b.String()), 0600)
+	default:
+		data, err := json.MarshalIndent(secrets, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0600)
 	}
-
-	return m, nil
 }
 
-// ==================== PROGRESS SYSTEM ====================
+func quoteDotenvValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	if strings.ContainsAny(value, " \t\"#") {
+		return "\"" + strings.ReplaceAll(value, "\"", "\\\"") + "\""
+	}
+	return value
+}
 
-type progressUpdateMsg struct {
-	ID      string
-	Percent float64
+func unquoteDotenvValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+		value = strings.ReplaceAll(value, "\\\"", "\"")
+	}
+	value = strings.ReplaceAll(value, "\\n", "\n")
+	value = strings.ReplaceAll(value, "\\\\", "\\")
+	return value
 }
 
-func (m Model) startProgressUpdates() bubbletea.Cmd {
-	return bubbletea.Tick(time.Second, func(t time.Time) bubbletea.Msg {
-		updates := []progressUpdateMsg{
-			{ID: "task1", Percent: min(1.0, m.progress[0].Percent+0.05)},
-			{ID: "task2", Percent: min(1.0, m.progress[1].Percent+0.03)},
-			{ID: "task3", Percent: min(1.0, m.progress[2].Percent+0.02)},
+// importSecrets reads secrets from path, auto-detecting JSON (a leading '['
+// or '{') vs dotenv format.
+func importSecrets(path string) ([]Secret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var secrets []Secret
+		if err := json.Unmarshal(data, &secrets); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON import: %w", err)
 		}
-		return updates
-	})
+		return secrets, nil
+	}
+
+	// Dotenv: NAME=VALUE per line, '#' comments allowed
+	var secrets []Secret
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, found := strings.Cut(line, "=")
+		if !found || name == "" {
+			continue
+		}
+		now := time.Now()
+		secrets = append(secrets, Secret{
+			ID:        generateID(),
+			Name:      strings.TrimSpace(name),
+			Value:     unquoteDotenvValue(strings.TrimSpace(value)),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+	return secrets, nil
 }
 
-func (m Model) updateProgress(msg progressUpdateMsg) (Model, bubbletea.Cmd) {
-	for i := range m.progress {
-		if m.progress[i].ID == msg.ID {
-			m.progress[i].Percent = msg.Percent
-			if msg.Percent >= 1.0 {
-				m.progress[i].IsActive = false
+// mergeSecrets merges imported secrets into existing ones by Name: matching
+// names update the stored value, new names append. It reports how many were
+// added vs. updated.
+func mergeSecrets(existing, imported []Secret) (merged []Secret, added, updated int) {
+	merged = make([]Secret, len(existing))
+	copy(merged, existing)
+
+	for _, in := range imported {
+		found := false
+		for i := range merged {
+			if merged[i].Name == in.Name {
+				merged[i].Value = in.Value
+				merged[i].UpdatedAt = time.Now()
+				updated++
+				found = true
+				break
 			}
-			break
+		}
+		if !found {
+			merged = append(merged, in)
+			added++
 		}
 	}
-	return m, nil
+	return merged, added, updated
 }
 
-// ==================== SECRET MANAGEMENT ====================
+// ==================== CLIPBOARD ====================
 
-func loadSecrets() []Secret {
-	home, _ := os.UserHomeDir()
-	secretFile := filepath.Join(home, ".go-tui-secrets.json")
-	
-	data, err := os.ReadFile(secretFile)
-	if err != nil {
-		return []Secret{}
-	}
-	
-	var secrets []Secret
-	if err := json.Unmarshal(data, &secrets); err != nil {
-		return []Secret{}
+// clipboard copies text to the OS clipboard using whichever platform command
+// is available (pbcopy/xclip/wl-copy/clip.exe).
+type clipboard struct{}
+
+func (clipboard) command() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("pbcopy"), nil
+	case "windows":
+		return exec.Command("clip.exe"), nil
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			if _, err := exec.LookPath("wl-copy"); err == nil {
+				return exec.Command("wl-copy"), nil
+			}
+		}
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return exec.Command("xclip", "-selection", "clipboard"), nil
+		}
+		return nil, errors.New("no clipboard command found (install xclip or wl-copy)")
 	}
-	
-	return secrets
 }
 
-func saveSecrets(secrets []Secret) error {
-	home, _ := os.UserHomeDir()
-	secretFile := filepath.Join(home, ".go-tui-secrets.json")
-	
-	data, err := json.MarshalIndent(secrets, "", "  ")
+func (c clipboard) Copy(s string) error {
+	cmd, err := c.command()
 	if err != nil {
 		return err
 	}
-	
-	return os.WriteFile(secretFile, data, 0600)
-}
-
-func (m *Model) saveSecret() {
-	if m.editingSecret == nil || m.newSecretName == "" || m.newSecretValue == "" {
-		return
+	cmd.Stdin = strings.NewReader(s)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("clipboard copy failed: %w", err)
 	}
-	
-	m.editingSecret.Name = m.newSecretName
-	m.editingSecret.Value = m.newSecretValue
-	m.editingSecret.UpdatedAt = time.Now()
-	
-	m.secrets = append(m.secrets, *m.editingSecret)
-	saveSecrets(m.secrets)
-	
-	m.editingSecret = nil
-	m.newSecretName = ""
-	m.newSecretValue = ""
+	return nil
 }
 
-func (m *Model) refreshSecrets() {
-	m.secrets = loadSecrets()
+// clipboardClearMsg asks the model to scrub the clipboard after the safety
+// timeout has elapsed.
+type clipboardClearMsg struct{}
+
+func scheduleClipboardClear() bubbletea.Cmd {
+	return bubbletea.Tick(30*time.Second, func(time.Time) bubbletea.Msg {
+		return clipboardClearMsg{}
+	})
 }
 
 // ==================== RENDER ====================
 
 func (m Model) View() string {
-	if m.width < 80 || m.height < 24 {
+	if terminalTooSmall(m, m.width, m.height) {
 		return m.renderTooSmall()
 	}
-	
+
 	var content strings.Builder
-	
+
 	// Header
 	content.WriteString(m.renderHeader())
-	
+
 	// Main content area
-	if m.showSecrets {
+	if m.showSecrets && m.showAuditLog {
+		content.WriteString(m.renderAuditLog())
+	} else if m.showSecrets {
 		content.WriteString(m.renderSecrets())
 	} else if m.showProgress {
 		content.WriteString(m.renderProgress())
 	} else {
 		content.WriteString(m.renderPanes())
 	}
-	
+
 	// Footer
 	content.WriteString(m.renderFooter())
-	
+
 	return content.String()
 }
 
@@ -409,25 +1638,25 @@ func (m Model) renderHeader() string {
 		Background(lipgloss.Color("#1E1E2E")).
 		Padding(0, 2).
 		Render("🚀 Multi-Pane TUI System")
-	
+
 	return lipgloss.PlaceHorizontal(m.width, lipgloss.Left, title)
 }
 
 func (m Model) renderPanes() string {
 	var content strings.Builder
-	
+
 	// Render each pane
 	for i, pane := range m.panes {
 		style := inactiveStyle
 		if i == m.activePane {
 			style = activeStyle
 		}
-		
+
 		// Create pane content
-		paneContent := fmt.Sprintf("%s\n\n%s", 
+		paneContent := fmt.Sprintf("%s\n\n%s",
 			titleStyle.Render(pane.Title),
 			pane.Content)
-		
+
 		// Truncate content to fit pane
 		lines := strings.Split(paneContent, "\n")
 		maxLines := pane.Height - 2 // Account for border
@@ -435,79 +1664,126 @@ func (m Model) renderPanes() string {
 			lines = lines[:maxLines]
 		}
 		paneContent = strings.Join(lines, "\n")
-		
+
 		// Render pane with border
 		renderedPane := style.Width(pane.Width).Height(pane.Height).Render(paneContent)
-		
+
 		// Position pane
-		content.WriteString(lipgloss.Place(m.height, m.width, 
+		content.WriteString(lipgloss.Place(m.width, m.height,
 			lipgloss.Left, lipgloss.Top,
 			renderedPane,
-			lipgloss.WithWhitespaceChars(" ", " "),
-			lipgloss.WithWhitespacePane(lipgloss.NewStyle().Background(lipgloss.Color("#1E1E2E")),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceBackground(lipgloss.Color("#1E1E2E")),
 		))
 	}
-	
+
 	return content.String()
 }
 
 func (m Model) renderProgress() string {
 	var content strings.Builder
-	
+
 	title := titleStyle.Render("📊 Progress Tracking")
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
 	content.WriteString("\n\n")
-	
+
 	// Create progress table
 	t := table.New().
 		Border(lipgloss.RoundedBorder()).
-		BorderStyle(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#6272A4"))).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			if row == 0 {
 				return titleStyle
 			}
 			return baseStyle
 		}).
-		Headers("TASK", "PROGRESS", "STATUS").
+		Headers("TASK", "PROGRESS", "ETA", "STATUS").
 		StyleFunc(func(row, col int) lipgloss.Style {
 			if row == 0 {
 				return titleStyle
 			}
 			return baseStyle
 		})
-	
-	for _, p := range m.progress {
+
+	for i, p := range m.progress {
 		status := "🔄 Active"
-		if !p.IsActive {
+		if p.Cancelled {
+			status = warningStyle.Render("🚫 cancelled")
+		} else if p.Error != "" {
+			status = errorStyle.Render("❌ " + p.Error)
+		} else if !p.IsActive {
 			status = "✅ Complete"
 		}
-		
-		progressBar := m.renderProgressBar(p.Percent, p.Color)
-		t.Row(p.Label, progressBar, status)
+
+		label := "  " + p.Label
+		if i == m.selectedProgress {
+			label = "▶ " + p.Label
+		}
+
+		eta := "—"
+		if remaining, ok := estimateETA(p, time.Now()); ok && !p.Indeterminate {
+			eta = fmt.Sprintf("~%s left", remaining.Round(time.Second))
+		}
+
+		var progressBar string
+		if p.Indeterminate {
+			progressBar = m.renderMarquee(p.Color)
+		} else {
+			progressBar = m.renderProgressBar(p.Percent, p.Color)
+		}
+		t.Row(label, progressBar, eta, status)
 	}
-	
+
 	content.WriteString(t.String())
 	return content.String()
 }
 
 func (m Model) renderSecrets() string {
 	var content strings.Builder
-	
-	title := titleStyle.Render("🔐 Secret Manager")
+
+	visible := m.visibleSecrets()
+
+	titleText := "🔐 Secret Manager"
+	if m.searchQuery != "" || m.tagFilter != "" {
+		titleText = fmt.Sprintf("🔐 Secret Manager (%d/%d matches)", len(visible), len(m.secrets))
+	}
+	title := titleStyle.Render(titleText)
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
 	content.WriteString("\n\n")
-	
+
+	if m.searchMode || m.searchQuery != "" || m.tagFilter != "" {
+		filterLine := fmt.Sprintf("Search: %s", m.searchQuery)
+		if m.tagFilter != "" {
+			filterLine += fmt.Sprintf("  Tag: %s", m.tagFilter)
+		}
+		content.WriteString(secretStyle.Render(filterLine))
+		content.WriteString("\n\n")
+	}
+
 	if m.editingSecret != nil {
 		// Editing form
 		content.WriteString(secretStyle.Render("📝 Add New Secret\n\n"))
 		content.WriteString(fmt.Sprintf("Name: %s\n", m.newSecretName))
-		content.WriteString(fmt.Sprintf("Value: %s\n", m.newSecretValue))
+		value := m.newSecretValue
+		if m.maskEditInput {
+			value = maskSecretValue(value)
+		}
+		content.WriteString(fmt.Sprintf("Value: %s\n", value))
+		expiryLabel := "Expiry"
+		if m.editingExpiry {
+			expiryLabel = "Expiry*"
+		}
+		content.WriteString(fmt.Sprintf("%s: %s\n", expiryLabel, m.newSecretExpiry))
 		content.WriteString("\n")
-		content.WriteString(baseStyle.Render("Commands: [Enter] Save [q] Cancel"))
+		if m.confirmingQuit {
+			content.WriteString(warningStyle.Render("Discard unsaved secret? [y/n]"))
+			content.WriteString("\n")
+		}
+		content.WriteString(baseStyle.Render(fmt.Sprintf("Commands: [Enter] Save [Ctrl+V] Reveal [Ctrl+E] Expiry [g] Generate (%d %s) [q] Cancel", m.genLength, m.genCharset)))
 	} else {
 		// Secrets list
-		if len(m.secrets) == 0 {
-			content.WriteString(warningStyle.Render("No secrets stored yet."))
+		if len(visible) == 0 {
+			content.WriteString(warningStyle.Render("No secrets match."))
 		} else {
 			t := table.New().
 				Border(lipgloss.RoundedBorder()).
@@ -517,52 +1793,149 @@ func (m Model) renderSecrets() string {
 					}
 					return secretStyle
 				}).
-				Headers("NAME", "CREATED", "TAGS")
-			
-			for _, secret := range m.secrets {
+				Headers("NAME", "VALUE", "CREATED", "STATUS", "TAGS")
+
+			for i, secret := range visible {
 				tags := strings.Join(secret.Tags, ", ")
 				if tags == "" {
 					tags = "-"
 				}
-				t.Row(secret.Name, secret.CreatedAt.Format("2006-01-02"), tags)
+				name := "  " + secret.Name
+				if i == m.selectedSecret {
+					name = "▶ " + secret.Name
+				}
+				value := maskSecretValue(secret.Value)
+				if secret.ID == m.revealedSecretID && time.Now().Before(m.revealExpiry) {
+					value = secret.Value
+				}
+				status := secretStatus(secret, time.Now())
+				switch status {
+				case secretStatusExpiring:
+					status = warningStyle.Render(status)
+				case secretStatusExpired:
+					status = errorStyle.Render(status)
+				}
+				t.Row(name, value, secret.CreatedAt.Format("2006-01-02"), status, tags)
 			}
-			
+
 			content.WriteString(t.String())
 		}
-		
+
 		content.WriteString("\n\n")
-		content.WriteString(baseStyle.Render("Commands: [n] New [r] Refresh [s] Switch to Panes"))
+		if m.confirmingDelete && m.selectedSecret < len(visible) {
+			content.WriteString(warningStyle.Render(fmt.Sprintf("Delete secret '%s'? [y/n]", visible[m.selectedSecret].Name)))
+		} else {
+			content.WriteString(baseStyle.Render("Commands: [n] New [e] Edit [d] Delete [/] Search [t] Tag Filter [r] Refresh [s] Switch to Panes"))
+		}
 	}
-	
+
+	return content.String()
+}
+
+func (m Model) renderAuditLog() string {
+	var content strings.Builder
+
+	title := titleStyle.Render("🧾 Secret Audit Log")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
+	content.WriteString("\n\n")
+
+	home, _ := os.UserHomeDir()
+	events := loadAuditEvents(filepath.Join(home, ".go-tui-secrets-audit.jsonl"), 20)
+	if len(events) == 0 {
+		content.WriteString(warningStyle.Render("No audit events recorded yet."))
+	} else {
+		for _, event := range events {
+			name, _ := event.Data["secret_name"].(string)
+			content.WriteString(fmt.Sprintf("%s  %-9s  %s\n",
+				event.Timestamp.Format("2006-01-02 15:04:05"),
+				event.Data["action"],
+				name))
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(baseStyle.Render("Commands: [a] Back to Secrets"))
 	return content.String()
 }
 
+// setStatus shows a transient message in the footer until its expiry.
+func (m *Model) setStatus(message string, isError bool) {
+	m.statusMessage = message
+	m.statusIsError = isError
+	m.statusExpiry = time.Now().Add(5 * time.Second)
+}
+
 func (m Model) renderFooter() string {
-	commands := "[Tab] Switch Pane | [s] Secrets | [p] Progress | [Ctrl+C] Quit"
-	if m.showSecrets {
-		commands = "[n] New | [r] Refresh | [q] Cancel | [s] Switch"
+	commands := "[Tab] Switch Pane | [s] Secrets | [p] Progress | [R] Resize | [Ctrl+C] Quit"
+	if m.resizeMode {
+		commands = warningStyle.Render("RESIZE") + " [↑↓←→] Resize | [Shift+↑↓←→] Move | [Esc] Done"
+	} else if m.showSecrets {
+		commands = "[n] New | [c] Copy | [r] Refresh | [q] Cancel | [s] Switch"
 	}
-	
+	if m.statusMessage != "" && time.Now().Before(m.statusExpiry) {
+		style := successStyle
+		if m.statusIsError {
+			style = errorStyle
+		}
+		commands = style.Render(m.statusMessage)
+	}
+
 	footerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#6C7086")).
 		Background(lipgloss.Color("#1E1E2E")).
 		Padding(0, 1)
-	
+
 	return lipgloss.Place(m.height, m.width,
 		lipgloss.Left, lipgloss.Bottom,
 		footerStyle.Render(commands),
 	)
 }
 
+// minSize implements minSizer for the collaborative TUI layout.
+func (m Model) minSize() (int, int) {
+	return 80, 24
+}
+
 func (m Model) renderTooSmall() string {
-	return errorStyle.Render("Terminal too small! Please resize to at least 80x24")
+	return errorStyle.Render(renderTooSmallView(m, m.width, m.height))
+}
+
+// renderMarquee renders an indeterminate bar: a spinner plus a block
+// sweeping across the same 20-cell track renderProgressBar uses, so mixed
+// rows stay aligned.
+func (m Model) renderMarquee(color string) string {
+	width := 20
+	pos := m.spinnerFrame % width
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		if i >= pos && i < pos+3 {
+			b.WriteString("█")
+		} else {
+			b.WriteString("░")
+		}
+	}
+	frame := spinnerFrames[m.spinnerFrame%len(spinnerFrames)]
+
+	barColor := lipgloss.Color("#50FA7B")
+	switch color {
+	case "blue":
+		barColor = lipgloss.Color("#86E1FC")
+	case "green":
+		barColor = lipgloss.Color("#50FA7B")
+	case "yellow":
+		barColor = lipgloss.Color("#FFB86C")
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(barColor).
+		Render(fmt.Sprintf("[%s] %s", b.String(), frame))
 }
 
 func (m Model) renderProgressBar(percent float64, color string) string {
 	width := 20
 	filled := int(percent * float64(width))
 	empty := width - filled
-	
+
 	barColor := lipgloss.Color("#50FA7B")
 	switch color {
 	case "blue":
@@ -572,12 +1945,12 @@ func (m Model) renderProgressBar(percent float64, color string) string {
 	case "yellow":
 		barColor = lipgloss.Color("#FFB86C")
 	}
-	
+
 	filledBar := strings.Repeat("█", filled)
 	emptyBar := strings.Repeat("░", empty)
-	
+
 	percentText := fmt.Sprintf("%.0f%%", percent*100)
-	
+
 	return lipgloss.NewStyle().
 		Foreground(barColor).
 		Render(fmt.Sprintf("[%s%s] %s", filledBar, emptyBar, percentText))
@@ -589,6 +1962,67 @@ func generateID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
+// SecretCharset selects the character set used by generateSecret.
+type SecretCharset int
+
+const (
+	CharsetAlphanumeric SecretCharset = iota
+	CharsetFullSymbol
+	CharsetHex
+)
+
+func (c SecretCharset) String() string {
+	switch c {
+	case CharsetFullSymbol:
+		return "symbols"
+	case CharsetHex:
+		return "hex"
+	default:
+		return "alphanumeric"
+	}
+}
+
+func (c SecretCharset) chars() string {
+	const alnum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	switch c {
+	case CharsetFullSymbol:
+		return alnum + "!@#$%^&*()-_=+[]{};:,.<>?/"
+	case CharsetHex:
+		return "0123456789abcdef"
+	default:
+		return alnum
+	}
+}
+
+// generateSecret returns a cryptographically-random string of the given
+// length drawn from the charset, using rejection sampling to avoid modulo
+// bias.
+func generateSecret(length int, charset SecretCharset) string {
+	chars := charset.chars()
+	out := make([]byte, 0, length)
+	buf := make([]byte, 1)
+	limit := 256 - 256%len(chars)
+	for len(out) < length {
+		if _, err := rand.Read(buf); err != nil {
+			continue
+		}
+		if int(buf[0]) >= limit {
+			continue
+		}
+		out = append(out, chars[int(buf[0])%len(chars)])
+	}
+	return string(out)
+}
+
+// maskSecretValue renders a secret value as a fixed-width run of dots so the
+// display never leaks the value's length.
+func maskSecretValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "••••••"
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -612,29 +2046,52 @@ func (m Model) isOnPaneBorder(x, y int, pane *Pane) bool {
 	// Check if on right or bottom border (for resizing)
 	onRightBorder := x == pane.X+pane.Width-1 && y >= pane.Y && y < pane.Y+pane.Height
 	onBottomBorder := y == pane.Y+pane.Height-1 && x >= pane.X && x < pane.X+pane.Width
-	
+
 	return onRightBorder || onBottomBorder
 }
 
 // ==================== MAIN ====================
 
 func main() {
+	// Headless mode drives the managers directly and emits JSON, for
+	// scripting and CI where there is no terminal at all.
+	for _, arg := range os.Args[1:] {
+		if arg == "--headless" {
+			if err := runHeadless(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "headless run failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if err := runSubcommand(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// RunTUI starts the collaborative TUI, the default subcommand.
+func RunTUI() error {
 	// Check terminal capabilities
 	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		fmt.Println("This application must be run in a terminal")
-		os.Exit(1)
+		return fmt.Errorf("this application must be run in a terminal")
+	}
+
+	// Unlock the secret store before entering the alternate screen
+	if err := initSecretCipher(); err != nil {
+		return fmt.Errorf("error unlocking secret store: %w", err)
 	}
-	
-	// Create and start the application
+
 	p := bubbletea.NewProgram(
 		initialModel(),
 		bubbletea.WithAltScreen(),
 		bubbletea.WithMouseCellMotion(),
 		bubbletea.WithMouseAllMotion(),
 	)
-	
+
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error starting application: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("error starting application: %w", err)
 	}
-}
\ No newline at end of file
+	return nil
+}
@@ -1,20 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	_ "modernc.org/sqlite"
 )
 
 // ==================== AI MODELS ====================
@@ -74,6 +79,55 @@ const (
 	RoleSystem    MessageRole = "system"
 )
 
+// LogLevel orders event severities so loggers can drop noise below a
+// threshold. Security events always rank highest.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelSecurity
+)
+
+// String returns the level's wire name, matching SystemEvent.Type values.
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelSecurity:
+		return "security"
+	default:
+		return "info"
+	}
+}
+
+// levelFromType maps a SystemEvent.Type string back to a LogLevel. Unknown
+// types rank as info so they are kept under the default threshold.
+func levelFromType(eventType string) LogLevel {
+	switch eventType {
+	case "debug":
+		return LevelDebug
+	case "info":
+		return LevelInfo
+	case "warning", "warn":
+		return LevelWarn
+	case "error":
+		return LevelError
+	case "security":
+		return LevelSecurity
+	default:
+		return LevelInfo
+	}
+}
+
 // ==================== INTERFACES ====================
 
 // Logger defines interface for logging operations
@@ -92,10 +146,22 @@ type Animator interface {
 
 // ==================== IMPLEMENTATIONS ====================
 
-// FileLogger implements Logger interface with file storage
+// FileLogger implements Logger interface with file storage. When MaxBytes
+// is set, files that grow past it are rotated to numbered backups
+// (events.jsonl.1 being the newest) keeping at most MaxBackups of them.
 type FileLogger struct {
 	eventsFile        string
 	conversationsFile string
+
+	// MaxBytes of 0 disables rotation; MaxBackups of 0 keeps no backups.
+	MaxBytes   int64
+	MaxBackups int
+
+	// MinLevel drops events below the threshold before they hit disk.
+	// The zero value (LevelDebug) keeps everything.
+	MinLevel LogLevel
+
+	mu sync.Mutex
 }
 
 func NewFileLogger(dataDir string) *FileLogger {
@@ -105,43 +171,532 @@ func NewFileLogger(dataDir string) *FileLogger {
 	}
 }
 
-func (fl *FileLogger) LogEvent(event SystemEvent) error {
-	data, err := json.Marshal(event)
+// rotateIfNeeded shifts path's numbered backups up by one and moves the
+// active file to path.1 once it exceeds MaxBytes. Callers hold fl.mu.
+func (fl *FileLogger) rotateIfNeeded(path string) error {
+	if fl.MaxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < fl.MaxBytes {
+		return nil
+	}
+
+	if fl.MaxBackups <= 0 {
+		return os.Remove(path)
+	}
+
+	// Shift older backups out of the way, dropping the oldest
+	os.Remove(fmt.Sprintf("%s.%d", path, fl.MaxBackups))
+	for i := fl.MaxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", path, err)
+	}
+	return nil
+}
+
+// appendLine marshals v and appends it as one JSONL line, rotating first if
+// the file has outgrown MaxBytes.
+func (fl *FileLogger) appendLine(path string, v interface{}, what string) error {
+	data, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return fmt.Errorf("failed to marshal %s: %w", what, err)
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if err := fl.rotateIfNeeded(path); err != nil {
+		return err
 	}
 
-	file, err := os.OpenFile(fl.eventsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open events file: %w", err)
+		return fmt.Errorf("failed to open %s file: %w", what, err)
 	}
 	defer file.Close()
 
 	_, err = file.WriteString(string(data) + "\n")
 	if err != nil {
-		return fmt.Errorf("failed to write event: %w", err)
+		return fmt.Errorf("failed to write %s: %w", what, err)
 	}
 
 	return nil
 }
 
+func (fl *FileLogger) LogEvent(event SystemEvent) error {
+	if levelFromType(event.Type) < fl.MinLevel {
+		return nil
+	}
+	return fl.appendLine(fl.eventsFile, event, "event")
+}
+
+// logAt builds and writes an event at the given level.
+func (fl *FileLogger) logAt(level LogLevel, source, msg string, data map[string]interface{}) error {
+	return fl.LogEvent(SystemEvent{
+		ID:        generateID(),
+		Timestamp: time.Now(),
+		Type:      level.String(),
+		Source:    source,
+		Message:   msg,
+		Data:      data,
+	})
+}
+
+// Debug logs a debug-level event.
+func (fl *FileLogger) Debug(source, msg string, data map[string]interface{}) error {
+	return fl.logAt(LevelDebug, source, msg, data)
+}
+
+// Info logs an info-level event.
+func (fl *FileLogger) Info(source, msg string, data map[string]interface{}) error {
+	return fl.logAt(LevelInfo, source, msg, data)
+}
+
+// Warn logs a warning-level event.
+func (fl *FileLogger) Warn(source, msg string, data map[string]interface{}) error {
+	return fl.logAt(LevelWarn, source, msg, data)
+}
+
+// Error logs an error-level event.
+func (fl *FileLogger) Error(source, msg string, data map[string]interface{}) error {
+	return fl.logAt(LevelError, source, msg, data)
+}
+
 func (fl *FileLogger) LogConversation(session ConversationSession) error {
-	data, err := json.Marshal(session)
+	return fl.appendLine(fl.conversationsFile, session, "conversation")
+}
+
+// asyncLogEntry carries either an event or a session through the queue.
+type asyncLogEntry struct {
+	event   *SystemEvent
+	session *ConversationSession
+}
+
+// AsyncFileLogger wraps FileLogger behind a buffered channel and a single
+// background writer goroutine, so LogEvent callers never pay for the
+// open/append/close on their own path. Close drains the queue and fsyncs,
+// guaranteeing nothing enqueued before Close is lost.
+type AsyncFileLogger struct {
+	inner  *FileLogger
+	queue  chan asyncLogEntry
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewAsyncFileLogger starts the writer goroutine with the given queue depth.
+func NewAsyncFileLogger(dataDir string, bufferSize int) *AsyncFileLogger {
+	if bufferSize <= 0 {
+		bufferSize = 256
+	}
+	al := &AsyncFileLogger{
+		inner: NewFileLogger(dataDir),
+		queue: make(chan asyncLogEntry, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go al.drain()
+	return al
+}
+
+func (al *AsyncFileLogger) drain() {
+	defer close(al.done)
+	for entry := range al.queue {
+		if entry.event != nil {
+			if err := al.inner.LogEvent(*entry.event); err != nil {
+				log.Printf("async log event failed: %v", err)
+			}
+		}
+		if entry.session != nil {
+			if err := al.inner.LogConversation(*entry.session); err != nil {
+				log.Printf("async log conversation failed: %v", err)
+			}
+		}
+	}
+}
+
+// enqueue blocks when the buffer is full rather than dropping, preserving
+// the no-loss guarantee.
+func (al *AsyncFileLogger) enqueue(entry asyncLogEntry) error {
+	al.mu.Lock()
+	if al.closed {
+		al.mu.Unlock()
+		return fmt.Errorf("logger is closed")
+	}
+	al.mu.Unlock()
+	al.queue <- entry
+	return nil
+}
+
+func (al *AsyncFileLogger) LogEvent(event SystemEvent) error {
+	return al.enqueue(asyncLogEntry{event: &event})
+}
+
+func (al *AsyncFileLogger) LogConversation(session ConversationSession) error {
+	return al.enqueue(asyncLogEntry{session: &session})
+}
+
+// Close stops accepting entries, waits for the queue to drain, and fsyncs
+// both files to disk.
+func (al *AsyncFileLogger) Close() error {
+	al.mu.Lock()
+	if al.closed {
+		al.mu.Unlock()
+		return nil
+	}
+	al.closed = true
+	al.mu.Unlock()
+
+	close(al.queue)
+	<-al.done
+
+	for _, path := range []string{al.inner.eventsFile, al.inner.conversationsFile} {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0644)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to open %s for sync: %w", path, err)
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to sync %s: %w", path, err)
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// EventFilter narrows what ReadEvents returns. Zero values mean "no
+// constraint"; Limit of 0 returns everything that matches.
+type EventFilter struct {
+	Type   string
+	Source string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// matches reports whether the event passes every set constraint.
+func (f EventFilter) matches(event SystemEvent) bool {
+	if f.Type != "" && event.Type != f.Type {
+		return false
+	}
+	if f.Source != "" && event.Source != f.Source {
+		return false
+	}
+	if !f.Since.IsZero() && event.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ConversationFilter narrows what ReadConversations returns.
+type ConversationFilter struct {
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+// ReadEvents parses the events file line-by-line and returns events passing
+// the filter. Malformed lines are counted and skipped rather than failing
+// the whole read; the count is returned alongside the events.
+func (fl *FileLogger) ReadEvents(filter EventFilter) ([]SystemEvent, int, error) {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	file, err := os.Open(fl.eventsFile)
 	if err != nil {
-		return fmt.Errorf("failed to marshal conversation: %w", err)
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer file.Close()
+
+	var events []SystemEvent
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var event SystemEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			skipped++
+			continue
+		}
+		if !filter.matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return events, skipped, fmt.Errorf("failed to read events file: %w", err)
 	}
+	return events, skipped, nil
+}
+
+// ReadConversations is the conversations-file analogue of ReadEvents.
+func (fl *FileLogger) ReadConversations(filter ConversationFilter) ([]ConversationSession, int, error) {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
 
-	file, err := os.OpenFile(fl.conversationsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.Open(fl.conversationsFile)
 	if err != nil {
-		return fmt.Errorf("failed to open conversations file: %w", err)
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to open conversations file: %w", err)
 	}
 	defer file.Close()
 
-	_, err = file.WriteString(string(data) + "\n")
+	var sessions []ConversationSession
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var session ConversationSession
+		if err := json.Unmarshal([]byte(line), &session); err != nil {
+			skipped++
+			continue
+		}
+		if !filter.Since.IsZero() && session.StartTime.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && session.StartTime.After(filter.Until) {
+			continue
+		}
+		sessions = append(sessions, session)
+		if filter.Limit > 0 && len(sessions) >= filter.Limit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sessions, skipped, fmt.Errorf("failed to read conversations file: %w", err)
+	}
+	return sessions, skipped, nil
+}
+
+// SQLiteLogger implements the Logger interface on top of an indexed SQLite
+// database, so the monitoring pane can run aggregate queries cheaply.
+// FileLogger remains the default backend.
+type SQLiteLogger struct {
+	db *sql.DB
+}
+
+// NewSQLiteLogger opens (or creates) the database at path and ensures the
+// schema exists.
+func NewSQLiteLogger(path string) (*SQLiteLogger, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	schema := `
+CREATE TABLE IF NOT EXISTS events (
+	id        TEXT PRIMARY KEY,
+	timestamp TEXT NOT NULL,
+	type      TEXT NOT NULL,
+	source    TEXT NOT NULL,
+	message   TEXT NOT NULL,
+	data      TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
+CREATE INDEX IF NOT EXISTS idx_events_source ON events(source);
+CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp);
+CREATE TABLE IF NOT EXISTS conversations (
+	id         TEXT PRIMARY KEY,
+	start_time TEXT NOT NULL,
+	end_time   TEXT,
+	payload    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_conversations_start ON conversations(start_time);
+`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return &SQLiteLogger{db: db}, nil
+}
+
+func (sl *SQLiteLogger) LogEvent(event SystemEvent) error {
+	var dataJSON []byte
+	if event.Data != nil {
+		var err error
+		dataJSON, err = json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event data: %w", err)
+		}
+	}
+	_, err := sl.db.Exec(
+		`INSERT OR REPLACE INTO events (id, timestamp, type, source, message, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		event.ID, event.Timestamp.UTC().Format(time.RFC3339Nano),
+		event.Type, event.Source, event.Message, string(dataJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+	return nil
+}
+
+func (sl *SQLiteLogger) LogConversation(session ConversationSession) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation: %w", err)
+	}
+	var endTime interface{}
+	if session.EndTime != nil {
+		endTime = session.EndTime.UTC().Format(time.RFC3339Nano)
+	}
+	_, err = sl.db.Exec(
+		`INSERT OR REPLACE INTO conversations (id, start_time, end_time, payload) VALUES (?, ?, ?, ?)`,
+		session.ID, session.StartTime.UTC().Format(time.RFC3339Nano), endTime, string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert conversation: %w", err)
+	}
+	return nil
+}
+
+// ReadEvents mirrors FileLogger.ReadEvents semantics, translated to SQL.
+// The skipped count covers rows whose stored JSON no longer parses.
+func (sl *SQLiteLogger) ReadEvents(filter EventFilter) ([]SystemEvent, int, error) {
+	query := `SELECT id, timestamp, type, source, message, data FROM events WHERE 1=1`
+	var args []interface{}
+	if filter.Type != "" {
+		query += ` AND type = ?`
+		args = append(args, filter.Type)
+	}
+	if filter.Source != "" {
+		query += ` AND source = ?`
+		args = append(args, filter.Source)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND timestamp >= ?`
+		args = append(args, filter.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.Until.IsZero() {
+		query += ` AND timestamp <= ?`
+		args = append(args, filter.Until.UTC().Format(time.RFC3339Nano))
+	}
+	query += ` ORDER BY timestamp`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := sl.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []SystemEvent
+	skipped := 0
+	for rows.Next() {
+		var event SystemEvent
+		var timestamp, dataJSON string
+		if err := rows.Scan(&event.ID, &timestamp, &event.Type, &event.Source, &event.Message, &dataJSON); err != nil {
+			skipped++
+			continue
+		}
+		if event.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp); err != nil {
+			skipped++
+			continue
+		}
+		if dataJSON != "" {
+			if err := json.Unmarshal([]byte(dataJSON), &event.Data); err != nil {
+				skipped++
+				continue
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return events, skipped, fmt.Errorf("failed to read event rows: %w", err)
+	}
+	return events, skipped, nil
+}
+
+// CountEventsByType returns aggregate event counts keyed by type.
+func (sl *SQLiteLogger) CountEventsByType() (map[string]int, error) {
+	rows, err := sl.db.Query(`SELECT type, COUNT(*) FROM events GROUP BY type`)
 	if err != nil {
-		return fmt.Errorf("failed to write conversation: %w", err)
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var eventType string
+		var count int
+		if err := rows.Scan(&eventType, &count); err != nil {
+			return nil, err
+		}
+		counts[eventType] = count
 	}
+	return counts, rows.Err()
+}
+
+// Close releases the underlying database handle.
+func (sl *SQLiteLogger) Close() error {
+	return sl.db.Close()
+}
 
+// ExportTranscript renders a conversation session as readable Markdown: a
+// header with start/end/duration, then one entry per message. System
+// messages are shown as blockquotes.
+func ExportTranscript(session ConversationSession, w io.Writer) error {
+	end := "ongoing"
+	duration := "ongoing"
+	if session.EndTime != nil {
+		end = session.EndTime.Format(time.RFC3339)
+		duration = session.EndTime.Sub(session.StartTime).Round(time.Second).String()
+	}
+
+	if _, err := fmt.Fprintf(w, "# Conversation %s\n\n", session.ID); err != nil {
+		return fmt.Errorf("failed to write transcript header: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "- Started: %s\n- Ended: %s\n- Duration: %s\n\n",
+		session.StartTime.Format(time.RFC3339), end, duration); err != nil {
+		return fmt.Errorf("failed to write transcript header: %w", err)
+	}
+
+	if len(session.Messages) == 0 {
+		_, err := fmt.Fprintln(w, "_No messages._")
+		return err
+	}
+
+	for _, msg := range session.Messages {
+		var err error
+		if msg.Role == string(RoleSystem) {
+			// Blockquote every line so multiline system notes stay quoted
+			_, err = fmt.Fprintf(w, "> **%s** (%s): %s\n\n",
+				msg.Role, msg.Timestamp.Format(time.RFC3339),
+				strings.ReplaceAll(msg.Content, "\n", "\n> "))
+		} else {
+			_, err = fmt.Fprintf(w, "**%s** (%s): %s\n\n",
+				msg.Role, msg.Timestamp.Format(time.RFC3339), msg.Content)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write transcript message: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -202,6 +757,234 @@ type Fish struct {
 	WavePhase float64 `json:"wave_phase"`
 }
 
+// AnimatorConfig tunes the underwater animation. Zero-valued fields fall
+// back to the built-in defaults via withDefaults; negative counts are
+// rejected by validate.
+type AnimatorConfig struct {
+	ParticleCount int      `json:"particle_count"`
+	StarCount     int      `json:"star_count"`
+	FishCount     int      `json:"fish_count"`
+	Planets       []Planet `json:"planets"`
+	BaseSpeed     float64  `json:"base_speed"`
+	OctopusSpeed  float64  `json:"octopus_speed"`
+}
+
+func defaultAnimatorConfig() AnimatorConfig {
+	return AnimatorConfig{
+		ParticleCount: 50,
+		StarCount:     100,
+		FishCount:     5,
+		Planets: []Planet{
+			{X: 20, Y: 10, Orbit: 15, Size: 2, Color: "#FF6B6B", Speed: 0.02, Angle: 0},
+			{X: 70, Y: 20, Orbit: 8, Size: 1.5, Color: "#4ECDC4", Speed: 0.03, Angle: math.Pi},
+			{X: 50, Y: 5, Orbit: 12, Size: 1, Color: "#95E1D3", Speed: 0.015, Angle: math.Pi / 2},
+		},
+		BaseSpeed:    1.0,
+		OctopusSpeed: 0.01,
+	}
+}
+
+// withDefaults fills any unset (zero) fields from the defaults.
+func (c AnimatorConfig) withDefaults() AnimatorConfig {
+	defaults := defaultAnimatorConfig()
+	if c.ParticleCount == 0 {
+		c.ParticleCount = defaults.ParticleCount
+	}
+	if c.StarCount == 0 {
+		c.StarCount = defaults.StarCount
+	}
+	if c.FishCount == 0 {
+		c.FishCount = defaults.FishCount
+	}
+	if len(c.Planets) == 0 {
+		c.Planets = defaults.Planets
+	}
+	if c.BaseSpeed == 0 {
+		c.BaseSpeed = defaults.BaseSpeed
+	}
+	if c.OctopusSpeed == 0 {
+		c.OctopusSpeed = defaults.OctopusSpeed
+	}
+	return c
+}
+
+// validate rejects configs that can't be animated.
+func (c AnimatorConfig) validate() error {
+	if c.ParticleCount < 0 || c.StarCount < 0 || c.FishCount < 0 {
+		return fmt.Errorf("animation counts must be non-negative")
+	}
+	if c.BaseSpeed < 0 || c.OctopusSpeed < 0 {
+		return fmt.Errorf("animation speeds must be non-negative")
+	}
+	return nil
+}
+
+// loadAnimatorConfig reads ~/.ai-tui-anim.json, returning the defaults when
+// the file doesn't exist and an error when it's malformed or invalid.
+func loadAnimatorConfig() (AnimatorConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return defaultAnimatorConfig(), nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".ai-tui-anim.json"))
+	if err != nil {
+		return defaultAnimatorConfig(), nil
+	}
+
+	var cfg AnimatorConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return AnimatorConfig{}, fmt.Errorf("malformed animation config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return AnimatorConfig{}, err
+	}
+	return cfg.withDefaults(), nil
+}
+
+// Recorder captures rendered animation frames into an asciicast v2 file
+// (the format asciinema understands) so demos can be replayed with
+// `asciinema play`. Frames carry timing relative to StartRecording.
+type Recorder struct {
+	mu     sync.Mutex
+	file   *os.File
+	start  time.Time
+	width  int
+	height int
+}
+
+// NewRecorder prepares a recorder for a terminal of the given dimensions.
+func NewRecorder(width, height int) *Recorder {
+	return &Recorder{width: width, height: height}
+}
+
+// StartRecording opens path and writes the asciicast v2 header.
+func (r *Recorder) StartRecording(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != nil {
+		return fmt.Errorf("already recording")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create cast file: %w", err)
+	}
+	header := map[string]interface{}{
+		"version":   2,
+		"width":     r.width,
+		"height":    r.height,
+		"timestamp": time.Now().Unix(),
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cast header: %w", err)
+	}
+	r.file = f
+	r.start = time.Now()
+	return nil
+}
+
+// WriteFrame appends one output event line: [elapsed, "o", data].
+func (r *Recorder) WriteFrame(frame string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	elapsed := time.Since(r.start).Seconds()
+	line, err := json.Marshal([]interface{}{elapsed, "o", frame})
+	if err != nil {
+		return err
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write cast frame: %w", err)
+	}
+	return nil
+}
+
+// IsRecording reports whether a cast file is currently open.
+func (r *Recorder) IsRecording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file != nil
+}
+
+// StopRecording flushes and closes the cast file.
+func (r *Recorder) StopRecording() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// AdaptiveTicker adjusts the animation tick interval based on how long the
+// last frame (Update+Render) actually took, so slow terminals don't fall
+// behind and fast ones don't burn CPU past the target frame rate.
+type AdaptiveTicker struct {
+	targetFPS float64
+	minFPS    float64
+	interval  time.Duration
+	lastCost  time.Duration
+}
+
+// NewAdaptiveTicker targets at most maxFPS and never throttles below 10 FPS.
+func NewAdaptiveTicker(maxFPS float64) *AdaptiveTicker {
+	if maxFPS <= 0 {
+		maxFPS = 60
+	}
+	return &AdaptiveTicker{
+		targetFPS: maxFPS,
+		minFPS:    10,
+		interval:  time.Duration(float64(time.Second) / maxFPS),
+	}
+}
+
+// RecordFrame feeds the measured cost of the last frame and lengthens (or
+// re-shrinks) the tick interval to keep within the frame budget.
+func (at *AdaptiveTicker) RecordFrame(cost time.Duration) {
+	at.lastCost = cost
+	budget := time.Duration(float64(time.Second) / at.targetFPS)
+	if cost > budget {
+		// Frame blew the budget: slow down to what we can actually sustain.
+		at.interval = cost
+	} else {
+		// Ease back toward the target rate.
+		at.interval = budget + (at.interval-budget)/2
+	}
+	floor := time.Duration(float64(time.Second) / at.minFPS)
+	if at.interval > floor {
+		at.interval = floor
+	}
+	if at.interval < budget {
+		at.interval = budget
+	}
+}
+
+// Interval is the delay to use for the next tick.
+func (at *AdaptiveTicker) Interval() time.Duration {
+	return at.interval
+}
+
+// EffectiveFPS reports the frame rate the current interval works out to.
+func (at *AdaptiveTicker) EffectiveFPS() float64 {
+	if at.interval <= 0 {
+		return at.targetFPS
+	}
+	return float64(time.Second) / float64(at.interval)
+}
+
 // UnderwaterAnimator implements Animator interface
 type UnderwaterAnimator struct {
 	particles   []Particle
@@ -212,53 +995,81 @@ type UnderwaterAnimator struct {
 	gradientPos float64
 	isPaused    bool
 	speed       float64
+	rng         *rand.Rand
+
+	// baseParticles is the configured ambient particle count; anything
+	// beyond it came from Emit and is pruned instead of recycled.
+	baseParticles int
+
+	// Boids tuning for fish schooling
+	separationRadius float64
+	cohesionWeight   float64
+
+	// pausedLayers freezes individual layers while the rest keep moving
+	pausedLayers map[AnimationLayer]bool
 }
 
 func NewUnderwaterAnimator() *UnderwaterAnimator {
-	rand.Seed(time.Now().UnixNano())
+	return NewUnderwaterAnimatorWithSeed(time.Now().UnixNano())
+}
+
+// NewUnderwaterAnimatorWithSeed builds the default scene from a fixed seed,
+// making the resulting animation fully reproducible.
+func NewUnderwaterAnimatorWithSeed(seed int64) *UnderwaterAnimator {
+	return newUnderwaterAnimator(defaultAnimatorConfig(), seed)
+}
+
+// NewUnderwaterAnimatorFromConfig builds the underwater scene using the
+// given (already-defaulted) configuration.
+func NewUnderwaterAnimatorFromConfig(cfg AnimatorConfig) *UnderwaterAnimator {
+	return newUnderwaterAnimator(cfg, time.Now().UnixNano())
+}
+
+// newUnderwaterAnimator owns a private *rand.Rand seeded as requested so
+// initialization and resets never touch (or pollute) the global RNG.
+func newUnderwaterAnimator(cfg AnimatorConfig, seed int64) *UnderwaterAnimator {
+	rng := rand.New(rand.NewSource(seed))
+	cfg = cfg.withDefaults()
 
 	// Create initial particles
-	particles := make([]Particle, 50)
+	particles := make([]Particle, cfg.ParticleCount)
 	for i := range particles {
 		particles[i] = Particle{
-			X:           rand.Float64() * 100,
-			Y:           rand.Float64() * 30,
-			VX:          (rand.Float64() - 0.5) * 0.2,
-			VY:          (rand.Float64() - 0.5) * 0.1,
-			Size:        rand.Float64()*2 + 0.5,
-			Color:       getRandomColor(),
-			Opacity:     rand.Float64(),
+			X:           rng.Float64() * 100,
+			Y:           rng.Float64() * 30,
+			VX:          (rng.Float64() - 0.5) * 0.2,
+			VY:          (rng.Float64() - 0.5) * 0.1,
+			Size:        rng.Float64()*2 + 0.5,
+			Color:       randomColor(rng),
+			Opacity:     rng.Float64(),
 			Lifetime:    0,
-			MaxLifetime: rand.Float64()*100 + 50,
+			MaxLifetime: rng.Float64()*100 + 50,
 		}
 	}
 
 	// Create stars
-	stars := make([]Star, 100)
+	stars := make([]Star, cfg.StarCount)
 	for i := range stars {
 		stars[i] = Star{
-			X:       rand.Float64() * 100,
-			Y:       rand.Float64() * 30,
-			Size:    rand.Float64()*1.5 + 0.5,
-			Bright:  rand.Float64(),
-			Twinkle: rand.Float64() * math.Pi * 2,
+			X:       rng.Float64() * 100,
+			Y:       rng.Float64() * 30,
+			Size:    rng.Float64()*1.5 + 0.5,
+			Bright:  rng.Float64(),
+			Twinkle: rng.Float64() * math.Pi * 2,
 		}
 	}
 
 	// Create planets
-	planets := []Planet{
-		{X: 20, Y: 10, Orbit: 15, Size: 2, Color: "#FF6B6B", Speed: 0.02, Angle: 0},
-		{X: 70, Y: 20, Orbit: 8, Size: 1.5, Color: "#4ECDC4", Speed: 0.03, Angle: math.Pi},
-		{X: 50, Y: 5, Orbit: 12, Size: 1, Color: "#95E1D3", Speed: 0.015, Angle: math.Pi / 2},
-	}
+	planets := make([]Planet, len(cfg.Planets))
+	copy(planets, cfg.Planets)
 
 	// Create octopus
 	tentacles := make([]Tentacle, 8)
 	for i := range tentacles {
 		tentacles[i] = Tentacle{
 			Angle:  float64(i) * (math.Pi * 2 / 8),
-			Length: 3 + rand.Float64()*2,
-			Wave:   rand.Float64() * math.Pi * 2,
+			Length: 3 + rng.Float64()*2,
+			Wave:   rng.Float64() * math.Pi * 2,
 		}
 	}
 
@@ -268,20 +1079,20 @@ func NewUnderwaterAnimator() *UnderwaterAnimator {
 		Angle:     0,
 		Tentacles: tentacles,
 		Color:     "#9B59B6",
-		Speed:     0.01,
+		Speed:     cfg.OctopusSpeed,
 	}
 
 	// Create fish
-	fish := make([]Fish, 5)
+	fish := make([]Fish, cfg.FishCount)
 	for i := range fish {
 		fish[i] = Fish{
-			X:         rand.Float64() * 100,
-			Y:         15 + rand.Float64()*15,
-			Angle:     rand.Float64() * math.Pi * 2,
-			Speed:     0.02 + rand.Float64()*0.02,
-			Size:      1 + rand.Float64(),
-			Color:     getRandomFishColor(),
-			WavePhase: rand.Float64() * math.Pi * 2,
+			X:         rng.Float64() * 100,
+			Y:         15 + rng.Float64()*15,
+			Angle:     rng.Float64() * math.Pi * 2,
+			Speed:     0.02 + rng.Float64()*0.02,
+			Size:      1 + rng.Float64(),
+			Color:     randomFishColor(rng),
+			WavePhase: rng.Float64() * math.Pi * 2,
 		}
 	}
 
@@ -293,7 +1104,15 @@ func NewUnderwaterAnimator() *UnderwaterAnimator {
 		fish:        fish,
 		gradientPos: 0,
 		isPaused:    false,
-		speed:       1.0,
+		speed:       cfg.BaseSpeed,
+		rng:         rng,
+
+		baseParticles: cfg.ParticleCount,
+
+		separationRadius: 4.0,
+		cohesionWeight:   0.005,
+
+		pausedLayers: make(map[AnimationLayer]bool),
 	}
 }
 
@@ -304,6 +1123,9 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 
 	// Update particles
 	for i := range ua.particles {
+		if ua.pausedLayers[LayerParticles] {
+			break
+		}
 		p := &ua.particles[i]
 		p.X += p.VX * deltaTime
 		p.Y += p.VY * deltaTime
@@ -321,19 +1143,34 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 			p.Y = 0
 		}
 
-		// Reset particle if lifetime exceeded
-		if p.Lifetime > p.MaxLifetime {
-			p.X = rand.Float64() * 100
-			p.Y = rand.Float64() * 30
-			p.VX = (rand.Float64() - 0.5) * 0.2
-			p.VY = (rand.Float64() - 0.5) * 0.1
+		// Reset ambient particles if lifetime exceeded; emitted extras
+		// are pruned below instead
+		if p.Lifetime > p.MaxLifetime && i < ua.baseParticles {
+			p.X = ua.rng.Float64() * 100
+			p.Y = ua.rng.Float64() * 30
+			p.VX = (ua.rng.Float64() - 0.5) * 0.2
+			p.VY = (ua.rng.Float64() - 0.5) * 0.1
 			p.Lifetime = 0
-			p.MaxLifetime = rand.Float64()*100 + 50
+			p.MaxLifetime = ua.rng.Float64()*100 + 50
+		}
+	}
+
+	// Prune expired burst particles
+	if len(ua.particles) > ua.baseParticles && !ua.pausedLayers[LayerParticles] {
+		kept := ua.particles[:ua.baseParticles]
+		for _, p := range ua.particles[ua.baseParticles:] {
+			if p.Lifetime <= p.MaxLifetime {
+				kept = append(kept, p)
+			}
 		}
+		ua.particles = kept
 	}
 
 	// Update stars (twinkling)
 	for i := range ua.stars {
+		if ua.pausedLayers[LayerStars] {
+			break
+		}
 		star := &ua.stars[i]
 		star.Twinkle += deltaTime * 0.1
 		star.Bright = 0.5 + 0.5*math.Sin(star.Twinkle)
@@ -341,6 +1178,9 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 
 	// Update planets (orbiting)
 	for i := range ua.planets {
+		if ua.pausedLayers[LayerPlanets] {
+			break
+		}
 		planet := &ua.planets[i]
 		planet.Angle += planet.Speed * deltaTime
 		planet.X = 50 + math.Cos(planet.Angle)*planet.Orbit
@@ -348,7 +1188,7 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 	}
 
 	// Update octopus
-	if ua.octopus != nil {
+	if ua.octopus != nil && !ua.pausedLayers[LayerOctopus] {
 		ua.octopus.Angle += ua.octopus.Speed * deltaTime
 		ua.octopus.X = 50 + math.Cos(ua.octopus.Angle)*5
 		ua.octopus.Y = 25 + math.Sin(ua.octopus.Angle)*2
@@ -361,8 +1201,14 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 		}
 	}
 
-	// Update fish (swimming)
+	// Update fish (swimming, with boids-style schooling)
+	if !ua.pausedLayers[LayerFish] {
+		ua.applyFlocking(deltaTime)
+	}
 	for i := range ua.fish {
+		if ua.pausedLayers[LayerFish] {
+			break
+		}
 		fish := &ua.fish[i]
 		fish.X += math.Cos(fish.Angle) * fish.Speed * deltaTime
 		fish.Y += math.Sin(fish.Angle) * fish.Speed * deltaTime * 0.3
@@ -383,81 +1229,144 @@ func (ua *UnderwaterAnimator) Update(deltaTime float64) error {
 	}
 
 	// Update gradient
-	ua.gradientPos += deltaTime * 0.005
-	if ua.gradientPos > 1 {
-		ua.gradientPos = 0
+	if !ua.pausedLayers[LayerGradient] {
+		ua.gradientPos += deltaTime * 0.005
+		if ua.gradientPos > 1 {
+			ua.gradientPos = 0
+		}
 	}
 
 	return nil
 }
 
-func (ua *UnderwaterAnimator) Render() string {
-	var bg strings.Builder
+// AnimationLayer identifies one independently pausable part of the scene.
+type AnimationLayer int
 
-	// Create gradient background
-	for y := 0; y < 30; y++ {
-		for x := 0; x < 100; x++ {
-			// Calculate gradient color
-			intensity := (math.Sin((float64(x)/10+ua.gradientPos)*math.Pi) + 1) / 2
-			depth := float64(y) / 30
+const (
+	LayerParticles AnimationLayer = iota
+	LayerStars
+	LayerPlanets
+	LayerOctopus
+	LayerFish
+	LayerGradient
+)
 
-			// Ocean gradient from deep blue to lighter blue
-			r := int(10 + depth*20 + intensity*10)
-			g := int(30 + depth*30 + intensity*20)
-			b := int(60 + depth*40 + intensity*30)
+// String returns the layer's display name.
+func (l AnimationLayer) String() string {
+	switch l {
+	case LayerParticles:
+		return "particles"
+	case LayerStars:
+		return "stars"
+	case LayerPlanets:
+		return "planets"
+	case LayerOctopus:
+		return "octopus"
+	case LayerFish:
+		return "fish"
+	case LayerGradient:
+		return "gradient"
+	default:
+		return "unknown"
+	}
+}
 
-			color := fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b)
-			bg.WriteString(color)
-			bg.WriteString(" ")
+// Scene dimensions used by the animator's coordinate space.
+const (
+	sceneWidth  = 100
+	sceneHeight = 30
+)
+
+// sceneCell is one character cell in the composited frame. An empty glyph
+// means only the gradient background shows through.
+type sceneCell struct {
+	glyph string
+	rgb   string // foreground as "r;g;b"
+}
+
+// placeSprite writes a (possibly multi-cell) sprite into the buffer at the
+// rounded position, clipping anything out of bounds. Later calls overwrite
+// earlier ones, establishing draw order.
+func placeSprite(cells [][]sceneCell, x, y float64, sprite, rgb string) {
+	row := int(math.Round(y))
+	if row < 0 || row >= len(cells) {
+		return
+	}
+	col := int(math.Round(x))
+	for _, r := range sprite {
+		if col >= 0 && col < len(cells[row]) {
+			cells[row][col] = sceneCell{glyph: string(r), rgb: rgb}
 		}
-		bg.WriteString("\x1b[0m\n")
+		col++
 	}
+}
 
-	// Render particles
-	for _, p := range ua.particles {
-		rgb := getRGBFromColor(p.Color)
-		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm•\x1b[0m", rgb))
+// compositeSprites lays every sprite into a sceneHeight×sceneWidth cell
+// buffer indexed by its real X/Y coordinates.
+func (ua *UnderwaterAnimator) compositeSprites() [][]sceneCell {
+	cells := make([][]sceneCell, sceneHeight)
+	for y := range cells {
+		cells[y] = make([]sceneCell, sceneWidth)
 	}
 
-	// Render stars
+	// Background layers first, foreground last so it wins overlaps
 	for _, star := range ua.stars {
 		brightness := int(star.Bright * 255)
-		size := int(star.Size)
-		if size == 0 {
-			size = 1
-		}
-
-		// Twinkling effect
 		if star.Bright > 0.8 {
-			bg.WriteString(fmt.Sprintf("\x1b[38;2;255;255;200m✦\x1b[0m"))
+			placeSprite(cells, star.X, star.Y, "✦", "255;255;200")
 		} else {
-			bg.WriteString(fmt.Sprintf("\x1b[38;2;%d;%d;%dm•\x1b[0m", brightness, brightness, brightness))
+			placeSprite(cells, star.X, star.Y, "•", fmt.Sprintf("%d;%d;%d", brightness, brightness, brightness))
 		}
 	}
 
-	// Render planets
 	for _, planet := range ua.planets {
-		rgb := getRGBFromHex(planet.Color)
-		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm●\x1b[0m", rgb))
+		placeSprite(cells, planet.X, planet.Y, "●", getRGBFromHex(planet.Color))
+	}
+
+	for _, p := range ua.particles {
+		placeSprite(cells, p.X, p.Y, "•", getRGBFromColor(p.Color))
 	}
 
-	// Render octopus
 	if ua.octopus != nil {
-		// Body
 		rgb := getRGBFromHex(ua.octopus.Color)
-		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm◉\x1b[0m", rgb))
-
-		// Tentacles
-		for range ua.octopus.Tentacles {
-			bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm~\x1b[0m", rgb))
+		for _, tentacle := range ua.octopus.Tentacles {
+			tx := ua.octopus.X + math.Cos(tentacle.Angle)*tentacle.Length
+			ty := ua.octopus.Y + math.Sin(tentacle.Angle)*tentacle.Length*0.5
+			placeSprite(cells, tx, ty, "~", rgb)
 		}
+		placeSprite(cells, ua.octopus.X, ua.octopus.Y, "◉", rgb)
 	}
 
-	// Render fish
 	for _, fish := range ua.fish {
-		// Fish body with wave motion
-		rgb := getRGBFromHex(fish.Color)
-		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm><>\x1b[0m", rgb))
+		placeSprite(cells, fish.X, fish.Y, "><>", getRGBFromHex(fish.Color))
+	}
+
+	return cells
+}
+
+func (ua *UnderwaterAnimator) Render() string {
+	cells := ua.compositeSprites()
+
+	var bg strings.Builder
+	for y := 0; y < sceneHeight; y++ {
+		for x := 0; x < sceneWidth; x++ {
+			// Calculate gradient color
+			intensity := (math.Sin((float64(x)/10+ua.gradientPos)*math.Pi) + 1) / 2
+			depth := float64(y) / sceneHeight
+
+			// Ocean gradient from deep blue to lighter blue
+			r := int(10 + depth*20 + intensity*10)
+			g := int(30 + depth*30 + intensity*20)
+			b := int(60 + depth*40 + intensity*30)
+
+			bg.WriteString(fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b))
+			if cell := cells[y][x]; cell.glyph != "" {
+				bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm%s", cell.rgb, cell.glyph))
+			} else {
+				bg.WriteString(" ")
+			}
+		}
+		bg.WriteString("\x1b[0m\n")
 	}
 
 	return bg.String()
@@ -471,37 +1380,286 @@ func (ua *UnderwaterAnimator) SetPaused(paused bool) {
 	ua.isPaused = paused
 }
 
+// maxParticles caps the particle slice so click bursts can't grow it
+// without bound.
+const maxParticles = 500
+
+// Emit spawns a burst of short-lived particles at (x, y) with outward
+// velocities. The burst ages out through the normal lifetime logic.
+func (ua *UnderwaterAnimator) Emit(x, y float64, count int) {
+	for i := 0; i < count; i++ {
+		if len(ua.particles) >= maxParticles {
+			return
+		}
+		angle := ua.rng.Float64() * math.Pi * 2
+		speed := 0.2 + ua.rng.Float64()*0.4
+		ua.particles = append(ua.particles, Particle{
+			X:           x,
+			Y:           y,
+			VX:          math.Cos(angle) * speed,
+			VY:          math.Sin(angle) * speed * 0.5,
+			Size:        1,
+			Color:       randomColor(ua.rng),
+			Opacity:     1,
+			Lifetime:    0,
+			MaxLifetime: 10 + ua.rng.Float64()*20,
+		})
+	}
+}
+
+// applyFlocking nudges each fish away from close neighbours (separation)
+// and gently toward the school's centre (cohesion). Fish counts are small,
+// so the O(n²) neighbour scan is fine.
+func (ua *UnderwaterAnimator) applyFlocking(deltaTime float64) {
+	if len(ua.fish) < 2 {
+		return
+	}
+
+	var centerX, centerY float64
+	for i := range ua.fish {
+		centerX += ua.fish[i].X
+		centerY += ua.fish[i].Y
+	}
+	centerX /= float64(len(ua.fish))
+	centerY /= float64(len(ua.fish))
+
+	for i := range ua.fish {
+		fish := &ua.fish[i]
+
+		// Separation: push directly away from any neighbour inside the radius
+		for j := range ua.fish {
+			if i == j {
+				continue
+			}
+			dx := fish.X - ua.fish[j].X
+			dy := fish.Y - ua.fish[j].Y
+			dist := math.Hypot(dx, dy)
+			if dist >= ua.separationRadius {
+				continue
+			}
+			if dist < 1e-6 {
+				// Perfectly overlapping: pick a deterministic split direction
+				dx, dy = math.Cos(float64(i)), math.Sin(float64(i))
+				dist = 1
+			}
+			push := (ua.separationRadius - dist) / ua.separationRadius
+			fish.X += dx / dist * push * deltaTime
+			fish.Y += dy / dist * push * deltaTime * 0.3
+		}
+
+		// Cohesion: drift loosely toward the school's centre
+		fish.X += (centerX - fish.X) * ua.cohesionWeight * deltaTime
+		fish.Y += (centerY - fish.Y) * ua.cohesionWeight * deltaTime * 0.3
+	}
+}
+
+// SetLayerPaused freezes or resumes a single layer; the global SetPaused
+// remains the master switch.
+func (ua *UnderwaterAnimator) SetLayerPaused(layer AnimationLayer, paused bool) {
+	ua.pausedLayers[layer] = paused
+}
+
+// IsLayerPaused reports whether the given layer is individually frozen.
+func (ua *UnderwaterAnimator) IsLayerPaused(layer AnimationLayer) bool {
+	return ua.pausedLayers[layer]
+}
+
 func (ua *UnderwaterAnimator) SetSpeed(speed float64) {
 	ua.speed = speed
 }
 
-// ==================== UI COMPONENTS ====================
+// ==================== ANIMATION THEMES ====================
+
+// animationThemes lists the selectable themes in cycle order.
+var animationThemes = []string{"underwater", "space", "rain", "matrix"}
+
+// NewAnimator builds an animator for the named theme. Unknown names fall
+// back to the default underwater theme.
+func NewAnimator(theme string) Animator {
+	switch theme {
+	case "space":
+		return NewSpaceAnimator()
+	case "rain":
+		return NewRainAnimator()
+	case "matrix":
+		return NewMatrixAnimator()
+	default:
+		return NewUnderwaterAnimator()
+	}
+}
+
+// SpaceAnimator renders a starfield with orbiting planets only.
+type SpaceAnimator struct {
+	stars    []Star
+	planets  []Planet
+	isPaused bool
+}
 
-type Pane struct {
-	ID       string  `json:"id"`
-	Title    string  `json:"title"`
-	Content  string  `json:"content"`
-	Width    int     `json:"width"`
-	Height   int     `json:"height"`
-	X        int     `json:"x"`
-	Y        int     `json:"y"`
-	IsActive bool    `json:"is_active"`
-	Opacity  float64 `json:"opacity"`
+func NewSpaceAnimator() *SpaceAnimator {
+	stars := make([]Star, 120)
+	for i := range stars {
+		stars[i] = Star{
+			X:       rand.Float64() * 100,
+			Y:       rand.Float64() * 30,
+			Size:    rand.Float64()*1.5 + 0.5,
+			Bright:  rand.Float64(),
+			Twinkle: rand.Float64() * math.Pi * 2,
+		}
+	}
+
+	planets := []Planet{
+		{X: 25, Y: 8, Orbit: 18, Size: 2, Color: "#FF6B6B", Speed: 0.02, Angle: 0},
+		{X: 70, Y: 18, Orbit: 10, Size: 1.5, Color: "#4ECDC4", Speed: 0.03, Angle: math.Pi},
+	}
+
+	return &SpaceAnimator{stars: stars, planets: planets}
+}
+
+func (sa *SpaceAnimator) Update(deltaTime float64) error {
+	if sa.isPaused {
+		return nil
+	}
+	for i := range sa.stars {
+		star := &sa.stars[i]
+		star.Twinkle += deltaTime * 0.1
+		star.Bright = 0.5 + 0.5*math.Sin(star.Twinkle)
+	}
+	for i := range sa.planets {
+		planet := &sa.planets[i]
+		planet.Angle += planet.Speed * deltaTime
+		planet.X = 50 + math.Cos(planet.Angle)*planet.Orbit
+		planet.Y = 15 + math.Sin(planet.Angle)*planet.Orbit*0.5
+	}
+	return nil
+}
+
+func (sa *SpaceAnimator) Render() string {
+	var bg strings.Builder
+	for _, star := range sa.stars {
+		brightness := int(star.Bright * 255)
+		if star.Bright > 0.8 {
+			bg.WriteString("\x1b[38;2;255;255;200m✦\x1b[0m")
+		} else {
+			bg.WriteString(fmt.Sprintf("\x1b[38;2;%d;%d;%dm•\x1b[0m", brightness, brightness, brightness))
+		}
+	}
+	for _, planet := range sa.planets {
+		rgb := getRGBFromHex(planet.Color)
+		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm●\x1b[0m", rgb))
+	}
+	return bg.String()
+}
+
+func (sa *SpaceAnimator) IsPaused() bool        { return sa.isPaused }
+func (sa *SpaceAnimator) SetPaused(paused bool) { sa.isPaused = paused }
+
+// RainAnimator renders falling raindrops.
+type RainAnimator struct {
+	drops    []Particle
+	isPaused bool
+}
+
+func NewRainAnimator() *RainAnimator {
+	drops := make([]Particle, 80)
+	for i := range drops {
+		drops[i] = Particle{
+			X:       rand.Float64() * 100,
+			Y:       rand.Float64() * 30,
+			VY:      0.3 + rand.Float64()*0.4,
+			Size:    1,
+			Color:   "#87CEEB",
+			Opacity: 0.5 + rand.Float64()*0.5,
+		}
+	}
+	return &RainAnimator{drops: drops}
+}
+
+func (ra *RainAnimator) Update(deltaTime float64) error {
+	if ra.isPaused {
+		return nil
+	}
+	for i := range ra.drops {
+		drop := &ra.drops[i]
+		drop.Y += drop.VY * deltaTime
+		if drop.Y > 30 {
+			drop.Y = 0
+			drop.X = rand.Float64() * 100
+		}
+	}
+	return nil
+}
+
+func (ra *RainAnimator) Render() string {
+	var bg strings.Builder
+	for _, drop := range ra.drops {
+		rgb := getRGBFromColor(drop.Color)
+		bg.WriteString(fmt.Sprintf("\x1b[38;2;%sm│\x1b[0m", rgb))
+	}
+	return bg.String()
+}
+
+func (ra *RainAnimator) IsPaused() bool        { return ra.isPaused }
+func (ra *RainAnimator) SetPaused(paused bool) { ra.isPaused = paused }
+
+// MatrixAnimator renders falling glyph columns.
+type MatrixAnimator struct {
+	columns  []float64
+	isPaused bool
 }
 
+func NewMatrixAnimator() *MatrixAnimator {
+	columns := make([]float64, 100)
+	for i := range columns {
+		columns[i] = rand.Float64() * 30
+	}
+	return &MatrixAnimator{columns: columns}
+}
+
+func (ma *MatrixAnimator) Update(deltaTime float64) error {
+	if ma.isPaused {
+		return nil
+	}
+	for i := range ma.columns {
+		ma.columns[i] += (0.2 + rand.Float64()*0.3) * deltaTime
+		if ma.columns[i] > 30 {
+			ma.columns[i] = 0
+		}
+	}
+	return nil
+}
+
+func (ma *MatrixAnimator) Render() string {
+	glyphs := []rune("ｱｲｳｴｵｶｷｸｹｺ0123456789")
+	var bg strings.Builder
+	for _, head := range ma.columns {
+		glyph := glyphs[int(head*7)%len(glyphs)]
+		bg.WriteString(fmt.Sprintf("\x1b[38;2;0;255;70m%c\x1b[0m", glyph))
+	}
+	return bg.String()
+}
+
+func (ma *MatrixAnimator) IsPaused() bool        { return ma.isPaused }
+func (ma *MatrixAnimator) SetPaused(paused bool) { ma.isPaused = paused }
+
+// ==================== UI COMPONENTS ====================
+
 // ==================== MAIN MODEL ====================
 
-type Model struct {
+type AITUIModel struct {
 	width   int
 	height  int
 	focused bool
 
 	// Animation system
-	animator Animator
+	animator   Animator
+	themeIndex int
 
 	// AI logging system
 	logger Logger
 
+	// User keybindings
+	keys *KeyMap
+
 	// UI panes
 	panes      []Pane
 	activePane int
@@ -512,9 +1670,13 @@ type Model struct {
 	// Current conversation
 	currentSession *ConversationSession
 	isRecording    bool
+
+	// Frame pacing and session capture
+	ticker   *AdaptiveTicker
+	recorder *Recorder
 }
 
-func initialModel() Model {
+func initialAITUIModel() AITUIModel {
 	// Create data directory
 	dataDir, err := os.UserHomeDir()
 	if err != nil {
@@ -524,9 +1686,19 @@ func initialModel() Model {
 	os.MkdirAll(dataDir, 0755)
 
 	// Initialize systems
-	animator := NewUnderwaterAnimator()
+	cfg, err := loadAnimatorConfig()
+	if err != nil {
+		log.Printf("Ignoring invalid animation config: %v", err)
+		cfg = defaultAnimatorConfig()
+	}
+	animator := NewUnderwaterAnimatorFromConfig(cfg)
 	logger := NewFileLogger(dataDir)
 
+	keys := LoadKeyMap(keyMapPath())
+	for _, w := range keys.Warnings() {
+		log.Printf("Key config: %s", w)
+	}
+
 	// Create UI panes with responsive sizing
 	panes := []Pane{
 		{
@@ -562,40 +1734,56 @@ func initialModel() Model {
 		},
 	}
 
-	return Model{
+	return AITUIModel{
 		width:          100,
 		height:         40,
 		focused:        true,
 		animator:       animator,
 		logger:         logger,
+		keys:           keys,
 		panes:          panes,
 		activePane:     0,
 		startTime:      time.Now(),
 		currentSession: nil,
 		isRecording:    false,
+		ticker:         NewAdaptiveTicker(60),
+		recorder:       NewRecorder(100, 40),
 	}
 }
 
 // ==================== UPDATE METHODS ====================
 
-func (m Model) Init() tea.Cmd {
-	return tea.Tick(time.Second/60, func(t time.Time) tea.Msg {
+func (m AITUIModel) Init() tea.Cmd {
+	return m.tickCmd()
+}
+
+// tickCmd schedules the next animation frame at the ticker's current interval.
+func (m AITUIModel) tickCmd() tea.Cmd {
+	return tea.Tick(m.ticker.Interval(), func(t time.Time) tea.Msg {
 		return t // Return time.Time directly
 	})
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m AITUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = int(msg.Width), int(msg.Height)
 		return m, nil
 
 	case time.Time:
-		// Update animation
+		// Update animation, timing the frame so the ticker can adapt
+		start := time.Now()
 		if err := m.animator.Update(1.0); err != nil {
 			log.Printf("Animation update error: %v", err)
 		}
-		return m, nil
+		frame := m.animator.Render()
+		if m.recorder.IsRecording() {
+			if err := m.recorder.WriteFrame(frame); err != nil {
+				log.Printf("Recording error: %v", err)
+			}
+		}
+		m.ticker.RecordFrame(time.Since(start))
+		return m, m.tickCmd()
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
@@ -608,24 +1796,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyCtrlC, tea.KeyEsc:
-		// Log session end
-		if m.currentSession != nil {
-			endTime := time.Now()
-			m.currentSession.EndTime = &endTime
-			m.logger.LogConversation(*m.currentSession)
-		}
-		return m, tea.Quit
+func (m *AITUIModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// User keybindings (~/.ai-tui-keys.json) resolve first, so remapping
+	// an action really moves it; keys with no binding fall through to the
+	// fixed shortcuts below.
+	keys := m.keys
+	if keys == nil {
+		keys = DefaultKeyMap()
+	}
+	switch keys.resolveAction(msg) {
+	case ActionQuit:
+		return m.endSession()
 
-	case tea.KeyTab:
+	case ActionSwitchPane:
 		m.activePane = (m.activePane + 1) % len(m.panes)
 		for i := range m.panes {
 			m.panes[i].IsActive = (i == m.activePane)
 		}
 		return m, nil
 
+	case ActionRefresh:
+		// Reset animation (rebuilds the model, reloading the key config)
+		*m = initialAITUIModel()
+		return m, tea.Printf("Animation reset")
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.endSession()
+
 	case tea.KeyRunes:
 		if len(msg.Runes) > 0 {
 			switch msg.Runes[0] {
@@ -650,10 +1849,33 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					animator.SetSpeed(animator.speed * 0.7)
 				}
 				return m, tea.Printf("Speed decreased")
-			case 'r':
-				// Reset animation
-				*m = initialModel()
-				return m, tea.Printf("Animation reset")
+			case '1', '2', '3', '4', '5', '6':
+				// Toggle an individual animation layer
+				if animator, ok := m.animator.(*UnderwaterAnimator); ok {
+					layer := AnimationLayer(msg.Runes[0] - '1')
+					animator.SetLayerPaused(layer, !animator.IsLayerPaused(layer))
+					return m, tea.Printf("Layer %s %s", layer,
+						map[bool]string{true: "paused", false: "resumed"}[animator.IsLayerPaused(layer)])
+				}
+				return m, nil
+			case 'R':
+				// Toggle asciicast capture of the animation
+				if m.recorder.IsRecording() {
+					if err := m.recorder.StopRecording(); err != nil {
+						return m, tea.Printf("Recording error: %v", err)
+					}
+					return m, tea.Printf("Recording stopped")
+				}
+				path := filepath.Join(os.TempDir(), fmt.Sprintf("ai-tui-%d.cast", time.Now().Unix()))
+				if err := m.recorder.StartRecording(path); err != nil {
+					return m, tea.Printf("Recording error: %v", err)
+				}
+				return m, tea.Printf("Recording to %s", path)
+			case 't':
+				// Cycle animation theme
+				m.themeIndex = (m.themeIndex + 1) % len(animationThemes)
+				m.animator = NewAnimator(animationThemes[m.themeIndex])
+				return m, tea.Printf("Theme: %s", animationThemes[m.themeIndex])
 			}
 		}
 	}
@@ -661,7 +1883,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+func (m *AITUIModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if msg.Type == tea.MouseLeft {
 		x, y := msg.X, msg.Y
 
@@ -676,12 +1898,27 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
+
+		// Click landed on the background: burst particles at the cursor
+		if animator, ok := m.animator.(*UnderwaterAnimator); ok {
+			animator.Emit(float64(x), float64(y), 12)
+		}
 	}
 
 	return m, nil
 }
 
-func (m *Model) toggleRecording() (tea.Model, tea.Cmd) {
+// endSession logs the end of any active conversation and quits.
+func (m *AITUIModel) endSession() (tea.Model, tea.Cmd) {
+	if m.currentSession != nil {
+		endTime := time.Now()
+		m.currentSession.EndTime = &endTime
+		m.logger.LogConversation(*m.currentSession)
+	}
+	return m, tea.Quit
+}
+
+func (m *AITUIModel) toggleRecording() (tea.Model, tea.Cmd) {
 	m.isRecording = !m.isRecording
 
 	if m.isRecording {
@@ -730,7 +1967,7 @@ func (m *Model) toggleRecording() (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m *Model) clearConversation() (tea.Model, tea.Cmd) {
+func (m *AITUIModel) clearConversation() (tea.Model, tea.Cmd) {
 	m.currentSession = nil
 	m.isRecording = false
 
@@ -746,7 +1983,7 @@ func (m *Model) clearConversation() (tea.Model, tea.Cmd) {
 	return m, tea.Printf("🗑️ Conversation cleared")
 }
 
-func (m *Model) showMonitoring() (tea.Model, tea.Cmd) {
+func (m *AITUIModel) showMonitoring() (tea.Model, tea.Cmd) {
 	// This would open a detailed monitoring view
 	event := SystemEvent{
 		ID:        generateID(),
@@ -762,9 +1999,14 @@ func (m *Model) showMonitoring() (tea.Model, tea.Cmd) {
 
 // ==================== RENDER METHODS ====================
 
-func (m Model) View() string {
-	if m.width < 80 || m.height < 40 {
-		return "Terminal too small! Please resize to at least 80x40"
+// minSize implements minSizer for this layout.
+func (m AITUIModel) minSize() (int, int) {
+	return 80, 40
+}
+
+func (m AITUIModel) View() string {
+	if terminalTooSmall(m, m.width, m.height) {
+		return renderTooSmallView(m, m.width, m.height)
 	}
 
 	var content strings.Builder
@@ -783,7 +2025,7 @@ func (m Model) View() string {
 			Bold(true).
 			Render("🔴 REC")
 
-		content.WriteString(lipgloss.Place(m.height, m.width,
+		content.WriteString(lipgloss.Place(m.width, m.height,
 			lipgloss.Right, lipgloss.Top,
 			recordingIndicator))
 	}
@@ -791,7 +2033,7 @@ func (m Model) View() string {
 	return content.String()
 }
 
-func (m Model) renderPanes() string {
+func (m AITUIModel) renderPanes() string {
 	var content strings.Builder
 
 	for _, pane := range m.panes {
@@ -818,7 +2060,7 @@ func (m Model) renderPanes() string {
 				lipgloss.NewStyle().Foreground(lipgloss.Color("#86E1FC")).Bold(true).Render(pane.Title),
 				paneContent))
 
-			content.WriteString(lipgloss.Place(m.height, m.width,
+			content.WriteString(lipgloss.Place(m.width, m.height,
 				lipgloss.Left, lipgloss.Top,
 				renderedPane))
 		}
@@ -827,7 +2069,7 @@ func (m Model) renderPanes() string {
 	return content.String()
 }
 
-func (m Model) formatConversationDisplay() string {
+func (m AITUIModel) formatConversationDisplay() string {
 	if m.currentSession == nil {
 		return "No active conversation"
 	}
@@ -842,14 +2084,15 @@ func (m Model) formatConversationDisplay() string {
 		map[bool]string{true: "Recording", false: "Active"}[m.isRecording])
 }
 
-func (m Model) formatMonitoringDisplay() string {
-	return fmt.Sprintf("System Status: %s\nAnimation: %s\nSpeed: %.1fx",
+func (m AITUIModel) formatMonitoringDisplay() string {
+	return fmt.Sprintf("System Status: %s\nAnimation: %s\nSpeed: %.1fx\nFPS: %.1f",
 		map[bool]string{true: "Active", false: "Paused"}[m.animator.IsPaused()],
 		map[bool]string{true: "Running", false: "Paused"}[!m.animator.IsPaused()],
-		m.getAnimationSpeed())
+		m.getAnimationSpeed(),
+		m.ticker.EffectiveFPS())
 }
 
-func (m Model) getAnimationSpeed() float64 {
+func (m AITUIModel) getAnimationSpeed() float64 {
 	if animator, ok := m.animator.(*UnderwaterAnimator); ok {
 		return animator.speed
 	}
@@ -858,10 +2101,6 @@ func (m Model) getAnimationSpeed() float64 {
 
 // ==================== HELPERS ====================
 
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
-}
-
 func getRandomColor() string {
 	colors := []string{"#FF6B6B", "#4ECDC4", "#45B7D1", "#96CEB4", "#FFEAA7", "#DDA0DD", "#98D8C8"}
 	return colors[rand.Intn(len(colors))]
@@ -872,6 +2111,18 @@ func getRandomFishColor() string {
 	return colors[rand.Intn(len(colors))]
 }
 
+// randomColor and randomFishColor mirror the helpers above but draw from a
+// caller-owned RNG so seeded animators stay deterministic.
+func randomColor(rng *rand.Rand) string {
+	colors := []string{"#FF6B6B", "#4ECDC4", "#45B7D1", "#96CEB4", "#FFEAA7", "#DDA0DD", "#98D8C8"}
+	return colors[rng.Intn(len(colors))]
+}
+
+func randomFishColor(rng *rand.Rand) string {
+	colors := []string{"#FF69B4", "#FFB347", "#87CEEB", "#98FB98", "#DDA0DD", "#F0E68C"}
+	return colors[rng.Intn(len(colors))]
+}
+
 func getRGBFromHex(hex string) string {
 	if len(hex) != 7 || hex[0] != '#' {
 		return "255;255;255"
@@ -930,10 +2181,11 @@ func hexToByte(hex string) byte {
 
 // ==================== MAIN ====================
 
-func main() {
-	// Try to run the TUI with fallback to demo mode
+// RunAI starts the AI TUI, falling back to the static demo when no
+// terminal is available.
+func RunAI() error {
 	p := tea.NewProgram(
-		initialModel(),
+		initialAITUIModel(),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -942,6 +2194,7 @@ func main() {
 		// Fallback to demo mode if TUI fails
 		runDemoMode()
 	}
+	return nil
 }
 
 func isInteractiveTerminal() bool {
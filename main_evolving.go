@@ -20,64 +20,9 @@ type AnimationState struct {
 	Direction int // 1 or -1
 }
 
-type Particle struct {
-	X           float64
-	Y           float64
-	VX          float64
-	VY          float64
-	Size        float64
-	Color       string
-	Opacity     float64
-	Lifetime    float64
-	MaxLifetime float64
-}
-
-type Star struct {
-	X       float64
-	Y       float64
-	Size    float64
-	Bright  float64
-	Twinkle float64
-}
-
-type Planet struct {
-	X     float64
-	Y     float64
-	Orbit float64
-	Size  float64
-	Color string
-	Speed float64
-	Angle float64
-}
-
-type Octopus struct {
-	X         float64
-	Y         float64
-	Angle     float64
-	Tentacles []Tentacle
-	Color     string
-	Speed     float64
-}
-
-type Tentacle struct {
-	Angle  float64
-	Length float64
-	Wave   float64
-}
-
-type Fish struct {
-	X         float64
-	Y         float64
-	Angle     float64
-	Speed     float64
-	Size      float64
-	Color     string
-	WavePhase float64
-}
-
 // ==================== MODEL ====================
 
-type Model struct {
+type EvolvingModel struct {
 	width   int
 	height  int
 	focused bool
@@ -101,21 +46,9 @@ type Model struct {
 	startTime time.Time
 }
 
-type Pane struct {
-	ID       string
-	Title    string
-	Content  string
-	Width    int
-	Height   int
-	X        int
-	Y        int
-	IsActive bool
-	Opacity  float64
-}
-
 // ==================== INITIALIZE ====================
 
-func initialModel() Model {
+func initialEvolvingModel() EvolvingModel {
 	rand.Seed(time.Now().UnixNano())
 
 	// Create initial particles
@@ -213,7 +146,7 @@ func initialModel() Model {
 			Title: "📊 System Stats",
 			Content: fmt.Sprintf("Particles: %d\nStars: %d\nPlanets: %d\nFish: %d\nOctopus: %s\n\nRuntime: %v",
 				len(particles), len(stars), len(planets), len(fish), "Active",
-				time.Since(m.startTime).Round(time.Second)),
+				time.Duration(0)),
 			Width:    30,
 			Height:   10,
 			X:        75,
@@ -234,7 +167,7 @@ func initialModel() Model {
 		},
 	}
 
-	return Model{
+	return EvolvingModel{
 		width:       100,
 		height:      40,
 		focused:     true,
@@ -253,20 +186,27 @@ func initialModel() Model {
 
 // ==================== UPDATE ====================
 
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		tea.WindowSize(),
-		tea.Tick(time.Second/60), // 60 FPS
-	)
+// evolveTickMsg carries the frame delta, in frames, for the animation
+// update. updateAnimation re-arms the tick to keep the loop running.
+type evolveTickMsg float64
+
+func evolveTickCmd() tea.Cmd {
+	return tea.Tick(time.Second/60, func(time.Time) tea.Msg { // 60 FPS
+		return evolveTickMsg(1)
+	})
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m EvolvingModel) Init() tea.Cmd {
+	return evolveTickCmd()
+}
+
+func (m EvolvingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = int(msg.Width), int(msg.Height)
 		return m, nil
 
-	case tea.TickMsg:
+	case evolveTickMsg:
 		return m.updateAnimation(msg)
 
 	case tea.KeyMsg:
@@ -280,7 +220,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m *Model) updateAnimation(msg tea.TickMsg) (tea.Model, tea.Cmd) {
+func (m EvolvingModel) updateAnimation(msg evolveTickMsg) (tea.Model, tea.Cmd) {
 	m.anim.Time += float64(msg) * m.anim.Speed
 
 	// Update particles
@@ -369,40 +309,39 @@ func (m *Model) updateAnimation(msg tea.TickMsg) (tea.Model, tea.Cmd) {
 		m.gradientPos = 0
 	}
 
-	return m, nil
+	return m, evolveTickCmd()
 }
 
-func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyCtrlC, tea.KeyEsc, tea.Key('q'):
+func (m EvolvingModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc", "q":
 		return m, tea.Quit
 
-	case tea.KeyTab:
+	case "tab":
 		m.activePane = (m.activePane + 1) % len(m.panes)
 		for i := range m.panes {
 			m.panes[i].IsActive = (i == m.activePane)
 		}
 		return m, nil
 
-	case tea.Key(' '):
+	case " ":
 		m.anim.Speed *= 0.5
 		if m.anim.Speed < 0.1 {
 			m.anim.Speed = 0.1
 		}
 		return m, nil
 
-	case tea.Key('+'):
+	case "+":
 		m.anim.Speed *= 2
 		if m.anim.Speed > 5 {
 			m.anim.Speed = 5
 		}
 		return m, nil
 
-	case tea.Key('r'):
-		*m = initialModel()
-		return m, nil
+	case "r":
+		return initialEvolvingModel(), nil
 
-	case tea.Key('p'):
+	case "p":
 		m.focused = !m.focused
 		return m, nil
 	}
@@ -410,7 +349,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+func (m EvolvingModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if msg.Type == tea.MouseLeft {
 		x, y := msg.X, msg.Y
 
@@ -432,9 +371,14 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 // ==================== RENDER ====================
 
-func (m Model) View() string {
-	if m.width < 80 || m.height < 40 {
-		return "Terminal too small! Please resize to at least 80x40"
+// minSize implements minSizer for this layout.
+func (m EvolvingModel) minSize() (int, int) {
+	return 80, 40
+}
+
+func (m EvolvingModel) View() string {
+	if terminalTooSmall(m, m.width, m.height) {
+		return renderTooSmallView(m, m.width, m.height)
 	}
 
 	var content strings.Builder
@@ -448,7 +392,7 @@ func (m Model) View() string {
 	return content.String()
 }
 
-func (m Model) renderBackground() string {
+func (m EvolvingModel) renderBackground() string {
 	var bg strings.Builder
 
 	// Create gradient background
@@ -494,7 +438,7 @@ func (m Model) renderBackground() string {
 	return bg.String()
 }
 
-func (m Model) renderPanes() string {
+func (m EvolvingModel) renderPanes() string {
 	var content strings.Builder
 
 	for _, pane := range m.panes {
@@ -505,7 +449,6 @@ func (m Model) renderPanes() string {
 				Background(lipgloss.Color("#1a1a2e")).
 				Foreground(lipgloss.Color("#ffffff")).
 				Bold(true).
-				Opacity(float64(pane.Opacity)).
 				Padding(0, 1).
 				Width(pane.Width).
 				Height(pane.Height)
@@ -515,7 +458,7 @@ func (m Model) renderPanes() string {
 				pane.Content)
 
 			renderedPane := style.Render(paneContent)
-			content.WriteString(lipgloss.Place(m.height, m.width,
+			content.WriteString(lipgloss.Place(m.width, m.height,
 				lipgloss.Left, lipgloss.Top,
 				renderedPane))
 		}
@@ -528,7 +471,7 @@ func (m Model) renderPanes() string {
 		60.0*m.anim.Speed,
 		len(m.particles))
 
-	content.WriteString(lipgloss.Place(m.height, m.width,
+	content.WriteString(lipgloss.Place(m.width, m.height,
 		lipgloss.Left, lipgloss.Bottom,
 		lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#ffffff")).
@@ -539,28 +482,18 @@ func (m Model) renderPanes() string {
 	return content.String()
 }
 
-// ==================== HELPERS ====================
-
-func getRandomColor() string {
-	colors := []string{"#FF6B6B", "#4ECDC4", "#45B7D1", "#96CEB4", "#FFEAA7", "#DDA0DD", "#98D8C8"}
-	return colors[rand.Intn(len(colors))]
-}
-
-func getRandomFishColor() string {
-	colors := []string{"#FF69B4", "#FFB347", "#87CEEB", "#98FB98", "#DDA0DD", "#F0E68C"}
-	return colors[rand.Intn(len(colors))]
-}
-
 // ==================== MAIN ====================
 
-func main() {
+// RunEvolving starts the evolving-background TUI demo.
+func RunEvolving() error {
 	p := tea.NewProgram(
-		initialModel(),
+		initialEvolvingModel(),
 		tea.WithAltScreen(),
 		tea.WithMouseAllMotion(),
 	)
 
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v", err)
+		return fmt.Errorf("error starting application: %w", err)
 	}
+	return nil
 }
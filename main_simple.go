@@ -8,25 +8,14 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-type Model struct {
+type SimpleModel struct {
 	panes      []Pane
 	activePane int
 	width      int
 	height     int
 }
 
-type Pane struct {
-	ID       string
-	Title    string
-	Content  string
-	Width    int
-	Height   int
-	X        int
-	Y        int
-	IsActive bool
-}
-
-func initialModel() Model {
+func initialSimpleModel() SimpleModel {
 	panes := []Pane{
 		{
 			ID:       "main",
@@ -60,7 +49,7 @@ func initialModel() Model {
 		},
 	}
 
-	return Model{
+	return SimpleModel{
 		panes:      panes,
 		activePane: 0,
 		width:      100,
@@ -68,11 +57,11 @@ func initialModel() Model {
 	}
 }
 
-func (m Model) Init() tea.Cmd {
+func (m SimpleModel) Init() tea.Cmd {
 	return nil
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m SimpleModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = int(msg.Width), int(msg.Height)
@@ -100,9 +89,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) View() string {
-	if m.width < 80 || m.height < 24 {
-		return "Terminal too small! Please resize to at least 80x24"
+// minSize implements minSizer for this layout.
+func (m SimpleModel) minSize() (int, int) {
+	return 80, 24
+}
+
+func (m SimpleModel) View() string {
+	if terminalTooSmall(m, m.width, m.height) {
+		return renderTooSmallView(m, m.width, m.height)
 	}
 
 	var content strings.Builder
@@ -160,20 +154,15 @@ func (m Model) View() string {
 	return content.String()
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-func main() {
+// RunSimple starts the minimal pane-layout demo.
+func RunSimple() error {
 	p := tea.NewProgram(
-		initialModel(),
+		initialSimpleModel(),
 		tea.WithAltScreen(),
 	)
 
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v", err)
+		return fmt.Errorf("error starting application: %w", err)
 	}
+	return nil
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ==================== MINIMUM SIZE ====================
+
+// minSizer is implemented by top-level models that need a minimum
+// terminal size to lay out their panes. Views consult it instead of
+// hardcoding dimensions so the threshold lives next to the layout that
+// requires it.
+type minSizer interface {
+	minSize() (width, height int)
+}
+
+// terminalTooSmall reports whether the current terminal is below the
+// model's minimum usable size.
+func terminalTooSmall(m minSizer, width, height int) bool {
+	minW, minH := m.minSize()
+	return width < minW || height < minH
+}
+
+// renderTooSmallView renders a centered notice showing the current and
+// required dimensions. It re-renders on every resize, so the numbers
+// track the terminal live until the window is large enough.
+func renderTooSmallView(m minSizer, width, height int) string {
+	minW, minH := m.minSize()
+	msg := fmt.Sprintf("Terminal too small!\nCurrent: %dx%d  Required: %dx%d\nPlease resize the window.", width, height, minW, minH)
+	if width > 0 && height > 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, msg)
+	}
+	return msg
+}
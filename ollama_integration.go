@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ==================== OLLAMA CLIENT ====================
+
+// OllamaConfig points the client at a local Ollama server.
+type OllamaConfig struct {
+	BaseURL string `json:"base_url"`
+	Model   string `json:"model"`
+}
+
+// OllamaClient talks to a local Ollama server's chat API.
+type OllamaClient struct {
+	config     OllamaConfig
+	httpClient *http.Client
+}
+
+// NewOllamaClient builds a client with the standard local endpoint.
+func NewOllamaClient(config OllamaConfig) *OllamaClient {
+	if config.BaseURL == "" {
+		config.BaseURL = "http://localhost:11434"
+	}
+	return &OllamaClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// ollamaChatRequest is the wire shape of an /api/chat request.
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+// ollamaChatChunk is one JSON line of an /api/chat response. Ollama
+// streams these by default, with the final line carrying done=true.
+type ollamaChatChunk struct {
+	Message ChatMessage `json:"message"`
+	Done    bool        `json:"done"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// ollamaTags is the wire shape of the /api/tags model listing.
+type ollamaTags struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// chat posts a chat request and invokes onChunk for every JSON line.
+func (c *OllamaClient) chat(ctx context.Context, model string, messages []ChatMessage, stream bool, onChunk func(ollamaChatChunk) error) error {
+	if model == "" {
+		model = c.config.Model
+	}
+	payload, err := json.Marshal(ollamaChatRequest{Model: model, Messages: messages, Stream: stream})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk ollamaChatChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return fmt.Errorf("failed to parse ollama response line: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+		if err := onChunk(chunk); err != nil {
+			return err
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	return scanner.Err()
+}
+
+// SendMessage sends a chat request and returns the full reply text.
+func (c *OllamaClient) SendMessage(ctx context.Context, model string, messages []ChatMessage) (string, error) {
+	var reply bytes.Buffer
+	err := c.chat(ctx, model, messages, false, func(chunk ollamaChatChunk) error {
+		reply.WriteString(chunk.Message.Content)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return reply.String(), nil
+}
+
+// SendMessageStream streams the reply, invoking onToken per content chunk.
+func (c *OllamaClient) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (string, error) {
+	var reply bytes.Buffer
+	err := c.chat(ctx, model, messages, true, func(chunk ollamaChatChunk) error {
+		if chunk.Message.Content != "" {
+			reply.WriteString(chunk.Message.Content)
+			onToken(chunk.Message.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return reply.String(), nil
+}
+
+// GetModels lists the locally available model names.
+func (c *OllamaClient) GetModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var parsed ollamaTags
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse tags: %w", err)
+	}
+	models := make([]string, 0, len(parsed.Models))
+	for _, m := range parsed.Models {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
+
+// ==================== OLLAMA PROVIDER ====================
+
+// OllamaProvider adapts the client to the agent Provider contract.
+type OllamaProvider struct {
+	client *OllamaClient
+}
+
+// NewOllamaProvider wraps a client for agent registration.
+func NewOllamaProvider(client *OllamaClient) *OllamaProvider {
+	return &OllamaProvider{client: client}
+}
+
+// ID implements Provider.
+func (p *OllamaProvider) ID() string { return "ollama" }
+
+// Name implements Provider.
+func (p *OllamaProvider) Name() string { return "Ollama" }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ollamaChatHandler mimics Ollama's JSON-lines chat endpoint.
+func ollamaChatHandler(t *testing.T, tokens []string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Stream {
+			for _, tok := range tokens {
+				fmt.Fprintf(w, `{"message":{"role":"assistant","content":%q},"done":false}`+"\n", tok)
+			}
+			fmt.Fprintln(w, `{"message":{"role":"assistant","content":""},"done":true}`)
+			return
+		}
+		fmt.Fprintf(w, `{"message":{"role":"assistant","content":%q},"done":true}`+"\n", strings.Join(tokens, ""))
+	}
+}
+
+func TestOllamaSendMessage(t *testing.T) {
+	srv := httptest.NewServer(ollamaChatHandler(t, []string{"hello ", "world"}))
+	defer srv.Close()
+
+	client := NewOllamaClient(OllamaConfig{BaseURL: srv.URL, Model: "llama3"})
+	got, err := client.SendMessage(context.Background(), "", []ChatMessage{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if got != "hello world" {
+		t.Errorf("reply = %q, want %q", got, "hello world")
+	}
+}
+
+func TestOllamaSendMessageStream(t *testing.T) {
+	srv := httptest.NewServer(ollamaChatHandler(t, []string{"a", "b", "c"}))
+	defer srv.Close()
+
+	client := NewOllamaClient(OllamaConfig{BaseURL: srv.URL})
+	var tokens []string
+	got, err := client.SendMessageStream(context.Background(), "llama3", nil, func(tok string) {
+		tokens = append(tokens, tok)
+	})
+	if err != nil {
+		t.Fatalf("SendMessageStream: %v", err)
+	}
+	if got != "abc" {
+		t.Errorf("full reply = %q, want abc", got)
+	}
+	if len(tokens) != 3 {
+		t.Errorf("received %d tokens, want 3 (%v)", len(tokens), tokens)
+	}
+}
+
+func TestOllamaGetModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"models":[{"name":"llama3:8b"},{"name":"mistral:7b"}]}`)
+	}))
+	defer srv.Close()
+
+	client := NewOllamaClient(OllamaConfig{BaseURL: srv.URL})
+	models, err := client.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	if len(models) != 2 || models[0] != "llama3:8b" {
+		t.Errorf("models = %v", models)
+	}
+}
+
+func TestOllamaErrorLine(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"error":"model not found"}`)
+	}))
+	defer srv.Close()
+
+	client := NewOllamaClient(OllamaConfig{BaseURL: srv.URL})
+	if _, err := client.SendMessage(context.Background(), "nope", nil); err == nil {
+		t.Fatal("expected error surfaced from the response body")
+	}
+}
+
+func TestOllamaProviderContract(t *testing.T) {
+	am := NewAgentManager()
+	provider := NewOllamaProvider(NewOllamaClient(OllamaConfig{}))
+	if err := am.AddProvider(provider); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	if p, ok := am.GetProvider("ollama"); !ok || p.Name() != "Ollama" {
+		t.Errorf("provider lookup = %v, %v", p, ok)
+	}
+}
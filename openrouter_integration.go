@@ -0,0 +1,787 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ==================== OPENROUTER TYPES ====================
+
+// ChatMessage is one turn in a chat completion request.
+type ChatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	Name       string     `json:"name,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// ToolSpec declares one callable function in OpenAI tools format.
+type ToolSpec struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes a tool's name and JSON-schema parameters.
+type ToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// ToolCall is the model asking for a function to be executed.
+type ToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// OpenRouterConfig configures the OpenRouter API client.
+type OpenRouterConfig struct {
+	APIKey        string        `json:"api_key"`
+	BaseURL       string        `json:"base_url"`
+	Model         string        `json:"model"`
+	Timeout       time.Duration `json:"timeout,omitempty"`
+	StreamTimeout time.Duration `json:"stream_timeout,omitempty"`
+	ProxyURL      string        `json:"proxy_url,omitempty"`
+}
+
+// RetryConfig controls retry behavior for transient API failures.
+type RetryConfig struct {
+	MaxAttempts int           `json:"max_attempts"`
+	BaseDelay   time.Duration `json:"base_delay"`
+	MaxDelay    time.Duration `json:"max_delay"`
+}
+
+// DefaultRetryConfig retries transient failures three times.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// openRouterRequest is the wire shape of a chat completion request.
+type openRouterRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	Tools    []ToolSpec    `json:"tools,omitempty"`
+}
+
+// OpenRouterResponse is the wire shape of a chat completion response.
+type OpenRouterResponse struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Message ChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openRouterModels is the wire shape of the model listing response.
+type openRouterModels struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// ==================== RATE LIMITING ====================
+
+// rateLimiter is a token bucket shared by all client calls.
+type rateLimiter struct {
+	mu        sync.Mutex
+	tokens    float64
+	burst     float64
+	perSecond float64
+	last      time.Time
+}
+
+// newRateLimiter allows rpm requests per minute with a burst of one.
+func newRateLimiter(rpm int) *rateLimiter {
+	return &rateLimiter{
+		tokens:    1,
+		burst:     1,
+		perSecond: float64(rpm) / 60.0,
+		last:      time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is done.
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+		rl.tokens += now.Sub(rl.last).Seconds() * rl.perSecond
+		if rl.tokens > rl.burst {
+			rl.tokens = rl.burst
+		}
+		rl.last = now
+		if rl.tokens >= 1 {
+			rl.tokens--
+			rl.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - rl.tokens) / rl.perSecond * float64(time.Second))
+		rl.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// ==================== OPENROUTER CLIENT ====================
+
+// OpenRouterClient talks to the OpenRouter chat completion API.
+type OpenRouterClient struct {
+	mu           sync.Mutex
+	config       OpenRouterConfig
+	httpClient   *http.Client
+	streamClient *http.Client
+	retry        RetryConfig
+	limiter      *rateLimiter
+}
+
+// NewOpenRouterClient builds a client with sensible defaults. Streaming
+// requests get their own client with a longer timeout, since completions
+// can take minutes to finish token by token.
+func NewOpenRouterClient(config OpenRouterConfig) *OpenRouterClient {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://openrouter.ai/api/v1"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.StreamTimeout <= 0 {
+		config.StreamTimeout = 5 * time.Minute
+	}
+
+	transport := http.DefaultTransport
+	if config.ProxyURL != "" {
+		if proxy, err := url.Parse(config.ProxyURL); err == nil {
+			transport = &http.Transport{Proxy: http.ProxyURL(proxy)}
+		}
+	}
+	return &OpenRouterClient{
+		config:       config,
+		httpClient:   &http.Client{Timeout: config.Timeout, Transport: transport},
+		streamClient: &http.Client{Timeout: config.StreamTimeout, Transport: transport},
+		retry:        DefaultRetryConfig(),
+	}
+}
+
+// SetHTTPClient injects a custom client for both request kinds, mainly
+// for tests.
+func (c *OpenRouterClient) SetHTTPClient(client *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.httpClient = client
+	c.streamClient = client
+}
+
+// SetRateLimit throttles the client to rpm requests per minute. A value
+// of zero or less removes the limit.
+func (c *OpenRouterClient) SetRateLimit(rpm int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rpm <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRateLimiter(rpm)
+}
+
+// acquireToken blocks on the rate limiter when one is configured.
+func (c *OpenRouterClient) acquireToken(ctx context.Context) error {
+	c.mu.Lock()
+	limiter := c.limiter
+	c.mu.Unlock()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
+
+// SetRetryConfig overrides the retry policy.
+func (c *OpenRouterClient) SetRetryConfig(retry RetryConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if retry.MaxAttempts > 0 {
+		c.retry = retry
+	}
+}
+
+// retryable reports whether an HTTP status warrants another attempt.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryDelay computes the jittered backoff before the given attempt,
+// honoring a Retry-After header when the server sent one.
+func (c *OpenRouterClient) retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	delay := c.retry.BaseDelay << uint(attempt)
+	if delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+	// Jitter ±25% so simultaneous clients don't retry in lockstep.
+	jitter := time.Duration(rand.Int63n(int64(delay)/2+1)) - delay/4
+	return delay + jitter
+}
+
+// doWithRetry issues the request, retrying 429s and 5xx responses with
+// backoff until the attempt budget or context is exhausted.
+func (c *OpenRouterClient) doWithRetry(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	if err := c.acquireToken(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	retry := c.retry
+	client := c.httpClient
+	apiKey := c.config.APIKey
+	c.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			retryAfter := ""
+			if he, ok := lastErr.(*httpStatusError); ok {
+				retryAfter = he.retryAfter
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.retryDelay(attempt-1, retryAfter)):
+			}
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			if readErr != nil {
+				return nil, readErr
+			}
+			return data, nil
+		}
+		lastErr = &httpStatusError{
+			status:     resp.StatusCode,
+			body:       string(data),
+			retryAfter: resp.Header.Get("Retry-After"),
+		}
+		if !retryable(resp.StatusCode) {
+			return nil, lastErr
+		}
+	}
+	return nil, fmt.Errorf("request failed after %d attempts: %w", retry.MaxAttempts, lastErr)
+}
+
+// httpStatusError carries a non-200 response through the retry loop.
+type httpStatusError struct {
+	status     int
+	body       string
+	retryAfter string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.status, e.body)
+}
+
+// sendMessageFull sends a chat completion request and returns the parsed
+// response including usage numbers.
+func (c *OpenRouterClient) sendMessageFull(ctx context.Context, model string, messages []ChatMessage) (*OpenRouterResponse, error) {
+	return c.sendWithTools(ctx, model, messages, nil)
+}
+
+// sendWithTools is sendMessageFull with optional tool definitions.
+func (c *OpenRouterClient) sendWithTools(ctx context.Context, model string, messages []ChatMessage, tools []ToolSpec) (*OpenRouterResponse, error) {
+	if model == "" {
+		model = c.config.Model
+	}
+	payload, err := json.Marshal(openRouterRequest{Model: model, Messages: messages, Tools: tools})
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.doWithRetry(ctx, http.MethodPost, c.config.BaseURL+"/chat/completions", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed OpenRouterResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("response contained no choices")
+	}
+	return &parsed, nil
+}
+
+// SendMessage sends a chat completion request and returns the reply text.
+func (c *OpenRouterClient) SendMessage(ctx context.Context, model string, messages []ChatMessage) (string, error) {
+	parsed, err := c.sendMessageFull(ctx, model, messages)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// GetModels lists the model IDs available to this API key.
+func (c *OpenRouterClient) GetModels(ctx context.Context) ([]string, error) {
+	data, err := c.doWithRetry(ctx, http.MethodGet, c.config.BaseURL+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	var parsed openRouterModels
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse models: %w", err)
+	}
+	models := make([]string, 0, len(parsed.Data))
+	for _, m := range parsed.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
+
+// ==================== TOOL CALLING ====================
+
+// ToolHandler executes one tool invocation with parsed JSON arguments.
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (string, error)
+
+// ToolDispatcher holds registered tools and executes the model's
+// tool_calls, producing the tool-role messages to feed back.
+type ToolDispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string]ToolHandler
+	specs    []ToolSpec
+}
+
+// NewToolDispatcher returns a dispatcher with no tools registered.
+func NewToolDispatcher() *ToolDispatcher {
+	return &ToolDispatcher{handlers: make(map[string]ToolHandler)}
+}
+
+// Register adds a tool; the spec is sent to the model, the handler runs
+// when the model calls it.
+func (td *ToolDispatcher) Register(spec ToolSpec, handler ToolHandler) error {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	name := spec.Function.Name
+	if name == "" || handler == nil {
+		return fmt.Errorf("tool registration requires a function name and handler")
+	}
+	if _, exists := td.handlers[name]; exists {
+		return fmt.Errorf("tool %s already registered", name)
+	}
+	if spec.Type == "" {
+		spec.Type = "function"
+	}
+	td.handlers[name] = handler
+	td.specs = append(td.specs, spec)
+	return nil
+}
+
+// Specs returns the registered tool definitions for the request body.
+func (td *ToolDispatcher) Specs() []ToolSpec {
+	td.mu.RLock()
+	defer td.mu.RUnlock()
+	out := make([]ToolSpec, len(td.specs))
+	copy(out, td.specs)
+	return out
+}
+
+// Dispatch executes one tool call and wraps the result as a tool-role
+// message referencing the call ID.
+func (td *ToolDispatcher) Dispatch(ctx context.Context, call ToolCall) (ChatMessage, error) {
+	td.mu.RLock()
+	handler, ok := td.handlers[call.Function.Name]
+	td.mu.RUnlock()
+	if !ok {
+		return ChatMessage{}, fmt.Errorf("unknown tool %s", call.Function.Name)
+	}
+
+	args := make(map[string]interface{})
+	if call.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			return ChatMessage{}, fmt.Errorf("invalid arguments for tool %s: %w", call.Function.Name, err)
+		}
+	}
+	result, err := handler(ctx, args)
+	if err != nil {
+		return ChatMessage{}, fmt.Errorf("tool %s failed: %w", call.Function.Name, err)
+	}
+	return ChatMessage{
+		Role:       "tool",
+		Name:       call.Function.Name,
+		ToolCallID: call.ID,
+		Content:    result,
+	}, nil
+}
+
+// maxToolRounds bounds how many tool round-trips one request may take.
+const maxToolRounds = 5
+
+// SendMessageWithTools runs a completion that may call registered tools,
+// executing each tool_call and feeding results back until the model
+// answers with plain content.
+func (c *OpenRouterClient) SendMessageWithTools(ctx context.Context, model string, messages []ChatMessage, dispatcher *ToolDispatcher) (string, error) {
+	specs := dispatcher.Specs()
+	for round := 0; round < maxToolRounds; round++ {
+		resp, err := c.sendWithTools(ctx, model, messages, specs)
+		if err != nil {
+			return "", err
+		}
+		reply := resp.Choices[0].Message
+		if len(reply.ToolCalls) == 0 {
+			return reply.Content, nil
+		}
+
+		messages = append(messages, reply)
+		for _, call := range reply.ToolCalls {
+			result, err := dispatcher.Dispatch(ctx, call)
+			if err != nil {
+				return "", err
+			}
+			messages = append(messages, result)
+		}
+	}
+	return "", fmt.Errorf("tool loop exceeded %d rounds", maxToolRounds)
+}
+
+// ==================== USAGE & COST ====================
+
+// UsageStats aggregates token counts across requests.
+type UsageStats struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	Requests         int `json:"requests"`
+}
+
+// add folds one response's usage into the running totals.
+func (u *UsageStats) add(prompt, completion, total int) {
+	u.PromptTokens += prompt
+	u.CompletionTokens += completion
+	u.TotalTokens += total
+	u.Requests++
+}
+
+// UsageTracker accumulates token usage per model and per conversation.
+type UsageTracker struct {
+	mu      sync.Mutex
+	byModel map[string]UsageStats
+	byConv  map[string]UsageStats
+	totals  UsageStats
+}
+
+// NewUsageTracker returns an empty tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		byModel: make(map[string]UsageStats),
+		byConv:  make(map[string]UsageStats),
+	}
+}
+
+// Record folds one response's usage into the per-model, per-conversation
+// and overall totals.
+func (ut *UsageTracker) Record(model, conversationID string, prompt, completion, total int) {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+
+	m := ut.byModel[model]
+	m.add(prompt, completion, total)
+	ut.byModel[model] = m
+
+	if conversationID != "" {
+		c := ut.byConv[conversationID]
+		c.add(prompt, completion, total)
+		ut.byConv[conversationID] = c
+	}
+	ut.totals.add(prompt, completion, total)
+}
+
+// Usage returns accumulated usage for one conversation.
+func (ut *UsageTracker) Usage(conversationID string) UsageStats {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	return ut.byConv[conversationID]
+}
+
+// ModelUsage returns accumulated usage for one model.
+func (ut *UsageTracker) ModelUsage(model string) UsageStats {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	return ut.byModel[model]
+}
+
+// Totals returns usage summed across all models and conversations.
+func (ut *UsageTracker) Totals() UsageStats {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	return ut.totals
+}
+
+// Models returns a snapshot of per-model usage for cost estimation.
+func (ut *UsageTracker) Models() map[string]UsageStats {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	out := make(map[string]UsageStats, len(ut.byModel))
+	for model, usage := range ut.byModel {
+		out[model] = usage
+	}
+	return out
+}
+
+// ModelPrice is the dollar cost per thousand tokens for one model.
+type ModelPrice struct {
+	PromptPer1K     float64 `json:"prompt_per_1k"`
+	CompletionPer1K float64 `json:"completion_per_1k"`
+}
+
+// CostEstimator turns tracked usage into a running dollar estimate.
+type CostEstimator struct {
+	mu     sync.Mutex
+	prices map[string]ModelPrice
+}
+
+// NewCostEstimator returns an estimator with no prices configured.
+func NewCostEstimator() *CostEstimator {
+	return &CostEstimator{prices: make(map[string]ModelPrice)}
+}
+
+// SetPrice configures the price table entry for a model.
+func (ce *CostEstimator) SetPrice(model string, price ModelPrice) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	ce.prices[model] = price
+}
+
+// Estimate computes the dollar cost of the given usage for one model.
+// Unpriced models cost zero.
+func (ce *CostEstimator) Estimate(model string, usage UsageStats) float64 {
+	ce.mu.Lock()
+	price, ok := ce.prices[model]
+	ce.mu.Unlock()
+	if !ok {
+		return 0
+	}
+	return float64(usage.PromptTokens)/1000.0*price.PromptPer1K +
+		float64(usage.CompletionTokens)/1000.0*price.CompletionPer1K
+}
+
+// EstimateTotal sums the estimated cost across every tracked model.
+func (ce *CostEstimator) EstimateTotal(tracker *UsageTracker) float64 {
+	var total float64
+	for model, usage := range tracker.Models() {
+		total += ce.Estimate(model, usage)
+	}
+	return total
+}
+
+// ==================== OPENROUTER PROVIDER ====================
+
+// OpenRouterProvider adapts the client to the agent Provider contract and
+// keeps per-conversation context between calls.
+type OpenRouterProvider struct {
+	client *OpenRouterClient
+
+	mu                 sync.Mutex
+	histories          map[string][]ChatMessage
+	maxContextMessages int
+	usage              *UsageTracker
+}
+
+// NewOpenRouterProvider wraps a client for agent registration.
+func NewOpenRouterProvider(client *OpenRouterClient) *OpenRouterProvider {
+	return &OpenRouterProvider{
+		client:             client,
+		histories:          make(map[string][]ChatMessage),
+		maxContextMessages: 20,
+	}
+}
+
+// ID implements Provider.
+func (p *OpenRouterProvider) ID() string { return "openrouter" }
+
+// Name implements Provider.
+func (p *OpenRouterProvider) Name() string { return "OpenRouter" }
+
+// SetUsageTracker makes the provider record token usage per call.
+func (p *OpenRouterProvider) SetUsageTracker(tracker *UsageTracker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usage = tracker
+}
+
+// Usage returns accumulated token usage for one conversation.
+func (p *OpenRouterProvider) Usage(conversationID string) UsageStats {
+	p.mu.Lock()
+	tracker := p.usage
+	p.mu.Unlock()
+	if tracker == nil {
+		return UsageStats{}
+	}
+	return tracker.Usage(conversationID)
+}
+
+// recordUsage folds a response's usage into the tracker when one is set.
+func (p *OpenRouterProvider) recordUsage(model, conversationID string, resp *OpenRouterResponse) {
+	p.mu.Lock()
+	tracker := p.usage
+	p.mu.Unlock()
+	if tracker == nil {
+		return
+	}
+	tracker.Record(model, conversationID,
+		resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.Usage.TotalTokens)
+}
+
+// SetMaxContextMessages bounds how many prior turns are resent per call.
+func (p *OpenRouterProvider) SetMaxContextMessages(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n > 0 {
+		p.maxContextMessages = n
+	}
+}
+
+// ResetConversation drops the stored history for a conversation.
+func (p *OpenRouterProvider) ResetConversation(conversationID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.histories, conversationID)
+}
+
+// contextFor snapshots the stored history plus the new user turn.
+func (p *OpenRouterProvider) contextFor(conversationID, content string) []ChatMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	history := p.histories[conversationID]
+	messages := make([]ChatMessage, 0, len(history)+1)
+	messages = append(messages, history...)
+	messages = append(messages, ChatMessage{Role: "user", Content: content})
+	return messages
+}
+
+// remember appends an exchange to the history, trimming the oldest turns
+// once the context budget is exceeded.
+func (p *OpenRouterProvider) remember(conversationID string, exchange ...ChatMessage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	history := append(p.histories[conversationID], exchange...)
+	if over := len(history) - p.maxContextMessages; over > 0 {
+		history = history[over:]
+	}
+	p.histories[conversationID] = history
+}
+
+// SendMessage sends one user turn within a conversation, resending prior
+// context so the model has memory.
+func (p *OpenRouterProvider) SendMessage(ctx context.Context, conversationID, model, content string) (string, error) {
+	messages := p.contextFor(conversationID, content)
+	resp, err := p.client.sendMessageFull(ctx, model, messages)
+	if err != nil {
+		return "", err
+	}
+	p.recordUsage(model, conversationID, resp)
+	reply := resp.Choices[0].Message.Content
+	p.remember(conversationID,
+		ChatMessage{Role: "user", Content: content},
+		ChatMessage{Role: "assistant", Content: reply},
+	)
+	return reply, nil
+}
+
+// defaultSystemPrompt builds a prompt from an agent's role and
+// personality when no explicit system prompt is configured.
+func defaultSystemPrompt(cfg AgentConfig) string {
+	name := cfg.Name
+	if name == "" {
+		name = cfg.ID
+	}
+	prompt := fmt.Sprintf("You are %s, an AI agent", name)
+	if cfg.Role != "" {
+		prompt += fmt.Sprintf(" acting as %s", cfg.Role)
+	}
+	prompt += "."
+	if cfg.Personality != "" {
+		prompt += fmt.Sprintf(" Your personality: %s.", cfg.Personality)
+	}
+	prompt += " Keep responses concise and relevant to the conversation."
+	return prompt
+}
+
+// systemPromptFor resolves the agent's system prompt, falling back to a
+// derived default.
+func systemPromptFor(cfg AgentConfig) string {
+	if cfg.SystemPrompt != "" {
+		return cfg.SystemPrompt
+	}
+	return defaultSystemPrompt(cfg)
+}
+
+// SendMessageAs sends a turn on behalf of an agent, injecting the agent's
+// system prompt ahead of the conversation context. The system message is
+// not stored in history so prompt changes take effect immediately.
+func (p *OpenRouterProvider) SendMessageAs(ctx context.Context, cfg AgentConfig, conversationID, content string) (string, error) {
+	messages := append(
+		[]ChatMessage{{Role: "system", Content: systemPromptFor(cfg)}},
+		p.contextFor(conversationID, content)...,
+	)
+	model := cfg.Model
+	resp, err := p.client.sendMessageFull(ctx, model, messages)
+	if err != nil {
+		return "", err
+	}
+	p.recordUsage(model, conversationID, resp)
+	reply := resp.Choices[0].Message.Content
+	p.remember(conversationID,
+		ChatMessage{Role: "user", Content: content},
+		ChatMessage{Role: "assistant", Content: reply},
+	)
+	return reply, nil
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chatReply builds a minimal chat completion response body.
+func chatReply(content string) []byte {
+	resp := map[string]interface{}{
+		"id": "gen-1",
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": content}},
+		},
+		"usage": map[string]int{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15},
+	}
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+// fastRetry keeps test backoff tight.
+func fastRetry() RetryConfig {
+	return RetryConfig{MaxAttempts: 4, BaseDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond}
+}
+
+func TestSendMessageRetriesOn429(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(chatReply("finally"))
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL, Model: "test-model"})
+	client.SetRetryConfig(fastRetry())
+
+	got, err := client.SendMessage(context.Background(), "", []ChatMessage{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if got != "finally" {
+		t.Errorf("reply = %q, want finally", got)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("server saw %d calls, want 3", n)
+	}
+}
+
+func TestSendMessageDoesNotRetry4xx(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	client.SetRetryConfig(fastRetry())
+
+	if _, err := client.SendMessage(context.Background(), "m", nil); err == nil {
+		t.Fatal("expected error on 401")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("server saw %d calls, want 1 (no retry on 401)", n)
+	}
+}
+
+func TestSendMessageRetryRespectsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	client.SetRetryConfig(RetryConfig{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.SendMessage(ctx, "m", nil); err == nil {
+		t.Fatal("expected error when context expires mid-retry")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("retry loop did not honor context cancellation")
+	}
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chatReply("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	// 600 rpm = one request every 100ms.
+	client.SetRateLimit(600)
+
+	const calls = 3
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		if _, err := client.SendMessage(context.Background(), "m", nil); err != nil {
+			t.Fatalf("SendMessage %d: %v", i, err)
+		}
+	}
+	elapsed := time.Since(start)
+	// First call uses the initial token; the next two must each wait.
+	if want := 180 * time.Millisecond; elapsed < want {
+		t.Errorf("3 calls finished in %v, want at least %v", elapsed, want)
+	}
+}
+
+func TestRateLimitHonorsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chatReply("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	client.SetRateLimit(1) // one request a minute
+
+	if _, err := client.SendMessage(context.Background(), "m", nil); err != nil {
+		t.Fatalf("first SendMessage: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendMessage(ctx, "m", nil); err == nil {
+		t.Fatal("expected second call to fail when context expires waiting for a token")
+	}
+}
+
+// captureMessages records the messages of each chat request it serves.
+func captureMessages(t *testing.T, requests *[][]ChatMessage) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []ChatMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		*requests = append(*requests, req.Messages)
+		w.Write(chatReply("reply"))
+	}
+}
+
+func TestProviderResendsConversationHistory(t *testing.T) {
+	var requests [][]ChatMessage
+	srv := httptest.NewServer(captureMessages(t, &requests))
+	defer srv.Close()
+
+	provider := NewOpenRouterProvider(NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL}))
+	ctx := context.Background()
+
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "first question"); err != nil {
+		t.Fatalf("first SendMessage: %v", err)
+	}
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "second question"); err != nil {
+		t.Fatalf("second SendMessage: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("server saw %d requests, want 2", len(requests))
+	}
+	second := requests[1]
+	if len(second) != 3 {
+		t.Fatalf("second request had %d messages, want 3 (prior exchange + new turn)", len(second))
+	}
+	if second[0].Content != "first question" || second[1].Content != "reply" || second[2].Content != "second question" {
+		t.Errorf("unexpected context: %+v", second)
+	}
+
+	// A different conversation must start clean.
+	if _, err := provider.SendMessage(ctx, "conv-2", "m", "hello"); err != nil {
+		t.Fatalf("conv-2 SendMessage: %v", err)
+	}
+	if got := len(requests[2]); got != 1 {
+		t.Errorf("new conversation sent %d messages, want 1", got)
+	}
+}
+
+func TestProviderTrimsOldestContext(t *testing.T) {
+	var requests [][]ChatMessage
+	srv := httptest.NewServer(captureMessages(t, &requests))
+	defer srv.Close()
+
+	provider := NewOpenRouterProvider(NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL}))
+	provider.SetMaxContextMessages(4)
+	ctx := context.Background()
+
+	for _, content := range []string{"turn-1", "turn-2", "turn-3", "turn-4"} {
+		if _, err := provider.SendMessage(ctx, "conv-1", "m", content); err != nil {
+			t.Fatalf("SendMessage %s: %v", content, err)
+		}
+	}
+
+	last := requests[len(requests)-1]
+	// Budget of 4 stored messages: two full exchanges, so turn-1's
+	// exchange must have been dropped before the final call.
+	if len(last) != 5 {
+		t.Fatalf("final request had %d messages, want 5", len(last))
+	}
+	if last[0].Content != "turn-2" {
+		t.Errorf("oldest retained message = %q, want turn-2", last[0].Content)
+	}
+}
+
+func TestResetConversation(t *testing.T) {
+	var requests [][]ChatMessage
+	srv := httptest.NewServer(captureMessages(t, &requests))
+	defer srv.Close()
+
+	provider := NewOpenRouterProvider(NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL}))
+	ctx := context.Background()
+
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "before reset"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	provider.ResetConversation("conv-1")
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "after reset"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if got := len(requests[1]); got != 1 {
+		t.Errorf("post-reset request sent %d messages, want 1", got)
+	}
+}
+
+func TestSendMessageAsInjectsSystemPrompt(t *testing.T) {
+	var requests [][]ChatMessage
+	srv := httptest.NewServer(captureMessages(t, &requests))
+	defer srv.Close()
+
+	provider := NewOpenRouterProvider(NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL}))
+	ctx := context.Background()
+
+	cfg := AgentConfig{ID: "coder", Name: "Coder", SystemPrompt: "You only write Go."}
+	if _, err := provider.SendMessageAs(ctx, cfg, "conv-1", "hello"); err != nil {
+		t.Fatalf("SendMessageAs: %v", err)
+	}
+	first := requests[0]
+	if first[0].Role != "system" || first[0].Content != "You only write Go." {
+		t.Errorf("first message = %+v, want the configured system prompt", first[0])
+	}
+
+	// Follow-up turns keep the system prompt first, ahead of history.
+	if _, err := provider.SendMessageAs(ctx, cfg, "conv-1", "again"); err != nil {
+		t.Fatalf("SendMessageAs: %v", err)
+	}
+	second := requests[1]
+	if second[0].Role != "system" {
+		t.Errorf("system prompt not first on follow-up: %+v", second[0])
+	}
+	if len(second) != 4 {
+		t.Errorf("follow-up had %d messages, want 4 (system + exchange + new turn)", len(second))
+	}
+}
+
+func TestDefaultSystemPrompt(t *testing.T) {
+	cfg := AgentConfig{ID: "analyst", Name: "Analyst", Role: "data analyst", Personality: "precise and skeptical"}
+	got := defaultSystemPrompt(cfg)
+	for _, want := range []string{"Analyst", "data analyst", "precise and skeptical"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("default prompt %q missing %q", got, want)
+		}
+	}
+	if systemPromptFor(AgentConfig{ID: "x", SystemPrompt: "custom"}) != "custom" {
+		t.Error("explicit SystemPrompt should win over the default")
+	}
+}
+
+func TestConfiguredTimeoutCutsSlowServer(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write(chatReply("too slow"))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL, Timeout: 50 * time.Millisecond})
+	client.SetRetryConfig(RetryConfig{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	start := time.Now()
+	_, err := client.SendMessage(context.Background(), "m", nil)
+	if err == nil {
+		t.Fatal("expected timeout error from slow server")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("error %v is not a timeout", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("request hung past the configured timeout")
+	}
+}
+
+func TestSetHTTPClientInjection(t *testing.T) {
+	var sawUserAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawUserAgent = r.Header.Get("User-Agent")
+		w.Write(chatReply("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	client.SetHTTPClient(&http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("User-Agent", "custom-test-client")
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	})
+	if _, err := client.SendMessage(context.Background(), "m", nil); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if sawUserAgent != "custom-test-client" {
+		t.Errorf("User-Agent = %q, injected transport not used", sawUserAgent)
+	}
+}
+
+// roundTripperFunc adapts a func to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestUsageTrackerAggregation(t *testing.T) {
+	tracker := NewUsageTracker()
+	tracker.Record("model-a", "conv-1", 100, 50, 150)
+	tracker.Record("model-a", "conv-1", 200, 100, 300)
+	tracker.Record("model-b", "conv-2", 10, 5, 15)
+
+	conv := tracker.Usage("conv-1")
+	if conv.PromptTokens != 300 || conv.CompletionTokens != 150 || conv.TotalTokens != 450 || conv.Requests != 2 {
+		t.Errorf("conv-1 usage = %+v", conv)
+	}
+	model := tracker.ModelUsage("model-b")
+	if model.TotalTokens != 15 || model.Requests != 1 {
+		t.Errorf("model-b usage = %+v", model)
+	}
+	totals := tracker.Totals()
+	if totals.TotalTokens != 465 || totals.Requests != 3 {
+		t.Errorf("totals = %+v", totals)
+	}
+}
+
+func TestCostEstimator(t *testing.T) {
+	tracker := NewUsageTracker()
+	tracker.Record("model-a", "conv-1", 1000, 500, 1500)
+	tracker.Record("model-b", "conv-1", 2000, 0, 2000)
+
+	est := NewCostEstimator()
+	est.SetPrice("model-a", ModelPrice{PromptPer1K: 0.01, CompletionPer1K: 0.03})
+	est.SetPrice("model-b", ModelPrice{PromptPer1K: 0.002})
+
+	// model-a: 1.0 * 0.01 + 0.5 * 0.03 = 0.025; model-b: 2.0 * 0.002 = 0.004.
+	got := est.EstimateTotal(tracker)
+	if got < 0.0289 || got > 0.0291 {
+		t.Errorf("EstimateTotal = %v, want 0.029", got)
+	}
+	if c := est.Estimate("unpriced", UsageStats{PromptTokens: 1e6}); c != 0 {
+		t.Errorf("unpriced model cost = %v, want 0", c)
+	}
+}
+
+func TestProviderRecordsUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chatReply("ok")) // usage: 10 prompt, 5 completion, 15 total
+	}))
+	defer srv.Close()
+
+	provider := NewOpenRouterProvider(NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL}))
+	tracker := NewUsageTracker()
+	provider.SetUsageTracker(tracker)
+
+	ctx := context.Background()
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "hi"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if _, err := provider.SendMessage(ctx, "conv-1", "m", "again"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	usage := provider.Usage("conv-1")
+	if usage.PromptTokens != 20 || usage.CompletionTokens != 10 || usage.Requests != 2 {
+		t.Errorf("conv-1 usage = %+v", usage)
+	}
+}
+
+func TestToolCallRoundTrip(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []ChatMessage `json:"messages"`
+			Tools    []ToolSpec    `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			if len(req.Tools) != 1 || req.Tools[0].Function.Name != "read_file" {
+				t.Errorf("tools not sent: %+v", req.Tools)
+			}
+			w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"",` +
+				`"tool_calls":[{"id":"call-1","type":"function","function":{"name":"read_file",` +
+				`"arguments":"{\"path\":\"notes.txt\"}"}}]}}]}`))
+			return
+		}
+		// Second round: the tool result must be in the transcript.
+		last := req.Messages[len(req.Messages)-1]
+		if last.Role != "tool" || last.ToolCallID != "call-1" || last.Content != "file contents here" {
+			t.Errorf("tool result not fed back: %+v", last)
+		}
+		w.Write(chatReply("the file says: file contents here"))
+	}))
+	defer srv.Close()
+
+	dispatcher := NewToolDispatcher()
+	var gotArgs map[string]interface{}
+	err := dispatcher.Register(ToolSpec{
+		Function: ToolFunction{Name: "read_file", Description: "Read a file"},
+	}, func(ctx context.Context, args map[string]interface{}) (string, error) {
+		gotArgs = args
+		return "file contents here", nil
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	reply, err := client.SendMessageWithTools(context.Background(), "m",
+		[]ChatMessage{{Role: "user", Content: "what is in notes.txt"}}, dispatcher)
+	if err != nil {
+		t.Fatalf("SendMessageWithTools: %v", err)
+	}
+	if reply != "the file says: file contents here" {
+		t.Errorf("reply = %q", reply)
+	}
+	if gotArgs["path"] != "notes.txt" {
+		t.Errorf("dispatcher args = %v, want path=notes.txt", gotArgs)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("server saw %d calls, want 2", n)
+	}
+}
+
+func TestDispatchUnknownTool(t *testing.T) {
+	dispatcher := NewToolDispatcher()
+	call := ToolCall{ID: "call-1", Type: "function"}
+	call.Function.Name = "missing"
+	if _, err := dispatcher.Dispatch(context.Background(), call); err == nil {
+		t.Error("expected unknown tool to error")
+	}
+}
+
+func TestGetModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]string{{"id": "model-a"}, {"id": "model-b"}},
+		})
+	}))
+	defer srv.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{BaseURL: srv.URL})
+	models, err := client.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	if len(models) != 2 || models[0] != "model-a" || models[1] != "model-b" {
+		t.Errorf("models = %v, want [model-a model-b]", models)
+	}
+}
@@ -132,7 +132,11 @@ func TestMemoryUsageExtended(t *testing.T) {
 	var m2 runtime.MemStats
 	runtime.ReadMemStats(&m2)
 
-	memUsed := m2.Alloc - m1.Alloc
+	// Alloc can shrink across GCs, so guard against underflow.
+	var memUsed uint64
+	if m2.Alloc > m1.Alloc {
+		memUsed = m2.Alloc - m1.Alloc
+	}
 	t.Logf("Memory used: %d bytes", memUsed)
 
 	// Memory usage should be reasonable (less than 10MB)
@@ -182,12 +186,11 @@ func TestStressManyParticles(t *testing.T) {
 	// Add many more particles
 	for i := 0; i < 500; i++ { // Add 500 more particles
 		particle := Particle{
-			X:       float64(i % animator.width),
-			Y:       float64(i % animator.height),
-			SpeedX:  (rand.Float64() - 0.5) * 2,
-			SpeedY:  (rand.Float64() - 0.5) * 2,
+			X:       float64(i % sceneWidth),
+			Y:       float64(i % sceneHeight),
+			VX:      (rand.Float64() - 0.5) * 2,
+			VY:      (rand.Float64() - 0.5) * 2,
 			Color:   getRandomColor(),
-			Type:    "bubble",
 			Opacity: rand.Float64(),
 		}
 		animator.particles = append(animator.particles, particle)
@@ -329,21 +332,22 @@ func TestMemoryLeakDetection(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		model := initialModel()
 
-		// Use the model
+		// Use the model, then let it fall out of scope for collection
 		for j := 0; j < 10; j++ {
 			model.Update(time.Now())
 			model.View()
 		}
-
-		// Model should be garbage collected
-		model = nil
 	}
 
 	runtime.GC()
 	var m2 runtime.MemStats
 	runtime.ReadMemStats(&m2)
 
-	memUsed := m2.Alloc - m1.Alloc
+	// Alloc can shrink across GCs, so guard against underflow.
+	var memUsed uint64
+	if m2.Alloc > m1.Alloc {
+		memUsed = m2.Alloc - m1.Alloc
+	}
 	t.Logf("Memory after creating/destroying 100 models: %d bytes", memUsed)
 
 	// Should not leak significant memory
@@ -387,17 +391,16 @@ func TestExtremeValuesStress(t *testing.T) {
 
 // Test resource exhaustion
 func TestResourceExhaustion(t *testing.T) {
-	// Test with very large window sizes
+	// Flood the animator with far more particle bursts than it retains
 	animator := NewUnderwaterAnimator()
-
-	// Simulate very large window
-	animator.width = 10000
-	animator.height = 10000
+	for i := 0; i < 1000; i++ {
+		animator.Emit(float64(i%sceneWidth), float64(i%sceneHeight), 10)
+	}
 
 	// Should handle gracefully
 	err := animator.Update(0.016)
 	if err != nil {
-		t.Errorf("Update failed with large window: %v", err)
+		t.Errorf("Update failed after particle flood: %v", err)
 	}
 
 	// Render might be slow but should not panic
@@ -405,10 +408,10 @@ func TestResourceExhaustion(t *testing.T) {
 	render := animator.Render()
 	duration := time.Since(start)
 
-	t.Logf("Large window render: %v, length: %d", duration, len(render))
+	t.Logf("Flooded render: %v, length: %d", duration, len(render))
 
 	if len(render) == 0 {
-		t.Error("Render should not return empty string even for large windows")
+		t.Error("Render should not return empty string after particle flood")
 	}
 }
 
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// drainJob applies job reports to the model until the job signals done or
+// the timeout elapses.
+func drainJob(t *testing.T, m Model, id string) Model {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-progressReports:
+			m, _ = m.updateProgress(msg)
+			if msg.ID == id && msg.Done {
+				return m
+			}
+		case <-deadline:
+			t.Fatal("Timed out waiting for job completion")
+			return m
+		}
+	}
+}
+
+// Test a registered job driving its progress row to 100%
+func TestRegisterJobReachesCompletion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	m.RegisterJob("fake-job", "Fake Job", func(ctx context.Context, report func(float64)) error {
+		for _, p := range []float64{0, 0.25, 0.5, 0.75, 1.0} {
+			report(p)
+		}
+		return nil
+	})
+
+	m = drainJob(t, m, "fake-job")
+
+	var job *Progress
+	for i := range m.progress {
+		if m.progress[i].ID == "fake-job" {
+			job = &m.progress[i]
+		}
+	}
+	if job == nil {
+		t.Fatal("Job progress row was not registered")
+	}
+	if job.Percent < 1.0 {
+		t.Errorf("Expected 100%% progress, got %.2f", job.Percent)
+	}
+	if job.IsActive {
+		t.Error("Expected completed job to be inactive")
+	}
+	if job.Error != "" {
+		t.Errorf("Expected no error, got %s", job.Error)
+	}
+}
+
+// Test that a failing job captures its error
+func TestRegisterJobCapturesError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	m.RegisterJob("failing-job", "Failing Job", func(ctx context.Context, report func(float64)) error {
+		report(0.5)
+		return errors.New("disk full")
+	})
+
+	m = drainJob(t, m, "failing-job")
+
+	for _, p := range m.progress {
+		if p.ID == "failing-job" {
+			if p.Error != "disk full" {
+				t.Errorf("Expected error 'disk full', got %q", p.Error)
+			}
+			if p.IsActive {
+				t.Error("Expected failed job to be inactive")
+			}
+			return
+		}
+	}
+	t.Fatal("Failing job row not found")
+}
+
+// Test ETA estimation against a synthetic timeline
+func TestEstimateETA(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := Progress{ID: "eta", IsActive: true}
+
+	// Steady 10%/second for five samples
+	for i := 1; i <= 5; i++ {
+		applyProgressSample(&p, float64(i)*0.1, start.Add(time.Duration(i)*time.Second))
+	}
+
+	remaining, ok := estimateETA(p, start.Add(5*time.Second))
+	if !ok {
+		t.Fatal("Expected an ETA with steady progress")
+	}
+	// 50% left at ~10%/s should be about 5 seconds
+	if remaining < 4*time.Second || remaining > 6*time.Second {
+		t.Errorf("Expected ETA near 5s, got %v", remaining)
+	}
+}
+
+// Test that ETA reports not-ok without enough data
+func TestEstimateETANoData(t *testing.T) {
+	now := time.Now()
+
+	// Never updated: no data
+	if _, ok := estimateETA(Progress{ID: "fresh"}, now); ok {
+		t.Error("Expected no ETA for a job with no samples")
+	}
+
+	// Complete: nothing remaining
+	done := Progress{ID: "done", Percent: 1.0, Rate: 0.1, StartedAt: now}
+	if _, ok := estimateETA(done, now); ok {
+		t.Error("Expected no ETA for a completed job")
+	}
+
+	// Stalled: samples arrived but the percent never moved
+	stalled := Progress{ID: "stalled", Percent: 0.5, StartedAt: now}
+	applyProgressSample(&stalled, 0.5, now.Add(time.Second))
+	if _, ok := estimateETA(stalled, now.Add(2*time.Second)); ok {
+		t.Error("Expected no ETA for a stalled job")
+	}
+}
+
+// Test the EMA smooths rate spikes rather than tracking them directly
+func TestProgressRateSmoothing(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := Progress{ID: "smooth"}
+
+	applyProgressSample(&p, 0.1, start.Add(1*time.Second))
+	applyProgressSample(&p, 0.2, start.Add(2*time.Second))
+	steadyRate := p.Rate
+
+	// A single 5x spike should move the EMA but nowhere near 5x
+	applyProgressSample(&p, 0.7, start.Add(3*time.Second))
+	if p.Rate <= steadyRate {
+		t.Error("Expected rate to increase after a fast sample")
+	}
+	if p.Rate > steadyRate*3 {
+		t.Errorf("EMA tracked the spike too closely: %f -> %f", steadyRate, p.Rate)
+	}
+}
+
+// Test cancelling a job mid-flight
+func TestCancelJobMidFlight(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	started := make(chan struct{})
+	exited := make(chan struct{})
+
+	m.RegisterJob("cancel-me", "Cancellable", func(ctx context.Context, report func(float64)) error {
+		close(started)
+		defer close(exited)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		percent := 0.0
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				percent += 0.01
+				report(percent)
+			}
+		}
+	})
+
+	<-started
+	m.cancelJob("cancel-me")
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Job goroutine did not exit after cancellation")
+	}
+
+	// Drain any queued reports; none may resurrect the cancelled row
+	for {
+		select {
+		case msg := <-progressReports:
+			m, _ = m.updateProgress(msg)
+			continue
+		default:
+		}
+		break
+	}
+
+	for _, p := range m.progress {
+		if p.ID == "cancel-me" {
+			if !p.Cancelled {
+				t.Error("Expected job to be marked cancelled")
+			}
+			if p.IsActive {
+				t.Error("Expected cancelled job to be inactive")
+			}
+			return
+		}
+	}
+	t.Fatal("Cancelled job row not found")
+}
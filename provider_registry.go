@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ==================== UNIFIED PROVIDER INTERFACE ====================
+
+// AIResponse is a provider-agnostic chat completion result.
+type AIResponse struct {
+	Content  string     `json:"content"`
+	Model    string     `json:"model"`
+	Provider string     `json:"provider"`
+	Usage    UsageStats `json:"usage"`
+}
+
+// AIProvider is the contract every chat backend implements, so callers
+// can dispatch to whichever provider an agent is configured for.
+type AIProvider interface {
+	SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error)
+	SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error)
+	Models(ctx context.Context) ([]string, error)
+	Name() string
+}
+
+// ==================== ADAPTERS ====================
+
+// openRouterAIProvider adapts OpenRouterClient to AIProvider.
+type openRouterAIProvider struct {
+	client *OpenRouterClient
+}
+
+// NewOpenRouterAIProvider wraps a client behind the unified interface.
+func NewOpenRouterAIProvider(client *OpenRouterClient) AIProvider {
+	return &openRouterAIProvider{client: client}
+}
+
+func (p *openRouterAIProvider) Name() string { return "openrouter" }
+
+func (p *openRouterAIProvider) SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error) {
+	resp, err := p.client.sendMessageFull(ctx, model, messages)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	return AIResponse{
+		Content:  resp.Choices[0].Message.Content,
+		Model:    model,
+		Provider: p.Name(),
+		Usage: UsageStats{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+			Requests:         1,
+		},
+	}, nil
+}
+
+// SendMessageStream falls back to a single completion; OpenRouter
+// streaming is not wired yet, so the reply arrives as one token.
+func (p *openRouterAIProvider) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	resp, err := p.SendMessage(ctx, model, messages)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	onToken(resp.Content)
+	return resp, nil
+}
+
+func (p *openRouterAIProvider) Models(ctx context.Context) ([]string, error) {
+	return p.client.GetModels(ctx)
+}
+
+// ollamaAIProvider adapts OllamaClient to AIProvider.
+type ollamaAIProvider struct {
+	client *OllamaClient
+}
+
+// NewOllamaAIProvider wraps a client behind the unified interface.
+func NewOllamaAIProvider(client *OllamaClient) AIProvider {
+	return &ollamaAIProvider{client: client}
+}
+
+func (p *ollamaAIProvider) Name() string { return "ollama" }
+
+func (p *ollamaAIProvider) SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error) {
+	content, err := p.client.SendMessage(ctx, model, messages)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	return AIResponse{Content: content, Model: model, Provider: p.Name()}, nil
+}
+
+func (p *ollamaAIProvider) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	content, err := p.client.SendMessageStream(ctx, model, messages, onToken)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	return AIResponse{Content: content, Model: model, Provider: p.Name()}, nil
+}
+
+func (p *ollamaAIProvider) Models(ctx context.Context) ([]string, error) {
+	return p.client.GetModels(ctx)
+}
+
+// ==================== FALLBACK PROVIDER ====================
+
+// FallbackProvider tries an ordered chain of providers, returning the
+// first success. The response's Provider field names which one served.
+type FallbackProvider struct {
+	name  string
+	chain []AIProvider
+}
+
+// NewFallbackProvider builds a chain tried in the given order.
+func NewFallbackProvider(name string, chain ...AIProvider) (*FallbackProvider, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("fallback chain needs at least one provider")
+	}
+	return &FallbackProvider{name: name, chain: chain}, nil
+}
+
+func (fp *FallbackProvider) Name() string { return fp.name }
+
+// SendMessage tries each provider in order until one succeeds.
+func (fp *FallbackProvider) SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error) {
+	var lastErr error
+	for _, provider := range fp.chain {
+		if ctx.Err() != nil {
+			return AIResponse{}, ctx.Err()
+		}
+		resp, err := provider.SendMessage(ctx, model, messages)
+		if err == nil {
+			resp.Provider = provider.Name()
+			return resp, nil
+		}
+		lastErr = err
+	}
+	return AIResponse{}, fmt.Errorf("all providers failed: %w", lastErr)
+}
+
+// SendMessageStream tries each provider in order, but only falls over
+// before the first token has been delivered — once a provider has started
+// streaming, its failure is surfaced rather than replayed elsewhere.
+func (fp *FallbackProvider) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	var lastErr error
+	for _, provider := range fp.chain {
+		if ctx.Err() != nil {
+			return AIResponse{}, ctx.Err()
+		}
+		started := false
+		resp, err := provider.SendMessageStream(ctx, model, messages, func(token string) {
+			started = true
+			onToken(token)
+		})
+		if err == nil {
+			resp.Provider = provider.Name()
+			return resp, nil
+		}
+		if started {
+			return AIResponse{}, err
+		}
+		lastErr = err
+	}
+	return AIResponse{}, fmt.Errorf("all providers failed: %w", lastErr)
+}
+
+// Models lists models from the first provider that answers.
+func (fp *FallbackProvider) Models(ctx context.Context) ([]string, error) {
+	var lastErr error
+	for _, provider := range fp.chain {
+		models, err := provider.Models(ctx)
+		if err == nil {
+			return models, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// ==================== PROVIDER REGISTRY ====================
+
+// ProviderRegistry resolves AIProviders by ID so dispatch code does not
+// hardcode a backend.
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]AIProvider
+}
+
+// NewProviderRegistry returns an empty registry.
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{providers: make(map[string]AIProvider)}
+}
+
+// Register adds a provider under an ID, rejecting duplicates.
+func (pr *ProviderRegistry) Register(id string, provider AIProvider) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if id == "" || provider == nil {
+		return fmt.Errorf("provider registration requires an ID and a provider")
+	}
+	if _, exists := pr.providers[id]; exists {
+		return fmt.Errorf("provider %s already registered", id)
+	}
+	pr.providers[id] = provider
+	return nil
+}
+
+// Get resolves a provider by ID.
+func (pr *ProviderRegistry) Get(id string) (AIProvider, bool) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	p, ok := pr.providers[id]
+	return p, ok
+}
+
+// IDs lists registered provider IDs.
+func (pr *ProviderRegistry) IDs() []string {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	ids := make([]string, 0, len(pr.providers))
+	for id := range pr.providers {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// SendMessageFor dispatches a message to the provider an agent's config
+// names, using the agent's configured model.
+func (pr *ProviderRegistry) SendMessageFor(ctx context.Context, cfg AgentConfig, messages []ChatMessage) (AIResponse, error) {
+	provider, ok := pr.Get(cfg.ProviderID)
+	if !ok {
+		return AIResponse{}, fmt.Errorf("agent %s references unknown provider %s", cfg.ID, cfg.ProviderID)
+	}
+	return provider.SendMessage(ctx, cfg.Model, messages)
+}
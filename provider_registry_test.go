@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeAIProvider answers with a canned prefix so tests can tell which
+// provider served a request.
+type fakeAIProvider struct {
+	name string
+	err  error
+}
+
+func (p *fakeAIProvider) Name() string { return p.name }
+
+func (p *fakeAIProvider) SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error) {
+	if p.err != nil {
+		return AIResponse{}, p.err
+	}
+	content := ""
+	if len(messages) > 0 {
+		content = messages[len(messages)-1].Content
+	}
+	return AIResponse{
+		Content:  fmt.Sprintf("%s answered: %s", p.name, content),
+		Model:    model,
+		Provider: p.name,
+	}, nil
+}
+
+func (p *fakeAIProvider) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	resp, err := p.SendMessage(ctx, model, messages)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	onToken(resp.Content)
+	return resp, nil
+}
+
+func (p *fakeAIProvider) Models(ctx context.Context) ([]string, error) {
+	return []string{p.name + "-model"}, nil
+}
+
+func TestRegistryRoutesByAgentConfig(t *testing.T) {
+	registry := NewProviderRegistry()
+	if err := registry.Register("openrouter", &fakeAIProvider{name: "openrouter"}); err != nil {
+		t.Fatalf("Register openrouter: %v", err)
+	}
+	if err := registry.Register("ollama", &fakeAIProvider{name: "ollama"}); err != nil {
+		t.Fatalf("Register ollama: %v", err)
+	}
+
+	ctx := context.Background()
+	msg := []ChatMessage{{Role: "user", Content: "ping"}}
+
+	cloud := AgentConfig{ID: "cloud-agent", ProviderID: "openrouter", Model: "gpt"}
+	resp, err := registry.SendMessageFor(ctx, cloud, msg)
+	if err != nil {
+		t.Fatalf("SendMessageFor cloud: %v", err)
+	}
+	if resp.Provider != "openrouter" || resp.Content != "openrouter answered: ping" {
+		t.Errorf("cloud response = %+v", resp)
+	}
+
+	local := AgentConfig{ID: "local-agent", ProviderID: "ollama", Model: "llama3"}
+	resp, err = registry.SendMessageFor(ctx, local, msg)
+	if err != nil {
+		t.Fatalf("SendMessageFor local: %v", err)
+	}
+	if resp.Provider != "ollama" {
+		t.Errorf("local response came from %q, want ollama", resp.Provider)
+	}
+
+	if _, err := registry.SendMessageFor(ctx, AgentConfig{ID: "x", ProviderID: "missing"}, msg); err == nil {
+		t.Error("expected unknown provider to error")
+	}
+}
+
+func TestFallbackUsesSecondaryOnPrimaryError(t *testing.T) {
+	primary := &fakeAIProvider{name: "primary", err: fmt.Errorf("upstream down")}
+	secondary := &fakeAIProvider{name: "secondary"}
+	fp, err := NewFallbackProvider("chain", primary, secondary)
+	if err != nil {
+		t.Fatalf("NewFallbackProvider: %v", err)
+	}
+
+	resp, err := fp.SendMessage(context.Background(), "m", []ChatMessage{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if resp.Provider != "secondary" {
+		t.Errorf("response served by %q, want secondary", resp.Provider)
+	}
+}
+
+func TestFallbackAllFail(t *testing.T) {
+	fp, err := NewFallbackProvider("chain",
+		&fakeAIProvider{name: "a", err: fmt.Errorf("a down")},
+		&fakeAIProvider{name: "b", err: fmt.Errorf("b down")},
+	)
+	if err != nil {
+		t.Fatalf("NewFallbackProvider: %v", err)
+	}
+	if _, err := fp.SendMessage(context.Background(), "m", nil); err == nil {
+		t.Fatal("expected error when every provider fails")
+	}
+}
+
+// streamingFailure emits tokens then errors, to exercise the no-fallback-
+// after-first-token rule.
+type streamingFailure struct {
+	fakeAIProvider
+}
+
+func (p *streamingFailure) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	onToken("partial")
+	return AIResponse{}, fmt.Errorf("stream broke mid-way")
+}
+
+func TestFallbackStreamOnlyBeforeFirstToken(t *testing.T) {
+	broken := &streamingFailure{fakeAIProvider{name: "broken"}}
+	secondary := &fakeAIProvider{name: "secondary"}
+	fp, err := NewFallbackProvider("chain", broken, secondary)
+	if err != nil {
+		t.Fatalf("NewFallbackProvider: %v", err)
+	}
+
+	var tokens []string
+	_, err = fp.SendMessageStream(context.Background(), "m", nil, func(tok string) {
+		tokens = append(tokens, tok)
+	})
+	if err == nil {
+		t.Fatal("expected mid-stream failure to surface, not fall back")
+	}
+	if len(tokens) != 1 || tokens[0] != "partial" {
+		t.Errorf("tokens = %v, want just the partial output", tokens)
+	}
+
+	// A provider failing before any token must fall over cleanly.
+	fp, err = NewFallbackProvider("chain",
+		&fakeAIProvider{name: "dead", err: fmt.Errorf("down")},
+		secondary,
+	)
+	if err != nil {
+		t.Fatalf("NewFallbackProvider: %v", err)
+	}
+	tokens = nil
+	resp, err := fp.SendMessageStream(context.Background(), "m", []ChatMessage{{Role: "user", Content: "hi"}}, func(tok string) {
+		tokens = append(tokens, tok)
+	})
+	if err != nil {
+		t.Fatalf("SendMessageStream: %v", err)
+	}
+	if resp.Provider != "secondary" || len(tokens) != 1 {
+		t.Errorf("resp = %+v tokens = %v, want secondary to stream", resp, tokens)
+	}
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	registry := NewProviderRegistry()
+	if err := registry.Register("a", &fakeAIProvider{name: "a"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Register("a", &fakeAIProvider{name: "other"}); err == nil {
+		t.Error("expected duplicate ID to be rejected")
+	}
+	if err := registry.Register("", &fakeAIProvider{name: "anon"}); err == nil {
+		t.Error("expected empty ID to be rejected")
+	}
+	if got := len(registry.IDs()); got != 1 {
+		t.Errorf("registry has %d providers, want 1", got)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== RESPONSE CACHE ====================
+
+// ResponseCache memoizes chat completions for identical prompts so
+// repeated development runs don't burn tokens. Entries expire after a
+// TTL and the cache evicts least-recently-used entries past its cap.
+type ResponseCache struct {
+	mu             sync.Mutex
+	entries        map[string]*list.Element
+	order          *list.List
+	ttl            time.Duration
+	maxEntries     int
+	maxTemperature float64
+	hits           int64
+	misses         int64
+}
+
+// cacheEntry is one memoized response with its storage time.
+type cacheEntry struct {
+	key      string
+	response AIResponse
+	storedAt time.Time
+}
+
+// NewResponseCache caps the cache at maxEntries with the given TTL.
+// Responses generated above temperature 0.3 are considered
+// non-deterministic and are never cached.
+func NewResponseCache(maxEntries int, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		entries:        make(map[string]*list.Element),
+		order:          list.New(),
+		ttl:            ttl,
+		maxEntries:     maxEntries,
+		maxTemperature: 0.3,
+	}
+}
+
+// SetMaxTemperature adjusts the determinism cutoff.
+func (rc *ResponseCache) SetMaxTemperature(t float64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.maxTemperature = t
+}
+
+// cacheKey normalizes the prompt into a stable lookup key.
+func cacheKey(model string, messages []ChatMessage, temperature float64) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%.3f\x00", model, temperature)
+	for _, m := range messages {
+		fmt.Fprintf(h, "%s\x1f%s\x1e", m.Role, strings.TrimSpace(m.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheable reports whether a request is eligible for caching at all.
+func (rc *ResponseCache) cacheable(temperature float64, stream bool) bool {
+	return !stream && temperature <= rc.maxTemperature
+}
+
+// Get returns a fresh cached response for the prompt, if any. Streaming
+// requests and high-temperature prompts always miss.
+func (rc *ResponseCache) Get(model string, messages []ChatMessage, temperature float64, stream bool) (AIResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if !rc.cacheable(temperature, stream) {
+		rc.misses++
+		return AIResponse{}, false
+	}
+	elem, ok := rc.entries[cacheKey(model, messages, temperature)]
+	if !ok {
+		rc.misses++
+		return AIResponse{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Since(entry.storedAt) > rc.ttl {
+		rc.order.Remove(elem)
+		delete(rc.entries, entry.key)
+		rc.misses++
+		return AIResponse{}, false
+	}
+	rc.order.MoveToFront(elem)
+	rc.hits++
+	return entry.response, true
+}
+
+// Put stores a response for the prompt, evicting the least recently used
+// entry when over capacity.
+func (rc *ResponseCache) Put(model string, messages []ChatMessage, temperature float64, stream bool, response AIResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if !rc.cacheable(temperature, stream) {
+		return
+	}
+	key := cacheKey(model, messages, temperature)
+	if elem, ok := rc.entries[key]; ok {
+		elem.Value.(*cacheEntry).response = response
+		elem.Value.(*cacheEntry).storedAt = time.Now()
+		rc.order.MoveToFront(elem)
+		return
+	}
+	elem := rc.order.PushFront(&cacheEntry{key: key, response: response, storedAt: time.Now()})
+	rc.entries[key] = elem
+	for rc.order.Len() > rc.maxEntries {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Stats returns lifetime hit and miss counts.
+func (rc *ResponseCache) Stats() (hits, misses int64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.hits, rc.misses
+}
+
+// Len returns the number of live entries.
+func (rc *ResponseCache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.order.Len()
+}
+
+// ==================== CACHING PROVIDER ====================
+
+// CachedProvider wraps an AIProvider with a response cache. Streaming
+// calls bypass the cache entirely.
+type CachedProvider struct {
+	inner       AIProvider
+	cache       *ResponseCache
+	temperature float64
+}
+
+// NewCachedProvider caches the inner provider's completions.
+func NewCachedProvider(inner AIProvider, cache *ResponseCache) *CachedProvider {
+	return &CachedProvider{inner: inner, cache: cache}
+}
+
+// SetTemperature records the temperature used for cache keying.
+func (cp *CachedProvider) SetTemperature(t float64) {
+	cp.temperature = t
+}
+
+func (cp *CachedProvider) Name() string { return cp.inner.Name() }
+
+func (cp *CachedProvider) SendMessage(ctx context.Context, model string, messages []ChatMessage) (AIResponse, error) {
+	if resp, ok := cp.cache.Get(model, messages, cp.temperature, false); ok {
+		return resp, nil
+	}
+	resp, err := cp.inner.SendMessage(ctx, model, messages)
+	if err != nil {
+		return AIResponse{}, err
+	}
+	cp.cache.Put(model, messages, cp.temperature, false, resp)
+	return resp, nil
+}
+
+func (cp *CachedProvider) SendMessageStream(ctx context.Context, model string, messages []ChatMessage, onToken func(string)) (AIResponse, error) {
+	return cp.inner.SendMessageStream(ctx, model, messages, onToken)
+}
+
+func (cp *CachedProvider) Models(ctx context.Context) ([]string, error) {
+	return cp.inner.Models(ctx)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheHitOnIdenticalPrompt(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+	messages := []ChatMessage{{Role: "user", Content: "what is 2+2"}}
+
+	if _, ok := cache.Get("m", messages, 0, false); ok {
+		t.Fatal("expected first lookup to miss")
+	}
+	cache.Put("m", messages, 0, false, AIResponse{Content: "4"})
+	resp, ok := cache.Get("m", messages, 0, false)
+	if !ok || resp.Content != "4" {
+		t.Fatalf("expected cache hit, got ok=%v resp=%+v", ok, resp)
+	}
+
+	hits, misses := cache.Stats()
+	if hits != 1 || misses != 1 {
+		t.Errorf("stats = %d hits / %d misses, want 1/1", hits, misses)
+	}
+}
+
+func TestCacheMissOnDifferentTemperature(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+	messages := []ChatMessage{{Role: "user", Content: "hi"}}
+
+	cache.Put("m", messages, 0.1, false, AIResponse{Content: "a"})
+	if _, ok := cache.Get("m", messages, 0.2, false); ok {
+		t.Error("different temperature should not hit")
+	}
+}
+
+func TestCacheSkipsStreamAndHighTemperature(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+	messages := []ChatMessage{{Role: "user", Content: "hi"}}
+
+	cache.Put("m", messages, 0, true, AIResponse{Content: "streamed"})
+	if _, ok := cache.Get("m", messages, 0, false); ok {
+		t.Error("streaming responses must not be cached")
+	}
+	cache.Put("m", messages, 0.9, false, AIResponse{Content: "creative"})
+	if _, ok := cache.Get("m", messages, 0.9, false); ok {
+		t.Error("high-temperature responses must not be cached")
+	}
+}
+
+func TestCacheTTLExpiry(t *testing.T) {
+	cache := NewResponseCache(10, 20*time.Millisecond)
+	messages := []ChatMessage{{Role: "user", Content: "hi"}}
+
+	cache.Put("m", messages, 0, false, AIResponse{Content: "fresh"})
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := cache.Get("m", messages, 0, false); ok {
+		t.Error("expired entry should miss")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expired entry not evicted, len = %d", cache.Len())
+	}
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	cache := NewResponseCache(2, time.Minute)
+	a := []ChatMessage{{Role: "user", Content: "a"}}
+	b := []ChatMessage{{Role: "user", Content: "b"}}
+	c := []ChatMessage{{Role: "user", Content: "c"}}
+
+	cache.Put("m", a, 0, false, AIResponse{Content: "A"})
+	cache.Put("m", b, 0, false, AIResponse{Content: "B"})
+	// Touch a so b becomes least recently used.
+	if _, ok := cache.Get("m", a, 0, false); !ok {
+		t.Fatal("expected hit on a")
+	}
+	cache.Put("m", c, 0, false, AIResponse{Content: "C"})
+
+	if _, ok := cache.Get("m", b, 0, false); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := cache.Get("m", a, 0, false); !ok {
+		t.Error("a should have survived eviction")
+	}
+}
+
+func TestCachedProviderShortCircuits(t *testing.T) {
+	inner := &fakeAIProvider{name: "inner"}
+	provider := NewCachedProvider(inner, NewResponseCache(10, time.Minute))
+
+	ctx := context.Background()
+	messages := []ChatMessage{{Role: "user", Content: "ping"}}
+	first, err := provider.SendMessage(ctx, "m", messages)
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	// Break the inner provider; the cached reply must still be served.
+	inner.err = context.DeadlineExceeded
+	second, err := provider.SendMessage(ctx, "m", messages)
+	if err != nil {
+		t.Fatalf("cached SendMessage: %v", err)
+	}
+	if second.Content != first.Content {
+		t.Errorf("cached reply = %q, want %q", second.Content, first.Content)
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bubbletea "github.com/charmbracelet/bubbletea"
+)
+
+// Test secret create/edit/delete round-trip against a temp HOME
+func TestSecretLifecycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	if len(m.secrets) != 0 {
+		t.Fatalf("Expected empty secret store, got %d secrets", len(m.secrets))
+	}
+
+	// Create
+	m.editingSecret = &Secret{ID: "test-secret-1", CreatedAt: time.Now()}
+	m.newSecretName = "API_KEY"
+	m.newSecretValue = "original-value"
+	m.saveSecret()
+
+	if len(m.secrets) != 1 {
+		t.Fatalf("Expected 1 secret after create, got %d", len(m.secrets))
+	}
+	if m.secrets[0].Value != "original-value" {
+		t.Errorf("Expected value 'original-value', got %s", m.secrets[0].Value)
+	}
+
+	// Edit
+	m.updateSecret("test-secret-1")
+	if m.editingSecret == nil {
+		t.Fatal("Expected updateSecret to enter editing state")
+	}
+	if m.newSecretValue != "original-value" {
+		t.Errorf("Expected form prefilled with 'original-value', got %s", m.newSecretValue)
+	}
+	m.newSecretValue = "edited-value"
+	m.saveSecret()
+
+	if len(m.secrets) != 1 {
+		t.Fatalf("Expected edit to update in place, got %d secrets", len(m.secrets))
+	}
+	if m.secrets[0].Value != "edited-value" {
+		t.Errorf("Expected value 'edited-value', got %s", m.secrets[0].Value)
+	}
+
+	// Verify persistence round-trips through disk
+	loaded, err := loadSecrets()
+	if err != nil {
+		t.Fatalf("Failed to load secrets: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Value != "edited-value" {
+		t.Errorf("Persisted secrets don't match model state: %+v", loaded)
+	}
+
+	// Delete
+	m.deleteSecret("test-secret-1")
+	if len(m.secrets) != 0 {
+		t.Fatalf("Expected 0 secrets after delete, got %d", len(m.secrets))
+	}
+
+	loaded, err = loadSecrets()
+	if err != nil {
+		t.Fatalf("Failed to load secrets after delete: %v", err)
+	}
+	if len(loaded) != 0 {
+		t.Errorf("Expected empty store after delete, got %d secrets", len(loaded))
+	}
+}
+
+// Test that deleting one secret never drops the others
+func TestDeleteSecretKeepsOthers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	for i, name := range []string{"first", "second", "third"} {
+		m.editingSecret = &Secret{ID: name, CreatedAt: time.Now()}
+		m.newSecretName = name
+		m.newSecretValue = "value"
+		m.saveSecret()
+		m.selectedSecret = i
+	}
+
+	m.deleteSecret("second")
+
+	if len(m.secrets) != 2 {
+		t.Fatalf("Expected 2 secrets after delete, got %d", len(m.secrets))
+	}
+	if m.secrets[0].ID != "first" || m.secrets[1].ID != "third" {
+		t.Errorf("Wrong secrets survived delete: %+v", m.secrets)
+	}
+	if m.selectedSecret >= len(m.secrets) {
+		t.Errorf("Selection cursor out of range after delete: %d", m.selectedSecret)
+	}
+}
+
+// Test filterSecrets query and tag behavior
+func TestFilterSecrets(t *testing.T) {
+	secrets := []Secret{
+		{ID: "1", Name: "GitHub Token", Tags: []string{"api", "github"}},
+		{ID: "2", Name: "AWS Key", Tags: []string{"api", "cloud"}},
+		{ID: "3", Name: "Database Password", Tags: []string{"db"}},
+	}
+
+	// Case-insensitive substring on name
+	got := filterSecrets(secrets, "github", "")
+	if len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("Expected GitHub Token only, got %+v", got)
+	}
+
+	// Match against tags too
+	got = filterSecrets(secrets, "cloud", "")
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("Expected AWS Key only, got %+v", got)
+	}
+
+	// Tag filter narrows the set
+	got = filterSecrets(secrets, "", "api")
+	if len(got) != 2 {
+		t.Errorf("Expected 2 api-tagged secrets, got %d", len(got))
+	}
+
+	// Combined query and tag
+	got = filterSecrets(secrets, "aws", "api")
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("Expected AWS Key for combined filter, got %+v", got)
+	}
+
+	// Empty query restores the full list and never mutates the input
+	got = filterSecrets(secrets, "", "")
+	if len(got) != 3 {
+		t.Errorf("Expected full list with empty filters, got %d", len(got))
+	}
+	if len(secrets) != 3 {
+		t.Errorf("Input slice was mutated")
+	}
+}
+
+// Test the relative expiry parser
+func TestParseRelativeExpiry(t *testing.T) {
+	now := time.Now()
+
+	got, err := parseRelativeExpiry("+30d")
+	if err != nil {
+		t.Fatalf("Failed to parse +30d: %v", err)
+	}
+	want := now.Add(30 * 24 * time.Hour)
+	if got.Sub(want) > time.Minute || want.Sub(got) > time.Minute {
+		t.Errorf("+30d parsed to %v, want about %v", got, want)
+	}
+
+	got, err = parseRelativeExpiry("+2w")
+	if err != nil {
+		t.Fatalf("Failed to parse +2w: %v", err)
+	}
+	want = now.Add(14 * 24 * time.Hour)
+	if got.Sub(want) > time.Minute || want.Sub(got) > time.Minute {
+		t.Errorf("+2w parsed to %v, want about %v", got, want)
+	}
+
+	for _, invalid := range []string{"", "30d", "+d", "+0d", "+-5d", "+30x", "+abc"} {
+		if _, err := parseRelativeExpiry(invalid); err == nil {
+			t.Errorf("Expected error for %q, got none", invalid)
+		}
+	}
+}
+
+// Test the derived expiry status
+func TestSecretStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	soon := now.Add(24 * time.Hour)
+	far := now.Add(60 * 24 * time.Hour)
+
+	if got := secretStatus(Secret{}, now); got != secretStatusValid {
+		t.Errorf("No expiry should be valid, got %s", got)
+	}
+	if got := secretStatus(Secret{ExpiresAt: &past}, now); got != secretStatusExpired {
+		t.Errorf("Past expiry should be expired, got %s", got)
+	}
+	if got := secretStatus(Secret{ExpiresAt: &soon}, now); got != secretStatusExpiring {
+		t.Errorf("Near expiry should be expiring, got %s", got)
+	}
+	if got := secretStatus(Secret{ExpiresAt: &far}, now); got != secretStatusValid {
+		t.Errorf("Far expiry should be valid, got %s", got)
+	}
+}
+
+// Test the random secret generator
+func TestGenerateSecret(t *testing.T) {
+	got := generateSecret(32, CharsetAlphanumeric)
+	if len(got) != 32 {
+		t.Fatalf("Expected length 32, got %d", len(got))
+	}
+	for _, c := range got {
+		if !strings.ContainsRune(CharsetAlphanumeric.chars(), c) {
+			t.Errorf("Character %q not in alphanumeric charset", c)
+		}
+	}
+
+	hex := generateSecret(16, CharsetHex)
+	if len(hex) != 16 {
+		t.Fatalf("Expected length 16, got %d", len(hex))
+	}
+	for _, c := range hex {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			t.Errorf("Character %q not in hex charset", c)
+		}
+	}
+
+	// Two calls must not collide
+	if generateSecret(32, CharsetAlphanumeric) == generateSecret(32, CharsetAlphanumeric) {
+		t.Error("Two generated secrets were identical")
+	}
+}
+
+// Test export/import round-trips for both formats
+func TestExportImportRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	secrets := []Secret{
+		{ID: "1", Name: "SIMPLE", Value: "plain-value", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "2", Name: "TRICKY", Value: "has space\nand newline", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	// JSON round-trip
+	jsonPath := filepath.Join(tempDir, "export.json")
+	if err := exportSecrets(secrets, jsonPath, ExportJSON); err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+	got, err := importSecrets(jsonPath)
+	if err != nil {
+		t.Fatalf("JSON import failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Value != "plain-value" || got[1].Value != "has space\nand newline" {
+		t.Errorf("JSON round-trip mismatch: %+v", got)
+	}
+
+	// Dotenv round-trip
+	envPath := filepath.Join(tempDir, "export.env")
+	if err := exportSecrets(secrets, envPath, ExportDotenv); err != nil {
+		t.Fatalf("Dotenv export failed: %v", err)
+	}
+	got, err = importSecrets(envPath)
+	if err != nil {
+		t.Fatalf("Dotenv import failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 secrets from dotenv, got %d", len(got))
+	}
+	if got[0].Name != "SIMPLE" || got[0].Value != "plain-value" {
+		t.Errorf("Dotenv simple value mismatch: %+v", got[0])
+	}
+	if got[1].Name != "TRICKY" || got[1].Value != "has space\nand newline" {
+		t.Errorf("Dotenv escaped value mismatch: %q", got[1].Value)
+	}
+}
+
+// Test that import merges by name rather than duplicating
+func TestMergeSecrets(t *testing.T) {
+	existing := []Secret{
+		{ID: "1", Name: "KEEP", Value: "old"},
+		{ID: "2", Name: "UPDATE", Value: "old"},
+	}
+	imported := []Secret{
+		{ID: "x", Name: "UPDATE", Value: "new"},
+		{ID: "y", Name: "ADD", Value: "fresh"},
+	}
+
+	merged, added, updated := mergeSecrets(existing, imported)
+
+	if added != 1 || updated != 1 {
+		t.Errorf("Expected 1 added and 1 updated, got %d/%d", added, updated)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 merged secrets, got %d", len(merged))
+	}
+	for _, secret := range merged {
+		switch secret.Name {
+		case "KEEP":
+			if secret.Value != "old" {
+				t.Errorf("KEEP was modified: %s", secret.Value)
+			}
+		case "UPDATE":
+			if secret.Value != "new" {
+				t.Errorf("UPDATE was not updated: %s", secret.Value)
+			}
+		case "ADD":
+			if secret.Value != "fresh" {
+				t.Errorf("ADD has wrong value: %s", secret.Value)
+			}
+		}
+	}
+}
+
+// Test that the audit log never contains a secret value
+func TestAuditLogNeverContainsValues(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := initialModel()
+	m.editingSecret = &Secret{ID: "audit-1", CreatedAt: time.Now()}
+	m.newSecretName = "AUDITED"
+	m.newSecretValue = "super-sensitive-value"
+	m.saveSecret()
+
+	m.auditSecret("revealed", m.secrets[0])
+	m.auditSecret("copied", m.secrets[0])
+	m.deleteSecret("audit-1")
+
+	data, err := os.ReadFile(filepath.Join(home, ".go-tui-secrets-audit.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(data), "super-sensitive-value") {
+		t.Error("Audit log contains a secret value")
+	}
+	if !strings.Contains(string(data), "AUDITED") {
+		t.Error("Audit log is missing the secret name")
+	}
+
+	// Should cover edit, reveal, copy and delete events
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Errorf("Expected 4 audit events, got %d", len(lines))
+	}
+}
+
+// Test that quitting mid-edit prompts instead of quitting
+func TestConfirmQuitWithUnsavedEdit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := initialModel()
+	m.showSecrets = true
+	m.editingSecret = &Secret{ID: "draft", CreatedAt: time.Now()}
+	m.newSecretName = "DRAFT"
+	m.newSecretValue = "partial"
+
+	// Quit with a dirty edit should prompt, not quit
+	m, cmd := m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyCtrlC})
+	if cmd != nil {
+		t.Error("Expected no quit command while edit is unsaved")
+	}
+	if !m.confirmingQuit {
+		t.Error("Expected confirmingQuit to be set")
+	}
+
+	// 'n' cancels and returns to editing
+	m, cmd = m.handleKey(bubbletea.KeyMsg{Type: 'n'})
+	if cmd != nil {
+		t.Error("Expected no command after cancelling quit")
+	}
+	if m.confirmingQuit {
+		t.Error("Expected confirmingQuit to be cleared")
+	}
+	if m.editingSecret == nil {
+		t.Error("Expected editing state to be preserved")
+	}
+
+	// Quitting from a clean state goes straight through
+	m.editingSecret = nil
+	_, cmd = m.handleKey(bubbletea.KeyMsg{Type: bubbletea.KeyCtrlC})
+	if cmd == nil {
+		t.Error("Expected quit command from clean state")
+	}
+}
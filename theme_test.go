@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that every theme renders non-empty output
+func TestAllThemesRender(t *testing.T) {
+	for _, theme := range animationThemes {
+		animator := NewAnimator(theme)
+		if animator == nil {
+			t.Fatalf("NewAnimator(%q) returned nil", theme)
+		}
+
+		output := animator.Render()
+		if len(output) == 0 {
+			t.Errorf("Theme %q rendered empty output", theme)
+		}
+	}
+}
+
+// Test that Update is a no-op for paused theme animators
+func TestThemesPausedUpdate(t *testing.T) {
+	for _, theme := range animationThemes {
+		animator := NewAnimator(theme)
+		animator.SetPaused(true)
+		if !animator.IsPaused() {
+			t.Errorf("Theme %q did not report paused", theme)
+		}
+
+		before := animator.Render()
+		for i := 0; i < 10; i++ {
+			if err := animator.Update(1.0); err != nil {
+				t.Errorf("Theme %q paused update returned error: %v", theme, err)
+			}
+		}
+		after := animator.Render()
+
+		if before != after {
+			t.Errorf("Theme %q changed state while paused", theme)
+		}
+	}
+}
+
+// Test that unknown themes fall back to underwater
+func TestUnknownThemeFallsBack(t *testing.T) {
+	animator := NewAnimator("nonexistent")
+	if _, ok := animator.(*UnderwaterAnimator); !ok {
+		t.Errorf("Expected UnderwaterAnimator fallback, got %T", animator)
+	}
+}
+
+// Test that a partial config is filled in with defaults
+func TestAnimatorConfigWithDefaults(t *testing.T) {
+	cfg := AnimatorConfig{ParticleCount: 10}.withDefaults()
+
+	if cfg.ParticleCount != 10 {
+		t.Errorf("Explicit particle count overridden: %d", cfg.ParticleCount)
+	}
+	if cfg.StarCount != 100 {
+		t.Errorf("Expected default star count 100, got %d", cfg.StarCount)
+	}
+	if cfg.FishCount != 5 {
+		t.Errorf("Expected default fish count 5, got %d", cfg.FishCount)
+	}
+	if cfg.BaseSpeed != 1.0 {
+		t.Errorf("Expected default base speed 1.0, got %f", cfg.BaseSpeed)
+	}
+	if len(cfg.Planets) == 0 {
+		t.Error("Expected default planets to be filled in")
+	}
+
+	animator := NewUnderwaterAnimatorFromConfig(cfg)
+	if len(animator.particles) != 10 {
+		t.Errorf("Expected 10 particles, got %d", len(animator.particles))
+	}
+	if len(animator.stars) != 100 {
+		t.Errorf("Expected 100 stars, got %d", len(animator.stars))
+	}
+}
+
+// Test config validation and loading from disk
+func TestLoadAnimatorConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Missing file falls back to defaults without error
+	cfg, err := loadAnimatorConfig()
+	if err != nil {
+		t.Fatalf("Missing config should not error: %v", err)
+	}
+	if cfg.ParticleCount != 50 {
+		t.Errorf("Expected default particle count, got %d", cfg.ParticleCount)
+	}
+
+	// Partial file merges with defaults
+	path := filepath.Join(home, ".ai-tui-anim.json")
+	os.WriteFile(path, []byte(`{"fish_count": 12}`), 0644)
+	cfg, err = loadAnimatorConfig()
+	if err != nil {
+		t.Fatalf("Partial config should load: %v", err)
+	}
+	if cfg.FishCount != 12 || cfg.StarCount != 100 {
+		t.Errorf("Partial config not merged with defaults: %+v", cfg)
+	}
+
+	// Negative counts are rejected
+	os.WriteFile(path, []byte(`{"star_count": -1}`), 0644)
+	if _, err := loadAnimatorConfig(); err == nil {
+		t.Error("Expected error for negative star count")
+	}
+
+	// Malformed JSON is rejected
+	os.WriteFile(path, []byte(`{not json`), 0644)
+	if _, err := loadAnimatorConfig(); err == nil {
+		t.Error("Expected error for malformed config")
+	}
+}
+
+// Test that a fixed seed reproduces the animation exactly
+func TestSeededAnimatorIsDeterministic(t *testing.T) {
+	a := NewUnderwaterAnimatorWithSeed(42)
+	b := NewUnderwaterAnimatorWithSeed(42)
+
+	for i := 0; i < 50; i++ {
+		if err := a.Update(1.0); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if err := b.Update(1.0); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	if len(a.particles) != len(b.particles) {
+		t.Fatalf("Particle counts diverged: %d vs %d", len(a.particles), len(b.particles))
+	}
+	for i := range a.particles {
+		if a.particles[i] != b.particles[i] {
+			t.Errorf("Particle %d diverged: %+v vs %+v", i, a.particles[i], b.particles[i])
+		}
+	}
+
+	// Different seeds should (overwhelmingly) differ
+	c := NewUnderwaterAnimatorWithSeed(7)
+	same := true
+	for i := range a.particles {
+		if a.particles[i] != c.particles[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Error("Different seeds produced identical particles")
+	}
+}
+
+// Test that sustained slow frames lengthen the tick interval
+func TestAdaptiveTickerSlowsDown(t *testing.T) {
+	ticker := NewAdaptiveTicker(60)
+	base := ticker.Interval()
+
+	// Feed frames that each cost twice the 60 FPS budget
+	for i := 0; i < 5; i++ {
+		ticker.RecordFrame(2 * time.Second / 60)
+	}
+	if ticker.Interval() <= base {
+		t.Errorf("Interval did not grow under load: %v <= %v", ticker.Interval(), base)
+	}
+
+	// Effective FPS must stay at or above the 10 FPS floor
+	for i := 0; i < 5; i++ {
+		ticker.RecordFrame(time.Second) // pathological 1s frames
+	}
+	if fps := ticker.EffectiveFPS(); fps < 10-1e-9 {
+		t.Errorf("Effective FPS fell below floor: %.2f", fps)
+	}
+
+	// Fast frames should recover toward the target rate
+	for i := 0; i < 50; i++ {
+		ticker.RecordFrame(time.Millisecond)
+	}
+	if fps := ticker.EffectiveFPS(); fps < 59 {
+		t.Errorf("Effective FPS did not recover: %.2f", fps)
+	}
+}
+
+// Test that recorded output is a valid asciicast v2 stream
+func TestRecorderWritesAsciicast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "demo.cast")
+	rec := NewRecorder(80, 24)
+
+	if err := rec.StartRecording(path); err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := rec.WriteFrame("frame\n"); err != nil {
+			t.Fatalf("WriteFrame failed: %v", err)
+		}
+	}
+	if err := rec.StopRecording(); err != nil {
+		t.Fatalf("StopRecording failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cast file: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header + 3 frames, got %d lines", len(lines))
+	}
+
+	var header struct {
+		Version int `json:"version"`
+		Width   int `json:"width"`
+		Height  int `json:"height"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("Header is not valid JSON: %v", err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Errorf("Bad header: %+v", header)
+	}
+
+	var prev float64 = -1
+	for _, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Frame line is not valid JSON: %v", err)
+		}
+		if len(event) != 3 {
+			t.Fatalf("Frame line has %d elements, want 3", len(event))
+		}
+		ts, ok := event[0].(float64)
+		if !ok || ts < prev {
+			t.Errorf("Frame timestamps not monotonic: %v after %v", event[0], prev)
+		}
+		prev = ts
+		if event[1] != "o" {
+			t.Errorf("Frame event type = %v, want \"o\"", event[1])
+		}
+		if event[2] != "frame\n" {
+			t.Errorf("Frame data = %v", event[2])
+		}
+	}
+}
+
+// Test that overlapping fish separate while schooling stays loose
+func TestFishSeparation(t *testing.T) {
+	ua := NewUnderwaterAnimatorWithSeed(1)
+	ua.fish = []Fish{
+		{X: 50, Y: 15, Angle: 0, Speed: 0},
+		{X: 50, Y: 15, Angle: 0, Speed: 0},
+	}
+
+	if err := ua.Update(1.0); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	dx := ua.fish[0].X - ua.fish[1].X
+	dy := ua.fish[0].Y - ua.fish[1].Y
+	if dx == 0 && dy == 0 {
+		t.Error("Overlapping fish did not separate after an update")
+	}
+
+	// Paused animators must not move fish at all
+	ua.fish[0] = Fish{X: 50, Y: 15}
+	ua.fish[1] = Fish{X: 50, Y: 15}
+	ua.SetPaused(true)
+	if err := ua.Update(1.0); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if ua.fish[0].X != 50 || ua.fish[1].X != 50 {
+		t.Error("Paused animator moved fish")
+	}
+}
+
+// Test that emitted bursts appear, are capped, and age out
+func TestEmitParticleBurst(t *testing.T) {
+	ua := NewUnderwaterAnimatorWithSeed(3)
+	base := len(ua.particles)
+
+	ua.Emit(10, 5, 12)
+	if len(ua.particles) != base+12 {
+		t.Errorf("Emit added %d particles, want 12", len(ua.particles)-base)
+	}
+
+	// Burst particles must be pruned once their lifetime is exceeded
+	for i := base; i < len(ua.particles); i++ {
+		ua.particles[i].Lifetime = ua.particles[i].MaxLifetime + 1
+	}
+	if err := ua.Update(0); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if len(ua.particles) != base {
+		t.Errorf("Expired burst not pruned: %d particles, want %d", len(ua.particles), base)
+	}
+
+	// Total particle count is capped
+	ua.Emit(0, 0, maxParticles*2)
+	if len(ua.particles) > maxParticles {
+		t.Errorf("Particle count %d exceeds cap %d", len(ua.particles), maxParticles)
+	}
+}
+
+// Test that sprites are composited at their real coordinates
+func TestCompositePlacesSpritesByPosition(t *testing.T) {
+	ua := NewUnderwaterAnimatorWithSeed(5)
+	ua.particles = []Particle{{X: 10, Y: 5, Color: "#FF6B6B"}}
+	ua.stars = nil
+	ua.planets = nil
+	ua.fish = nil
+	ua.octopus = nil
+
+	cells := ua.compositeSprites()
+	if got := cells[5][10].glyph; got == "" || got == " " {
+		t.Errorf("Expected a glyph at (10,5), got %q", got)
+	}
+	for y := range cells {
+		for x := range cells[y] {
+			if y == 5 && x == 10 {
+				continue
+			}
+			if cells[y][x].glyph != "" {
+				t.Errorf("Unexpected glyph %q at (%d,%d)", cells[y][x].glyph, x, y)
+			}
+		}
+	}
+
+	// Out-of-bounds sprites are clipped, not wrapped or panicking
+	ua.particles = []Particle{{X: -3, Y: 500, Color: "#FF6B6B"}}
+	cells = ua.compositeSprites()
+	for y := range cells {
+		for x := range cells[y] {
+			if cells[y][x].glyph != "" {
+				t.Errorf("Out-of-bounds sprite leaked into (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+// Test that pausing one layer leaves the others animating
+func TestPerLayerPause(t *testing.T) {
+	ua := NewUnderwaterAnimatorWithSeed(9)
+	ua.SetLayerPaused(LayerFish, true)
+	if !ua.IsLayerPaused(LayerFish) {
+		t.Fatal("Fish layer did not report paused")
+	}
+
+	fishX := make([]float64, len(ua.fish))
+	for i, f := range ua.fish {
+		fishX[i] = f.X
+	}
+	particleX := ua.particles[0].X
+	particleY := ua.particles[0].Y
+
+	if err := ua.Update(1.0); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for i, f := range ua.fish {
+		if f.X != fishX[i] {
+			t.Errorf("Fish %d moved while its layer was paused", i)
+		}
+	}
+	if ua.particles[0].X == particleX && ua.particles[0].Y == particleY {
+		t.Error("Particles froze even though only the fish layer was paused")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+// ==================== SHARED DOMAIN TYPES ====================
+
+// Pane is a rectangular UI region used by every top-level layout. It is
+// the union of the per-layout variants that used to live in each main
+// file: the collaborative TUI resizes panes (IsResizable) while the AI
+// TUI fades them over its animated background (Opacity); layouts simply
+// ignore the fields they do not use.
+type Pane struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Content     string  `json:"content"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	X           int     `json:"x"`
+	Y           int     `json:"y"`
+	IsActive    bool    `json:"is_active"`
+	IsResizable bool    `json:"is_resizable"`
+	Opacity     float64 `json:"opacity"`
+}
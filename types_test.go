@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+// TestUnifiedPaneServesAllLayouts is a smoke test that the single Pane
+// type carries every field its consumers rely on, including the ones
+// that used to live only in one layout's local definition.
+func TestUnifiedPaneServesAllLayouts(t *testing.T) {
+	pane := Pane{
+		ID:          "main",
+		Title:       "Main",
+		Content:     "hello",
+		Width:       40,
+		Height:      10,
+		X:           0,
+		Y:           0,
+		IsActive:    true,
+		IsResizable: true,
+		Opacity:     0.8,
+	}
+	if !pane.IsResizable {
+		t.Error("collaborative TUI needs IsResizable")
+	}
+	if pane.Opacity != 0.8 {
+		t.Error("AI TUI needs Opacity")
+	}
+}
+
+// TestSharedTypesConstructible exercises the other consolidated domain
+// types alongside their managers.
+func TestSharedTypesConstructible(t *testing.T) {
+	fm, err := NewFileManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileManager: %v", err)
+	}
+	if fm == nil {
+		t.Fatal("NewFileManager returned nil")
+	}
+
+	cm := NewConversationManager()
+	if err := cm.CreateConversation("smoke", "debate", []string{"a", "b"}); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	state, cerr := cm.GetConversation("smoke")
+	if cerr != nil {
+		t.Fatalf("GetConversation: %v", cerr)
+	}
+	if state.Type != ConvDebate {
+		t.Errorf("conversation type = %q", state.Type)
+	}
+}
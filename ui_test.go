@@ -8,9 +8,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Test initialModel creation
+// Test initialAITUIModel creation
 func TestInitialModel(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	if model.animator == nil {
 		t.Error("Model should have an animator")
@@ -55,7 +55,7 @@ func TestInitialModel(t *testing.T) {
 
 // Test model initialization
 func TestModelInit(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 	cmd := model.Init()
 
 	if cmd == nil {
@@ -65,26 +65,19 @@ func TestModelInit(t *testing.T) {
 
 // Test model update with key messages
 func TestModelUpdateKeyMsg(t *testing.T) {
-	model := initialModel()
+	t.Setenv("HOME", t.TempDir())
+	model := initialAITUIModel()
 
 	// Test Tab key
 	tabMsg := tea.KeyMsg{Type: tea.KeyTab, Runes: []rune{'\t'}}
-	updatedModel, cmd := model.Update(tabMsg)
+	updatedModel, _ := model.Update(tabMsg)
 
 	if updatedModel == nil {
 		t.Error("Update should return a model")
 	}
 
-	if cmd == nil {
-		t.Error("Update should return a command")
-	}
-
 	// Check that active pane changed
-	modelPtr, ok := updatedModel.(*Model)
-	if !ok {
-		t.Fatal("Failed to type assert updated model")
-	}
-	if modelPtr.activePane == model.activePane {
+	if asAITUIModel(t, updatedModel).activePane == model.activePane {
 		t.Error("Tab key should change active pane")
 	}
 
@@ -92,11 +85,7 @@ func TestModelUpdateKeyMsg(t *testing.T) {
 	sMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
 	updatedModel, _ = model.Update(sMsg)
 
-	modelPtr, ok = updatedModel.(*Model)
-	if !ok {
-		t.Fatal("Failed to type assert updated model")
-	}
-	if modelPtr.isRecording == model.isRecording {
+	if asAITUIModel(t, updatedModel).isRecording == model.isRecording {
 		t.Error("'s' key should toggle recording state")
 	}
 
@@ -151,7 +140,7 @@ func TestModelUpdateKeyMsg(t *testing.T) {
 
 	// Test Ctrl+C
 	ctrlCMsg := tea.KeyMsg{Type: tea.KeyCtrlC}
-	updatedModel, cmd = model.Update(ctrlCMsg)
+	updatedModel, cmd := model.Update(ctrlCMsg)
 
 	if updatedModel == nil {
 		t.Error("Ctrl+C update should return a model")
@@ -164,7 +153,7 @@ func TestModelUpdateKeyMsg(t *testing.T) {
 
 // Test model update with time messages (animation ticks)
 func TestModelUpdateTimeMsg(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test time message for animation
 	timeMsg := time.Now()
@@ -181,7 +170,7 @@ func TestModelUpdateTimeMsg(t *testing.T) {
 
 // Test model update with window size messages
 func TestModelUpdateWindowSizeMsg(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test window size message
 	sizeMsg := tea.WindowSizeMsg{
@@ -189,26 +178,21 @@ func TestModelUpdateWindowSizeMsg(t *testing.T) {
 		Height: 50,
 	}
 
-	updatedModel, cmd := model.Update(sizeMsg)
+	updatedModel, _ := model.Update(sizeMsg)
 
 	if updatedModel == nil {
 		t.Error("Window size update should return a model")
 	}
 
-	if cmd == nil {
-		t.Error("Window size update should return a command")
-	}
-
-	// Check that animator was updated
-	updatedAnimator := updatedModel.(*Model).animator
-	if updatedAnimator == nil {
+	// Check that animator survived the resize
+	if asAITUIModel(t, updatedModel).animator == nil {
 		t.Error("Animator should still exist after window size update")
 	}
 }
 
 // Test model update with mouse messages
 func TestModelUpdateMouseMsg(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test mouse click
 	mouseMsg := tea.MouseMsg{
@@ -217,20 +201,16 @@ func TestModelUpdateMouseMsg(t *testing.T) {
 		Y:    5,
 	}
 
-	updatedModel, cmd := model.Update(mouseMsg)
+	updatedModel, _ := model.Update(mouseMsg)
 
 	if updatedModel == nil {
 		t.Error("Mouse update should return a model")
 	}
-
-	if cmd == nil {
-		t.Error("Mouse update should return a command")
-	}
 }
 
 // Test model view rendering
 func TestModelView(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	view := model.View()
 
@@ -238,36 +218,33 @@ func TestModelView(t *testing.T) {
 		t.Error("View should not return empty string")
 	}
 
-	// Should contain pane titles
+	// Only the active pane is drawn over the animation, so walk the
+	// panes with Tab and check each title as it becomes active.
 	if !strings.Contains(view, "AI Workspace") {
 		t.Error("View should contain main pane title")
 	}
 
-	if !strings.Contains(view, "Conversation") {
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model = asAITUIModel(t, updated)
+	if !strings.Contains(model.View(), "Conversation") {
 		t.Error("View should contain conversation pane title")
 	}
 
-	if !strings.Contains(view, "Monitor") {
-		t.Error("View should contain monitoring pane title")
-	}
-
-	if !strings.Contains(view, "Conversation") {
-		t.Error("View should contain conversation pane title")
-	}
-
-	if !strings.Contains(view, "Monitor") {
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model = asAITUIModel(t, updated)
+	if !strings.Contains(model.View(), "Monitor") {
 		t.Error("View should contain monitoring pane title")
 	}
 }
 
 // Test pane switching
 func TestPaneSwitching(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test switching to next pane
 	initialPane := model.activePane
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
 	if model.activePane == initialPane {
 		t.Error("Tab should switch to next pane")
@@ -276,7 +253,7 @@ func TestPaneSwitching(t *testing.T) {
 	// Test switching through all panes
 	for i := 0; i < len(model.panes)*2; i++ {
 		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
-		model = updatedModel.(*Model)
+		model = asAITUIModel(t, updatedModel)
 
 		if model.activePane < 0 || model.activePane >= len(model.panes) {
 			t.Errorf("Active pane %d is out of bounds", model.activePane)
@@ -286,7 +263,8 @@ func TestPaneSwitching(t *testing.T) {
 
 // Test recording functionality
 func TestRecordingFunctionality(t *testing.T) {
-	model := initialModel()
+	t.Setenv("HOME", t.TempDir())
+	model := initialAITUIModel()
 
 	// Test starting recording
 	if model.isRecording {
@@ -294,7 +272,7 @@ func TestRecordingFunctionality(t *testing.T) {
 	}
 
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
 	if !model.isRecording {
 		t.Error("Should be recording after toggle")
@@ -302,7 +280,7 @@ func TestRecordingFunctionality(t *testing.T) {
 
 	// Test stopping recording
 	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
 	if model.isRecording {
 		t.Error("Should not be recording after second toggle")
@@ -311,34 +289,40 @@ func TestRecordingFunctionality(t *testing.T) {
 
 // Test conversation clearing
 func TestClearConversation(t *testing.T) {
-	model := initialModel()
+	t.Setenv("HOME", t.TempDir())
+	model := initialAITUIModel()
 
 	// Start recording first
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
-	// Add some content to conversation
-	model.panes[1].Content = "Test conversation content"
+	if model.currentSession == nil {
+		t.Fatal("Recording should open a session")
+	}
 
 	// Clear conversation
 	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
 	// Check that conversation was cleared
-	expectedContent := "Conversation cleared.\n\nPress 's' to start a new recording session."
-	if model.panes[1].Content != expectedContent {
-		t.Errorf("Expected cleared conversation content, got: %s", model.panes[1].Content)
+	if model.currentSession != nil {
+		t.Error("Clearing should drop the current session")
+	}
+
+	if model.isRecording {
+		t.Error("Clearing should stop recording")
 	}
 }
 
 // Test monitoring display
 func TestMonitoringDisplay(t *testing.T) {
-	model := initialModel()
+	t.Setenv("HOME", t.TempDir())
+	model := initialAITUIModel()
 
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
-	// Check that monitoring pane was updated
+	// Check that monitoring pane still has content
 	monitoringContent := model.panes[2].Content
 
 	if len(monitoringContent) == 0 {
@@ -353,7 +337,7 @@ func TestMonitoringDisplay(t *testing.T) {
 
 // Test conversation formatting
 func TestConversationFormatting(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test with empty conversation
 	formatted := model.formatConversationDisplay()
@@ -363,14 +347,14 @@ func TestConversationFormatting(t *testing.T) {
 	}
 
 	// Should contain default message
-	if !strings.Contains(formatted, "No conversation") {
+	if !strings.Contains(formatted, "No active conversation") {
 		t.Error("Should contain no conversation message")
 	}
 }
 
 // Test monitoring formatting
 func TestMonitoringFormatting(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	formatted := model.formatMonitoringDisplay()
 
@@ -386,13 +370,13 @@ func TestMonitoringFormatting(t *testing.T) {
 
 // Test animation speed control
 func TestAnimationSpeedControl(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	initialSpeed := model.getAnimationSpeed()
 
 	// Test speed increase
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 	newSpeed := model.getAnimationSpeed()
 
 	if newSpeed <= initialSpeed {
@@ -401,7 +385,7 @@ func TestAnimationSpeedControl(t *testing.T) {
 
 	// Test speed decrease
 	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 	decreasedSpeed := model.getAnimationSpeed()
 
 	if decreasedSpeed >= newSpeed {
@@ -411,15 +395,17 @@ func TestAnimationSpeedControl(t *testing.T) {
 
 // Test animation reset
 func TestAnimationReset(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Change animation state
-	model.animator.SetSpeed(2.0)
+	if animator, ok := model.animator.(*UnderwaterAnimator); ok {
+		animator.SetSpeed(2.0)
+	}
 	model.animator.SetPaused(true)
 
 	// Reset animation
 	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
-	model = updatedModel.(*Model)
+	model = asAITUIModel(t, updatedModel)
 
 	// Check that animation was reset
 	if model.getAnimationSpeed() != 1.0 {
@@ -433,7 +419,7 @@ func TestAnimationReset(t *testing.T) {
 
 // Test edge cases
 func TestEdgeCases(t *testing.T) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	// Test unknown key
 	unknownMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
@@ -471,7 +457,7 @@ func TestEdgeCases(t *testing.T) {
 
 // Benchmark model update
 func BenchmarkModelUpdate(b *testing.B) {
-	model := initialModel()
+	model := initialAITUIModel()
 	timeMsg := time.Now()
 
 	b.ResetTimer()
@@ -482,7 +468,7 @@ func BenchmarkModelUpdate(b *testing.B) {
 
 // Benchmark model view
 func BenchmarkModelView(b *testing.B) {
-	model := initialModel()
+	model := initialAITUIModel()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {